@@ -1,7 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
@@ -13,6 +20,8 @@ import (
 	"trello-backend/internal/config"
 	"trello-backend/internal/middlewares"
 	"trello-backend/internal/routes"
+	"trello-backend/internal/services"
+	"trello-backend/internal/storage"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
@@ -45,6 +54,27 @@ func initDB(cfg *config.Config) *gorm.DB {
 	return db
 }
 
+func initBlobStore(cfg *config.Config) storage.BlobStore {
+	if cfg.StorageDriver == "s3" {
+		store, err := storage.NewS3BlobStore(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		}, nil)
+		if err != nil {
+			log.Fatal("無法初始化 S3 儲存:", err)
+		}
+		return store
+	}
+	store, err := storage.NewLocalBlobStore(cfg.StorageLocalDir)
+	if err != nil {
+		log.Fatal("無法初始化本機儲存:", err)
+	}
+	return store
+}
+
 func setupSwagger() {
 	docs.SwaggerInfo.Title = "Trello 後端 API"
 	docs.SwaggerInfo.Description = "Trello 後端 API 文件"
@@ -56,17 +86,45 @@ func setupSwagger() {
 
 func main() {
 	cfg := config.LoadConfig()
+	// 保留天數小於 1 時，清除工作會在下一次執行時永久刪除垃圾桶中的所有項目
+	if cfg.TrashRetentionDays < 1 {
+		log.Fatalf("TRASH_RETENTION_DAYS 必須至少為 1，目前為 %d", cfg.TrashRetentionDays)
+	}
 	db := initDB(cfg)
 
 	// 初始化 Swagger 文件
 	setupSwagger()
 
 	// 使用 wire 進行相依性注入
-	api, err := app.InitializeAPI(db, cfg.JWTSecret)
+	maxUploadSize := cfg.AttachmentMaxSizeMB << 20
+	api, err := app.InitializeAPI(db, cfg.JWTSecret, initBlobStore(cfg), services.AttachmentOptions{
+		MaxSize:   maxUploadSize,
+		URLSecret: cfg.GetAttachmentURLSecret(),
+		URLTTL:    15 * time.Minute,
+	})
 	if err != nil {
 		log.Fatal("無法初始化 API:", err)
 	}
 
+	// 收到 SIGINT/SIGTERM 時取消，背景工作與 HTTP 伺服器一起結束
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	var workers sync.WaitGroup
+
+	// 背景定期永久刪除超過保留期限的垃圾桶項目
+	trashRetention := time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		services.RunTrashPurger(ctx, api.TrashService(), trashRetention, time.Hour)
+	}()
+	// 背景定期依重複規則建立週期性卡片
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		services.RunRecurrenceScheduler(ctx, api.RecurrenceService(), time.Minute)
+	}()
+
 	// 設定路由
 	engine := gin.Default()
 
@@ -74,9 +132,11 @@ func main() {
 	gqlSrv := handler.New(graph.NewExecutableSchema(graph.Config{
 		Resolvers: graph.NewResolverFromAPI(api),
 	}))
+	gqlSrv.SetErrorPresenter(graph.ErrorPresenter)
 	gqlSrv.AddTransport(transport.Options{})
 	gqlSrv.AddTransport(transport.GET{})
 	gqlSrv.AddTransport(transport.POST{})
+	gqlSrv.AddTransport(transport.MultipartForm{MaxUploadSize: maxUploadSize, MaxMemory: 32 << 20})
 	gqlSrv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 	gqlSrv.Use(extension.Introspection{})
 	gqlSrv.Use(extension.AutomaticPersistedQuery{Cache: lru.New[string](100)})
@@ -86,7 +146,7 @@ func main() {
 	// GraphQL 查詢路由
 	engine.POST("/api/graphql/query", middlewares.AuthMiddleware(cfg.JWTSecret), func(c *gin.Context) {
 		ctx := c.Request.Context()
-		ctx = graph.DataloaderMiddleware(api.CardService())(ctx)
+		ctx = graph.DataloaderMiddleware(api.BoardService(), api.CardService(), api.AttachmentService(), api.CustomFieldService(), api.CardRelationService(), api.WatchService(), api.TimeTrackingService(), api.RecurrenceService(), api.VoteService(), api.UserBoardService())(ctx)
 		c.Request = c.Request.WithContext(ctx)
 		gqlSrv.ServeHTTP(c.Writer, c.Request)
 	})
@@ -102,7 +162,21 @@ func main() {
 	router.SetupRoutes()
 
 	// 啟動伺服器
-	if err := engine.Run(":8080"); err != nil {
-		log.Fatal("伺服器啟動失敗:", err)
+	srv := &http.Server{Addr: ":8080", Handler: engine}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("伺服器啟動失敗:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Println("正在關閉伺服器...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("伺服器關閉失敗: %v", err)
 	}
+	// 等待背景工作完成目前的交易後再結束
+	workers.Wait()
 }
@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"trello-backend/graph/model"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/graph-gophers/dataloader"
+)
+
+// Attachment 相關 resolver function
+
+func (r *mutationResolver) UploadAttachment(ctx context.Context, cardID string, file graphql.Upload) (*model.Attachment, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	cid, err := strconv.ParseUint(cardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	a, err := r.AttachmentService.UploadAttachment(ctx, userID, uint(cid), file.Filename, file.Size, file.File)
+	if err != nil {
+		return nil, err
+	}
+	return toModelAttachment(a, r.AttachmentService), nil
+}
+
+func (r *mutationResolver) DeleteAttachment(ctx context.Context, id string) (bool, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return false, errors.New("未驗證身份")
+	}
+	aid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	err = r.AttachmentService.DeleteAttachment(ctx, userID, uint(aid))
+	return err == nil, err
+}
+
+// Attachments is the resolver for the attachments field.
+func (r *cardResolver) Attachments(ctx context.Context, obj *model.Card) ([]*model.Attachment, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return nil, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.AttachmentsByCardID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return nil, err
+	}
+	attachments, ok := result.([]*model.Attachment)
+	if !ok {
+		return nil, errors.New("unexpected dataloader result type")
+	}
+	return attachments, nil
+}
+
+func (r *mutationResolver) SetCardCover(ctx context.Context, input model.SetCardCoverInput) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	cid, err := strconv.ParseUint(input.CardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	var attachmentID *uint
+	if input.AttachmentID != nil {
+		aid, err := strconv.ParseUint(*input.AttachmentID, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		id := uint(aid)
+		attachmentID = &id
+	}
+	c, err := r.AttachmentService.SetCardCover(userID, uint(cid), attachmentID, ptrToStr(input.Color))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCard(c), nil
+}
+
+func (r *mutationResolver) RemoveCardCover(ctx context.Context, cardID string) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	cid, err := strconv.ParseUint(cardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.AttachmentService.SetCardCover(userID, uint(cid), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return toModelCard(c), nil
+}
+
+// Attachment is the resolver for the attachment field.
+func (r *cardCoverResolver) Attachment(ctx context.Context, obj *model.CardCover) (*model.Attachment, error) {
+	if obj.AttachmentID == nil {
+		return nil, nil
+	}
+	// 透過卡片附件的 dataloader 取得，避免每張卡片各查一次
+	attachments, err := r.Card().Attachments(ctx, &model.Card{ID: obj.CardID})
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attachments {
+		if a.ID == *obj.AttachmentID {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"strconv"
 	"trello-backend/graph/model"
-	"trello-backend/pkg/utils"
+	"trello-backend/internal/models"
+	"trello-backend/internal/services"
+
+	"github.com/graph-gophers/dataloader"
 )
 
 // Board 相關 resolver function
@@ -19,25 +22,36 @@ func (r *mutationResolver) CreateBoard(ctx context.Context, input model.CreateBo
 	if input.Position != nil {
 		position = *input.Position
 	}
-	b, err := r.BoardService.CreateBoard(input.Name, userID, int(position))
+	key := ""
+	if input.Key != nil {
+		key = *input.Key
+	}
+	b, err := r.BoardService.CreateBoard(input.Name, key, userID, int(position))
 	if err != nil {
 		return nil, err
 	}
-	return &model.Board{
-		ID:        strconv.FormatUint(uint64(b.ID), 10),
-		Name:      b.Name,
-		Position:  int32(b.Position),
-		CreatedAt: b.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: b.UpdatedAt.Format(utils.TimeFormat),
-	}, nil
+	return toModelBoard(b), nil
 }
 
 func (r *mutationResolver) UpdateBoard(ctx context.Context, input model.UpdateBoardInput) (*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
 	id, err := strconv.ParseUint(input.ID, 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	err = r.BoardService.UpdateBoard(uint(id), input.Name)
+	key := ""
+	if input.Key != nil {
+		key = *input.Key
+	}
+	var settings *services.BoardSettingsPatch
+	if input.Settings != nil {
+		patch := toBoardSettingsPatch(input.Settings)
+		settings = &patch
+	}
+	err = r.BoardService.UpdateBoard(userID, uint(id), ptrToStr(input.Name), key, settings)
 	if err != nil {
 		return nil, err
 	}
@@ -45,20 +59,36 @@ func (r *mutationResolver) UpdateBoard(ctx context.Context, input model.UpdateBo
 	if err != nil {
 		return nil, err
 	}
-	return &model.Board{
-		ID:        strconv.FormatUint(uint64(b.ID), 10),
-		Name:      b.Name,
-		CreatedAt: b.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: b.UpdatedAt.Format(utils.TimeFormat),
-	}, nil
+	return toModelBoard(b), nil
+}
+
+// UpdateBoardSettings 變更看板的外觀與功能設定，未指定的欄位不變更
+func (r *mutationResolver) UpdateBoardSettings(ctx context.Context, boardID string, input model.BoardSettingsInput) (*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	id, err := strconv.ParseUint(boardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	b, err := r.BoardService.UpdateBoardSettings(userID, uint(id), toBoardSettingsPatch(&input))
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoard(b), nil
 }
 
 func (r *mutationResolver) DeleteBoard(ctx context.Context, id string) (bool, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return false, errors.New("未驗證身份")
+	}
 	bid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
 		return false, err
 	}
-	err = r.BoardService.DeleteBoard(uint(bid))
+	err = r.BoardService.DeleteBoard(userID, uint(bid))
 	return err == nil, err
 }
 
@@ -87,13 +117,7 @@ func (r *mutationResolver) MoveBoard(ctx context.Context, input model.MoveBoardI
 		if err != nil {
 			return nil, err
 		}
-		return &model.Board{
-			ID:        strconv.FormatUint(uint64(b.ID), 10),
-			Name:      b.Name,
-			Position:  int32(b.Position),
-			CreatedAt: b.CreatedAt.Format(utils.TimeFormat),
-			UpdatedAt: b.UpdatedAt.Format(utils.TimeFormat),
-		}, nil
+		return toModelBoard(b), nil
 	}
 	// 更新其他 board 的 position
 	for i := range boards {
@@ -125,16 +149,43 @@ func (r *mutationResolver) MoveBoard(ctx context.Context, input model.MoveBoardI
 	if err != nil {
 		return nil, err
 	}
-	return &model.Board{
-		ID:        strconv.FormatUint(uint64(b.ID), 10),
-		Name:      b.Name,
-		Position:  int32(b.Position),
-		CreatedAt: b.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: b.UpdatedAt.Format(utils.TimeFormat),
-	}, nil
+	return toModelBoard(b), nil
 }
 
-func (r *queryResolver) Boards(ctx context.Context) ([]*model.Board, error) {
+func (r *mutationResolver) ArchiveBoard(ctx context.Context, id string) (*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	b, err := r.BoardService.ArchiveBoard(userID, uint(bid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoard(b), nil
+}
+
+func (r *mutationResolver) UnarchiveBoard(ctx context.Context, id string) (*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	b, err := r.BoardService.UnarchiveBoard(userID, uint(bid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoard(b), nil
+}
+
+// Boards 取得使用者未封存的看板，預設不含已關閉的看板
+func (r *queryResolver) Boards(ctx context.Context, includeClosed *bool) ([]*model.Board, error) {
 	userID, ok := UserIDFromContext(ctx)
 	if !ok {
 		return nil, errors.New("未驗證身份")
@@ -143,34 +194,133 @@ func (r *queryResolver) Boards(ctx context.Context) ([]*model.Board, error) {
 	if err != nil {
 		return nil, err
 	}
-	result := make([]*model.Board, 0, len(boards))
-	for _, b := range boards {
-		result = append(result, &model.Board{
-			ID:        strconv.FormatUint(uint64(b.ID), 10),
-			Name:      b.Name,
-			Position:  int32(b.Position),
-			CreatedAt: b.CreatedAt.Format(utils.TimeFormat),
-			UpdatedAt: b.UpdatedAt.Format(utils.TimeFormat),
-		})
+	if includeClosed != nil && *includeClosed {
+		closed, err := r.BoardService.GetClosedBoards(userID)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, closed...)
 	}
-	return result, nil
+	return toModelBoards(boards), nil
+}
+
+// ClosedBoards 取得使用者已關閉的看板
+func (r *queryResolver) ClosedBoards(ctx context.Context) ([]*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	boards, err := r.BoardService.GetClosedBoards(userID)
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoards(boards), nil
 }
 
 func (r *queryResolver) Board(ctx context.Context, id string) (*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
 	boardID, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	b, err := r.BoardService.GetBoard(uint(boardID))
+	b, err := r.BoardService.GetReadableBoard(userID, uint(boardID))
 	if err != nil {
 		return nil, err
 	}
-	return &model.Board{
-		ID:        strconv.FormatUint(uint64(b.ID), 10),
-		Name:      b.Name,
-		Position:  int32(b.Position),
-		CreatedAt: b.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: b.UpdatedAt.Format(utils.TimeFormat),
+	r.recordBoardView(ctx, b.ID)
+	return toModelBoard(b), nil
+}
+
+func (r *mutationResolver) CloseBoard(ctx context.Context, id string) (*model.Board, error) {
+	return r.changeBoardClosed(ctx, id, r.BoardService.CloseBoard)
+}
+
+func (r *mutationResolver) ReopenBoard(ctx context.Context, id string) (*model.Board, error) {
+	return r.changeBoardClosed(ctx, id, r.BoardService.ReopenBoard)
+}
+
+func (r *mutationResolver) changeBoardClosed(ctx context.Context, id string, change func(string, uint) (*models.Board, error)) (*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	b, err := change(userID, uint(bid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoard(b), nil
+}
+
+func (r *mutationResolver) SetBoardTemplate(ctx context.Context, id string, isTemplate bool) (*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	b, err := r.BoardService.SetTemplate(userID, uint(bid), isTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoard(b), nil
+}
+
+// BoardTemplates 取得範本庫中的看板
+func (r *queryResolver) BoardTemplates(ctx context.Context) ([]*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	boards, err := r.BoardService.GetTemplates(userID)
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoards(boards), nil
+}
+
+// ArchivedBoards 取得使用者已封存的看板
+func (r *queryResolver) ArchivedBoards(ctx context.Context) ([]*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	boards, err := r.BoardService.GetArchivedBoards(userID)
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoards(boards), nil
+}
+
+// ArchivedItems 取得看板中已封存的清單與卡片
+func (r *queryResolver) ArchivedItems(ctx context.Context, boardID string) (*model.ArchivedItems, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(boardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := r.ListService.GetArchivedLists(userID, uint(bid))
+	if err != nil {
+		return nil, err
+	}
+	cards, err := r.CardService.GetArchivedCards(userID, uint(bid))
+	if err != nil {
+		return nil, err
+	}
+	return &model.ArchivedItems{
+		Lists: toModelLists(lists),
+		Cards: toModelCards(cards),
 	}, nil
 }
 
@@ -184,16 +334,23 @@ func (r *boardResolver) Lists(ctx context.Context, obj *model.Board) ([]*model.L
 	if err != nil {
 		return nil, err
 	}
-	result := make([]*model.List, 0, len(lists))
-	for _, l := range lists {
-		result = append(result, &model.List{
-			ID:        strconv.FormatUint(uint64(l.ID), 10),
-			Name:      l.Name,
-			BoardID:   strconv.FormatUint(uint64(l.BoardID), 10),
-			CreatedAt: l.CreatedAt.Format(utils.TimeFormat),
-			UpdatedAt: l.UpdatedAt.Format(utils.TimeFormat),
-			Position:  int32(l.Position),
-		})
+	return toModelLists(lists), nil
+}
+
+// EstimateTotal 加總看板中未封存清單的估計工作量
+func (r *boardResolver) EstimateTotal(ctx context.Context, obj *model.Board) (float64, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return 0, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.EstimateTotalByBoardID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return 0, err
+	}
+	total, ok := result.(float64)
+	if !ok {
+		return 0, errors.New("unexpected dataloader result type")
 	}
-	return result, nil
+	return total, nil
 }
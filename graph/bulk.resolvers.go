@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"trello-backend/graph/model"
+	"trello-backend/internal/services"
+)
+
+// 卡片批次操作相關 resolver function
+
+func (r *mutationResolver) MoveCards(ctx context.Context, input model.MoveCardsInput) ([]*model.BulkCardResult, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	targetListID, err := strconv.ParseUint(input.TargetListID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	cardIDs := make([]uint, len(input.CardIds))
+	for i, id := range input.CardIds {
+		cid, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		cardIDs[i] = uint(cid)
+	}
+	results, err := r.CardService.MoveCards(userID, cardIDs, uint(targetListID))
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if result.Err != nil || result.Card.ListID == result.FromListID {
+			continue
+		}
+		card, fromListID := result.Card, result.FromListID
+		notifyWatchers(ctx, func(actorID string) error {
+			return r.WatchService.NotifyCardMoved(actorID, card, fromListID)
+		})
+	}
+	return toModelBulkCardResults(results), nil
+}
+
+func (r *mutationResolver) ArchiveListCards(ctx context.Context, listID string) ([]*model.BulkCardResult, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	lid, err := strconv.ParseUint(listID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.CardService.ArchiveListCards(userID, uint(lid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelBulkCardResults(results), nil
+}
+
+func toModelBulkCardResults(results []services.BulkCardResult) []*model.BulkCardResult {
+	out := make([]*model.BulkCardResult, 0, len(results))
+	for _, result := range results {
+		item := &model.BulkCardResult{
+			CardID:  strconv.FormatUint(uint64(result.CardID), 10),
+			Success: result.Err == nil,
+		}
+		if result.Err != nil {
+			msg := result.Err.Error()
+			item.Error = &msg
+		} else {
+			item.Card = toModelCard(result.Card)
+		}
+		out = append(out, item)
+	}
+	return out
+}
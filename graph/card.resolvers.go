@@ -2,39 +2,50 @@ package graph
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 	"trello-backend/graph/model"
-	"trello-backend/pkg/utils"
+	"trello-backend/internal/models"
+	"trello-backend/internal/services"
+
+	"github.com/graph-gophers/dataloader"
 )
 
 // Card 相關 resolver function
 
+// 未指定 withinHours 時，dueSoonCards 預設查詢的時間範圍
+const defaultDueSoonHours = 24
+
+// dueSoonCards 可查詢的最大時間範圍（90 天）
+const maxDueSoonHours = 90 * 24
+
 func (r *mutationResolver) CreateCard(ctx context.Context, input model.CreateCardInput) (*model.Card, error) {
 	lid, err := strconv.ParseUint(input.ListID, 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	boardID := uint(0)
-	if input.BoardID != "" {
-		bid, err := strconv.ParseUint(input.BoardID, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		boardID = uint(bid)
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
 	}
-	c, err := r.CardService.CreateCard(uint(lid), boardID, input.Title, ptrToStr(input.Content))
+	// 卡片所屬的看板一律由清單決定，不採用 input.boardId
+	c, err := r.CardService.CreateCard(userID, uint(lid), services.CardDetails{
+		Title:           input.Title,
+		Content:         ptrToStr(input.Content),
+		StartAt:         input.StartAt,
+		DueAt:           input.DueAt,
+		CompletedAt:     input.CompletedAt,
+		ReminderMinutes: int32ToIntPtr(input.ReminderMinutes),
+		Priority:        toCardPriority(input.Priority),
+		Estimate:        input.Estimate,
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &model.Card{
-		ID:        strconv.FormatUint(uint64(c.ID), 10),
-		Title:     c.Title,
-		Content:   strToPtr(c.Content),
-		ListID:    strconv.FormatUint(uint64(c.ListID), 10),
-		CreatedAt: c.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: c.UpdatedAt.Format(utils.TimeFormat),
-		Position:  int32(c.Position),
-	}, nil
+	return toModelCard(c), nil
 }
 
 func (r *mutationResolver) UpdateCard(ctx context.Context, input model.UpdateCardInput) (*model.Card, error) {
@@ -42,7 +53,26 @@ func (r *mutationResolver) UpdateCard(ctx context.Context, input model.UpdateCar
 	if err != nil {
 		return nil, err
 	}
-	err = r.CardService.UpdateCard(uint(id), input.Title, ptrToStr(input.Content))
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	before, err := r.CardService.GetCardByID(uint(id))
+	if err != nil {
+		return nil, err
+	}
+	// 未指定的日期、提醒、優先順序與估計工作量維持原值，明確指定為 null 時清除
+	details := services.CardDetails{
+		Title:   input.Title,
+		Content: ptrToStr(input.Content),
+	}
+	details.StartAt = optionalCardField(&details, services.CardStartAt, input.StartAt)
+	details.DueAt = optionalCardField(&details, services.CardDueAt, input.DueAt)
+	details.CompletedAt = optionalCardField(&details, services.CardCompletedAt, input.CompletedAt)
+	details.ReminderMinutes = int32ToIntPtr(optionalCardField(&details, services.CardReminder, input.ReminderMinutes))
+	details.Priority = toCardPriority(optionalCardField(&details, services.CardPriority, input.Priority))
+	details.Estimate = optionalCardField(&details, services.CardEstimate, input.Estimate)
+	err = r.CardService.UpdateCard(userID, uint(id), details)
 	if err != nil {
 		return nil, err
 	}
@@ -50,23 +80,23 @@ func (r *mutationResolver) UpdateCard(ctx context.Context, input model.UpdateCar
 	if err != nil {
 		return nil, err
 	}
-	return &model.Card{
-		ID:        strconv.FormatUint(uint64(c.ID), 10),
-		Title:     c.Title,
-		Content:   strToPtr(c.Content),
-		ListID:    strconv.FormatUint(uint64(c.ListID), 10),
-		CreatedAt: c.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: c.UpdatedAt.Format(utils.TimeFormat),
-		Position:  int32(c.Position),
-	}, nil
+	notifyWatchers(ctx, func(actorID string) error {
+		return r.WatchService.NotifyDueDateChanged(actorID, c, before.DueAt)
+	})
+	return toModelCard(c), nil
 }
 
 func (r *mutationResolver) DeleteCard(ctx context.Context, id string) (bool, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return false, errors.New("未驗證身份")
+	}
 	cid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
 		return false, err
 	}
-	err = r.CardService.DeleteCard(uint(cid))
+	// 卡片移至垃圾桶，附件檔案在永久刪除時才清除
+	err = r.CardService.DeleteCard(userID, uint(cid))
 	return err == nil, err
 }
 
@@ -79,7 +109,15 @@ func (r *mutationResolver) MoveCard(ctx context.Context, input model.MoveCardInp
 	if err != nil {
 		return nil, err
 	}
-	err = r.CardService.MoveCard(uint(id), uint(targetListID), int(input.NewPosition))
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	before, err := r.CardService.GetCardByID(uint(id))
+	if err != nil {
+		return nil, err
+	}
+	err = r.CardService.MoveCard(userID, uint(id), uint(targetListID), int(input.NewPosition))
 	if err != nil {
 		return nil, err
 	}
@@ -87,57 +125,235 @@ func (r *mutationResolver) MoveCard(ctx context.Context, input model.MoveCardInp
 	if err != nil {
 		return nil, err
 	}
-	return &model.Card{
-		ID:        strconv.FormatUint(uint64(c.ID), 10),
-		Title:     c.Title,
-		Content:   strToPtr(c.Content),
-		ListID:    strconv.FormatUint(uint64(c.ListID), 10),
-		CreatedAt: c.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: c.UpdatedAt.Format(utils.TimeFormat),
-		Position:  int32(c.Position),
-	}, nil
+	notifyWatchers(ctx, func(actorID string) error {
+		return r.WatchService.NotifyCardMoved(actorID, c, before.ListID)
+	})
+	return toModelCard(c), nil
 }
 
-func (r *queryResolver) Cards(ctx context.Context, listID string) ([]*model.Card, error) {
+// SortListCards 依欄位重新排列清單中的卡片，未指定方向時為遞增
+func (r *mutationResolver) SortListCards(ctx context.Context, listID string, by model.ListSortField, direction *model.SortDirection) ([]*model.Card, error) {
 	lid, err := strconv.ParseUint(listID, 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	cards, err := r.CardService.GetCards(uint(lid))
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	descending := direction != nil && *direction == model.SortDirectionDesc
+	cards, err := r.CardService.SortListCards(userID, uint(lid), services.ListSortField(strings.ToLower(by.String())), descending)
+	if err != nil {
+		return nil, err
+	}
+	return toModelCards(cards), nil
+}
+
+func (r *mutationResolver) ArchiveCard(ctx context.Context, id string) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	cid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.CardService.ArchiveCard(userID, uint(cid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCard(c), nil
+}
+
+func (r *mutationResolver) UnarchiveCard(ctx context.Context, id string) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	cid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	result := make([]*model.Card, 0, len(cards))
-	for _, c := range cards {
-		result = append(result, &model.Card{
-			ID:        strconv.FormatUint(uint64(c.ID), 10),
-			Title:     c.Title,
-			Content:   strToPtr(c.Content),
-			ListID:    strconv.FormatUint(uint64(c.ListID), 10),
-			CreatedAt: c.CreatedAt.Format(utils.TimeFormat),
-			UpdatedAt: c.UpdatedAt.Format(utils.TimeFormat),
-			Position:  int32(c.Position),
-		})
+	c, err := r.CardService.UnarchiveCard(userID, uint(cid))
+	if err != nil {
+		return nil, err
 	}
-	return result, nil
+	return toModelCard(c), nil
 }
 
-func (r *queryResolver) Card(ctx context.Context, id string) (*model.Card, error) {
+func (r *mutationResolver) SetCardTemplate(ctx context.Context, id string, isTemplate bool) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
 	cid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	c, err := r.CardService.GetCardByID(uint(cid))
+	c, err := r.CardService.SetTemplate(userID, uint(cid), isTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return toModelCard(c), nil
+}
+
+// CreateCardFromTemplate 以範本建立卡片，並複製範本的自訂欄位值
+func (r *mutationResolver) CreateCardFromTemplate(ctx context.Context, templateID string, listID string) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	tid, err := strconv.ParseUint(templateID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	lid, err := strconv.ParseUint(listID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.CardService.CreateCardFromTemplate(userID, uint(tid), uint(lid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCard(c), nil
+}
+
+func (r *queryResolver) CardTemplates(ctx context.Context, boardID string) ([]*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(boardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := r.CardService.GetTemplates(userID, uint(bid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCards(cards), nil
+}
+
+func (r *queryResolver) Cards(ctx context.Context, listID string, customFields []*model.CustomFieldFilterInput, sortBy *model.CardSortField) ([]*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	lid, err := strconv.ParseUint(listID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := toCustomFieldFilters(customFields)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := r.CustomFieldService.FilterCards(userID, uint(lid), filters)
+	if err != nil {
+		return nil, err
+	}
+	if sortBy != nil && *sortBy == model.CardSortFieldPriority {
+		services.SortCardsByPriority(cards)
+	}
+	return toModelCards(cards), nil
+}
+
+// Card 以 ID 或卡片參照（例如 WEB-142）取得卡片；以參照查詢時只會找到目前使用者看板中的卡片
+func (r *queryResolver) Card(ctx context.Context, id *string, ref *string) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	var (
+		c   *models.Card
+		err error
+	)
+	switch {
+	case id != nil && ref != nil:
+		return nil, errors.New("id 與 ref 只能擇一指定")
+	case id != nil:
+		cid, perr := strconv.ParseUint(*id, 10, 64)
+		if perr != nil {
+			return nil, perr
+		}
+		c, err = r.CardService.GetReadableCard(userID, uint(cid))
+	case ref != nil:
+		c, err = r.CardService.GetCardByReference(userID, *ref)
+	default:
+		return nil, errors.New("必須指定 id 或 ref")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toModelCard(c), nil
+}
+
+// Reference 以看板代號與卡片編號組成卡片參照
+func (r *cardResolver) Reference(ctx context.Context, obj *model.Card) (string, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return "", errors.New("dataloader not found in context")
+	}
+	thunk := loaders.BoardKeyByID.Load(ctx, dataloader.StringKey(obj.BoardID))
+	result, err := thunk()
+	if err != nil {
+		return "", err
+	}
+	key, ok := result.(string)
+	if !ok {
+		return "", errors.New("unexpected dataloader result type")
+	}
+	return fmt.Sprintf("%s-%d", key, obj.Number), nil
+}
+
+// OverdueCards 取得看板中已過期且未完成的卡片
+func (r *queryResolver) OverdueCards(ctx context.Context, boardID string) ([]*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(boardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := r.CardService.GetOverdueCards(userID, uint(bid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCards(cards), nil
+}
+
+// DueSoonCards 取得看板中即將到期的卡片
+func (r *queryResolver) DueSoonCards(ctx context.Context, boardID string, withinHours *int32) ([]*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(boardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	hours := int32(defaultDueSoonHours)
+	if withinHours != nil {
+		hours = *withinHours
+	}
+	if hours < 1 || hours > maxDueSoonHours {
+		return nil, fmt.Errorf("withinHours 須介於 1 到 %d 之間", maxDueSoonHours)
+	}
+	cards, err := r.CardService.GetDueSoonCards(userID, uint(bid), time.Duration(hours)*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	return toModelCards(cards), nil
+}
+
+// ContentHTML 回傳卡片內容渲染後的 HTML，沒有內容時為 null
+func (r *cardResolver) ContentHTML(ctx context.Context, obj *model.Card) (*string, error) {
+	if obj.Content == nil || *obj.Content == "" {
+		return nil, nil
+	}
+	html, err := r.CardService.RenderContent(*obj.Content)
 	if err != nil {
 		return nil, err
 	}
-	return &model.Card{
-		ID:        strconv.FormatUint(uint64(c.ID), 10),
-		Title:     c.Title,
-		Content:   strToPtr(c.Content),
-		ListID:    strconv.FormatUint(uint64(c.ListID), 10),
-		CreatedAt: c.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: c.UpdatedAt.Format(utils.TimeFormat),
-		Position:  int32(c.Position),
-	}, nil
+	return &html, nil
 }
@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"trello-backend/graph/model"
+	"trello-backend/internal/models"
+	"trello-backend/internal/services"
+
+	"github.com/graph-gophers/dataloader"
+)
+
+// 卡片關係相關 resolver function
+
+func (r *mutationResolver) AddCardRelation(ctx context.Context, input model.CardRelationInput) (*model.Card, error) {
+	return r.changeCardRelation(ctx, input, r.CardRelationService.AddRelation)
+}
+
+func (r *mutationResolver) RemoveCardRelation(ctx context.Context, input model.CardRelationInput) (*model.Card, error) {
+	return r.changeCardRelation(ctx, input, r.CardRelationService.RemoveRelation)
+}
+
+func (r *mutationResolver) changeCardRelation(ctx context.Context, input model.CardRelationInput, change func(string, uint, uint, models.CardRelationType) error) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	fromID, err := strconv.ParseUint(input.FromCardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	toID, err := strconv.ParseUint(input.ToCardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	relationType := models.CardRelationType(strings.ToLower(input.Type.String()))
+	if err := change(userID, uint(fromID), uint(toID), relationType); err != nil {
+		return nil, err
+	}
+	c, err := r.CardService.GetCardByID(uint(fromID))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCard(c), nil
+}
+
+func (r *cardResolver) Blocks(ctx context.Context, obj *model.Card) ([]*model.Card, error) {
+	relations, err := loadCardRelations(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	return toModelCards(relations.Blocks), nil
+}
+
+func (r *cardResolver) BlockedBy(ctx context.Context, obj *model.Card) ([]*model.Card, error) {
+	relations, err := loadCardRelations(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	return toModelCards(relations.BlockedBy), nil
+}
+
+func (r *cardResolver) RelatedCards(ctx context.Context, obj *model.Card) ([]*model.Card, error) {
+	relations, err := loadCardRelations(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	return toModelCards(relations.Related), nil
+}
+
+func (r *cardResolver) HasOpenBlockers(ctx context.Context, obj *model.Card) (bool, error) {
+	relations, err := loadCardRelations(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+	return relations.OpenBlockers > 0, nil
+}
+
+func (r *cardResolver) BlockedWarning(ctx context.Context, obj *model.Card) (bool, error) {
+	relations, err := loadCardRelations(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+	return relations.BlockedWarning(), nil
+}
+
+// loadCardRelations 透過 dataloader 批次取得卡片的關係摘要
+func loadCardRelations(ctx context.Context, obj *model.Card) (services.CardRelations, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return services.CardRelations{}, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.CardRelationsByCardID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return services.CardRelations{}, err
+	}
+	relations, ok := result.(services.CardRelations)
+	if !ok {
+		return services.CardRelations{}, errors.New("unexpected dataloader result type")
+	}
+	return relations, nil
+}
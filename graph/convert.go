@@ -0,0 +1,325 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"trello-backend/graph/model"
+	"trello-backend/internal/models"
+	"trello-backend/internal/services"
+	"trello-backend/pkg/utils"
+
+	"github.com/99designs/gqlgen/graphql"
+	"gorm.io/gorm"
+)
+
+// toModelBoard 將 DB 的 Board 轉為 GraphQL 的 Board
+func toModelBoard(b *models.Board) *model.Board {
+	return &model.Board{
+		ID:         strconv.FormatUint(uint64(b.ID), 10),
+		Name:       b.Name,
+		Key:        b.Key,
+		Position:   int32(b.Position),
+		CreatedAt:  b.CreatedAt.Format(utils.TimeFormat),
+		UpdatedAt:  b.UpdatedAt.Format(utils.TimeFormat),
+		ArchivedAt: b.ArchivedAt,
+		DeletedAt:  deletedAtPtr(b.DeletedAt),
+		ClosedAt:   b.ClosedAt,
+		Settings:   toModelBoardSettings(&b.Settings),
+		IsTemplate: b.IsTemplate,
+	}
+}
+
+func toModelBoardSettings(s *models.BoardSettings) *model.BoardSettings {
+	settings := &model.BoardSettings{
+		Description:       s.Description,
+		Visibility:        model.BoardVisibility(strings.ToUpper(string(s.Visibility))),
+		CommentsEnabled:   !s.CommentsDisabled,
+		VotingEnabled:     !s.VotingDisabled,
+		CardCoversEnabled: !s.CardCoversDisabled,
+	}
+	if s.BackgroundColor != "" {
+		settings.BackgroundColor = strToPtr(s.BackgroundColor)
+	}
+	if s.BackgroundImageURL != "" {
+		settings.BackgroundImageURL = strToPtr(s.BackgroundImageURL)
+	}
+	return settings
+}
+
+// toBoardSettingsPatch 將 GraphQL 的設定輸入轉為 service 的變更內容
+func toBoardSettingsPatch(in *model.BoardSettingsInput) services.BoardSettingsPatch {
+	patch := services.BoardSettingsPatch{
+		Description:        in.Description,
+		BackgroundColor:    in.BackgroundColor,
+		BackgroundImageURL: in.BackgroundImageURL,
+		CommentsEnabled:    in.CommentsEnabled,
+		VotingEnabled:      in.VotingEnabled,
+		CardCoversEnabled:  in.CardCoversEnabled,
+	}
+	if in.Visibility != nil {
+		v := models.BoardVisibility(strings.ToLower(in.Visibility.String()))
+		patch.Visibility = &v
+	}
+	return patch
+}
+
+func toModelBoards(boards []models.Board) []*model.Board {
+	result := make([]*model.Board, 0, len(boards))
+	for i := range boards {
+		result = append(result, toModelBoard(&boards[i]))
+	}
+	return result
+}
+
+// toModelList 將 DB 的 List 轉為 GraphQL 的 List
+func toModelList(l *models.List) *model.List {
+	return &model.List{
+		ID:         strconv.FormatUint(uint64(l.ID), 10),
+		Name:       l.Name,
+		BoardID:    strconv.FormatUint(uint64(l.BoardID), 10),
+		CreatedAt:  l.CreatedAt.Format(utils.TimeFormat),
+		UpdatedAt:  l.UpdatedAt.Format(utils.TimeFormat),
+		Position:   int32(l.Position),
+		IsDone:     l.IsDone,
+		ArchivedAt: l.ArchivedAt,
+		DeletedAt:  deletedAtPtr(l.DeletedAt),
+		WipLimit:   intToInt32Ptr(l.WipLimit),
+		WipMode:    model.WipLimitMode(strings.ToUpper(string(l.WipMode))),
+	}
+}
+
+func toModelLists(lists []models.List) []*model.List {
+	result := make([]*model.List, 0, len(lists))
+	for i := range lists {
+		result = append(result, toModelList(&lists[i]))
+	}
+	return result
+}
+
+// toModelCard 將 DB 的 Card 轉為 GraphQL 的 Card
+func toModelCard(c *models.Card) *model.Card {
+	return &model.Card{
+		ID:              strconv.FormatUint(uint64(c.ID), 10),
+		Title:           c.Title,
+		Content:         strToPtr(c.Content),
+		ListID:          strconv.FormatUint(uint64(c.ListID), 10),
+		BoardID:         strconv.FormatUint(uint64(c.BoardID), 10),
+		Number:          int32(c.Number),
+		CreatedAt:       c.CreatedAt.Format(utils.TimeFormat),
+		UpdatedAt:       c.UpdatedAt.Format(utils.TimeFormat),
+		Position:        int32(c.Position),
+		StartAt:         c.StartAt,
+		DueAt:           c.DueAt,
+		CompletedAt:     c.CompletedAt,
+		ReminderMinutes: intToInt32Ptr(c.ReminderMinutes),
+		Cover:           toModelCardCover(c),
+		ArchivedAt:      c.ArchivedAt,
+		DeletedAt:       deletedAtPtr(c.DeletedAt),
+		IsTemplate:      c.IsTemplate,
+		Priority:        model.CardPriority(strings.ToUpper(string(c.Priority))),
+		Estimate:        c.Estimate,
+	}
+}
+
+// toCardPriority 未指定時回傳 nil
+func toCardPriority(p *model.CardPriority) *models.CardPriority {
+	if p == nil {
+		return nil
+	}
+	priority := models.CardPriority(strings.ToLower(p.String()))
+	return &priority
+}
+
+// toModelCardCover 未設定封面時回傳 nil
+func toModelCardCover(c *models.Card) *model.CardCover {
+	if c.CoverAttachmentID == nil && c.CoverColor == "" {
+		return nil
+	}
+	cover := &model.CardCover{CardID: strconv.FormatUint(uint64(c.ID), 10)}
+	if c.CoverAttachmentID != nil {
+		id := strconv.FormatUint(uint64(*c.CoverAttachmentID), 10)
+		cover.AttachmentID = &id
+	}
+	if c.CoverColor != "" {
+		cover.Color = strToPtr(c.CoverColor)
+	}
+	return cover
+}
+
+func toModelCards(cards []models.Card) []*model.Card {
+	result := make([]*model.Card, 0, len(cards))
+	for i := range cards {
+		result = append(result, toModelCard(&cards[i]))
+	}
+	return result
+}
+
+// toModelAttachment 將 DB 的 Attachment 轉為 GraphQL 的 Attachment，並附上簽章連結
+func toModelAttachment(a *models.Attachment, attachmentService services.AttachmentService) *model.Attachment {
+	result := &model.Attachment{
+		ID:          strconv.FormatUint(uint64(a.ID), 10),
+		CardID:      strconv.FormatUint(uint64(a.CardID), 10),
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        int32(a.Size),
+		URL:         attachmentService.DownloadURL(a),
+		CreatedAt:   a.CreatedAt.Format(utils.TimeFormat),
+	}
+	if thumbnailURL := attachmentService.ThumbnailURL(a); thumbnailURL != "" {
+		result.ThumbnailURL = &thumbnailURL
+	}
+	return result
+}
+
+func deletedAtPtr(d gorm.DeletedAt) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Time
+}
+
+func intToInt32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
+
+// optionalCardField 取出可省略的欄位值；明確指定為 null 時將 field 加入 details.Clear
+func optionalCardField[T any](details *services.CardDetails, field services.CardField, o graphql.Omittable[*T]) *T {
+	v, set := o.ValueOK()
+	if set && v == nil {
+		details.Clear |= field
+	}
+	return v
+}
+
+func int32ToIntPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+// toModelCustomField 將 DB 的 CustomField 轉為 GraphQL 的 CustomField
+func toModelCustomField(f *models.CustomField) *model.CustomField {
+	options := make([]*model.CustomFieldOption, 0, len(f.Options))
+	for i := range f.Options {
+		options = append(options, toModelCustomFieldOption(&f.Options[i]))
+	}
+	return &model.CustomField{
+		ID:       strconv.FormatUint(uint64(f.ID), 10),
+		BoardID:  strconv.FormatUint(uint64(f.BoardID), 10),
+		Name:     f.Name,
+		Type:     model.CustomFieldType(strings.ToUpper(string(f.Type))),
+		Position: int32(f.Position),
+		Options:  options,
+	}
+}
+
+func toModelCustomFields(fields []models.CustomField) []*model.CustomField {
+	result := make([]*model.CustomField, 0, len(fields))
+	for i := range fields {
+		result = append(result, toModelCustomField(&fields[i]))
+	}
+	return result
+}
+
+func toModelCustomFieldOption(o *models.CustomFieldOption) *model.CustomFieldOption {
+	return &model.CustomFieldOption{
+		ID:    strconv.FormatUint(uint64(o.ID), 10),
+		Label: o.Label,
+	}
+}
+
+// toModelCustomFieldValue 需預載 CustomField 與其選項
+func toModelCustomFieldValue(v *models.CustomFieldValue) *model.CustomFieldValue {
+	result := &model.CustomFieldValue{
+		Field:   toModelCustomField(&v.CustomField),
+		Text:    v.TextValue,
+		Number:  v.NumberValue,
+		Date:    v.DateValue,
+		Checked: v.CheckboxValue,
+	}
+	if v.OptionID != nil {
+		for i := range v.CustomField.Options {
+			if v.CustomField.Options[i].ID == *v.OptionID {
+				result.Option = toModelCustomFieldOption(&v.CustomField.Options[i])
+			}
+		}
+	}
+	return result
+}
+
+// parseIDs 將多個 GraphQL ID 轉為 uint
+func parseIDs(ids []string) ([]uint, error) {
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		v, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, uint(v))
+	}
+	return result, nil
+}
+
+func toModelNotification(n *models.Notification) *model.Notification {
+	return &model.Notification{
+		ID:        strconv.FormatUint(uint64(n.ID), 10),
+		Type:      model.NotificationType(strings.ToUpper(string(n.Type))),
+		BoardID:   strconv.FormatUint(uint64(n.BoardID), 10),
+		CardID:    strconv.FormatUint(uint64(n.CardID), 10),
+		Message:   n.Message,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+func toModelTimeEntry(e *models.TimeEntry) *model.TimeEntry {
+	result := &model.TimeEntry{
+		ID:              strconv.FormatUint(uint64(e.ID), 10),
+		CardID:          strconv.FormatUint(uint64(e.CardID), 10),
+		UserID:          e.UserID,
+		StartedAt:       e.StartedAt,
+		EndedAt:         e.EndedAt,
+		DurationSeconds: int32(e.DurationSeconds),
+	}
+	if e.Note != "" {
+		result.Note = strToPtr(e.Note)
+	}
+	return result
+}
+
+func toModelCardRecurrence(r *models.CardRecurrence) *model.CardRecurrence {
+	result := &model.CardRecurrence{
+		Rule:             r.Rule,
+		ListID:           strconv.FormatUint(uint64(r.ListID), 10),
+		StartAt:          r.StartAt,
+		NextRunAt:        r.NextRunAt,
+		LastOccurrenceAt: r.LastOccurrenceAt,
+		OccurrenceCount:  int32(r.OccurrenceCount),
+	}
+	if r.LastCardID != nil {
+		id := strconv.FormatUint(uint64(*r.LastCardID), 10)
+		result.LastCardID = &id
+	}
+	return result
+}
+
+func toModelCardRevision(r *models.CardRevision) *model.CardRevision {
+	return &model.CardRevision{
+		ID:         strconv.FormatUint(uint64(r.ID), 10),
+		CardID:     strconv.FormatUint(uint64(r.CardID), 10),
+		UserID:     r.UserID,
+		OldTitle:   r.OldTitle,
+		NewTitle:   r.NewTitle,
+		OldContent: strToPtr(r.OldContent),
+		NewContent: strToPtr(r.NewContent),
+		Diff:       services.RevisionDiff(r),
+		CreatedAt:  r.CreatedAt,
+	}
+}
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"trello-backend/graph/model"
+	"trello-backend/internal/repositories"
+)
+
+// 複製相關 resolver function
+
+func (r *mutationResolver) CopyCard(ctx context.Context, input model.CopyCardInput) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	cardID, err := strconv.ParseUint(input.CardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	listID, err := strconv.ParseUint(input.TargetListID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	card, err := r.CopyService.CopyCard(userID, uint(cardID), uint(listID), intPtr(input.Position), toCopyOptions(input.Options))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCard(card), nil
+}
+
+func (r *mutationResolver) CopyList(ctx context.Context, input model.CopyListInput) (*model.List, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	listID, err := strconv.ParseUint(input.ListID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	boardID, err := strconv.ParseUint(input.TargetBoardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	var name string
+	if input.Name != nil {
+		name = *input.Name
+	}
+	list, err := r.CopyService.CopyList(userID, uint(listID), uint(boardID), name, intPtr(input.Position), toCopyOptions(input.Options))
+	if err != nil {
+		return nil, err
+	}
+	return toModelList(list), nil
+}
+
+func (r *mutationResolver) CopyBoard(ctx context.Context, input model.CopyBoardInput) (*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	boardID, err := strconv.ParseUint(input.BoardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	var name string
+	if input.Name != nil {
+		name = *input.Name
+	}
+	board, err := r.CopyService.CopyBoard(userID, uint(boardID), name, toCopyOptions(input.Options))
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoard(board), nil
+}
+
+// CreateBoardFromTemplate 以範本看板為目前使用者建立新看板
+func (r *mutationResolver) CreateBoardFromTemplate(ctx context.Context, templateID string, name *string) (*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	tid, err := strconv.ParseUint(templateID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	board, err := r.CopyService.CreateBoardFromTemplate(userID, uint(tid), ptrToStr(name))
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoard(board), nil
+}
+
+// toCopyOptions 未提供選項時只帶入自訂欄位值，與 schema 的預設值一致
+func toCopyOptions(input *model.CopyOptionsInput) repositories.CopyOptions {
+	opts := repositories.CopyOptions{CustomFieldValues: true}
+	if input == nil {
+		return opts
+	}
+	if input.Attachments != nil {
+		opts.Attachments = *input.Attachments
+	}
+	if input.CustomFieldValues != nil {
+		opts.CustomFieldValues = *input.CustomFieldValues
+	}
+	return opts
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
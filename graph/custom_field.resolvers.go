@@ -0,0 +1,192 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"trello-backend/graph/model"
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+	"trello-backend/internal/services"
+
+	"github.com/graph-gophers/dataloader"
+)
+
+// 自訂欄位相關 resolver function
+
+func (r *mutationResolver) CreateCustomField(ctx context.Context, input model.CreateCustomFieldInput) (*model.CustomField, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(input.BoardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	fieldType := models.CustomFieldType(strings.ToLower(input.Type.String()))
+	f, err := r.CustomFieldService.CreateField(userID, uint(bid), input.Name, fieldType, input.Options)
+	if err != nil {
+		return nil, err
+	}
+	return toModelCustomField(f), nil
+}
+
+func (r *mutationResolver) UpdateCustomField(ctx context.Context, input model.UpdateCustomFieldInput) (*model.CustomField, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	id, err := strconv.ParseUint(input.ID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	var options []models.CustomFieldOption
+	if input.Options != nil {
+		options = make([]models.CustomFieldOption, 0, len(input.Options))
+		for _, o := range input.Options {
+			option := models.CustomFieldOption{Label: o.Label}
+			if o.ID != nil {
+				oid, err := strconv.ParseUint(*o.ID, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				option.ID = uint(oid)
+			}
+			options = append(options, option)
+		}
+	}
+	f, err := r.CustomFieldService.UpdateField(userID, uint(id), input.Name, options)
+	if err != nil {
+		return nil, err
+	}
+	return toModelCustomField(f), nil
+}
+
+func (r *mutationResolver) DeleteCustomField(ctx context.Context, id string) (bool, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return false, errors.New("未驗證身份")
+	}
+	fid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	err = r.CustomFieldService.DeleteField(userID, uint(fid))
+	return err == nil, err
+}
+
+func (r *mutationResolver) SetCustomFieldValue(ctx context.Context, input model.SetCustomFieldValueInput) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	cid, err := strconv.ParseUint(input.CardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	fid, err := strconv.ParseUint(input.FieldID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	details := services.CustomFieldValueDetails{
+		Text:    input.Text,
+		Number:  input.Number,
+		Date:    input.Date,
+		Checked: input.Checked,
+	}
+	if input.OptionID != nil {
+		oid, err := strconv.ParseUint(*input.OptionID, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		optionID := uint(oid)
+		details.OptionID = &optionID
+	}
+	if err := r.CustomFieldService.SetCardValue(userID, uint(cid), uint(fid), details); err != nil {
+		return nil, err
+	}
+	c, err := r.CardService.GetCardByID(uint(cid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCard(c), nil
+}
+
+func (r *queryResolver) CustomFields(ctx context.Context, boardID string) ([]*model.CustomField, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(boardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := r.CustomFieldService.GetFields(userID, uint(bid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCustomFields(fields), nil
+}
+
+// CustomFields is the resolver for the customFields field.
+func (r *boardResolver) CustomFields(ctx context.Context, obj *model.Board) ([]*model.CustomField, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	boardID, err := strconv.ParseUint(obj.ID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := r.CustomFieldService.GetFields(userID, uint(boardID))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCustomFields(fields), nil
+}
+
+// CustomFieldValues 透過 dataloader 批次取得卡片的自訂欄位值
+func (r *cardResolver) CustomFieldValues(ctx context.Context, obj *model.Card) ([]*model.CustomFieldValue, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return nil, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.CustomFieldValuesByCardID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return nil, err
+	}
+	values, ok := result.([]*model.CustomFieldValue)
+	if !ok {
+		return nil, errors.New("unexpected dataloader result type")
+	}
+	return values, nil
+}
+
+// toCustomFieldFilters 將 GraphQL 的篩選條件轉為 repository 使用的條件
+func toCustomFieldFilters(inputs []*model.CustomFieldFilterInput) ([]repositories.CustomFieldFilter, error) {
+	filters := make([]repositories.CustomFieldFilter, 0, len(inputs))
+	for _, in := range inputs {
+		fid, err := strconv.ParseUint(in.FieldID, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		optionIDs, err := parseIDs(in.OptionIds)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, repositories.CustomFieldFilter{
+			FieldID:   uint(fid),
+			Text:      in.Text,
+			Min:       in.Min,
+			Max:       in.Max,
+			From:      in.From,
+			To:        in.To,
+			Checked:   in.Checked,
+			OptionIDs: optionIDs,
+			IsEmpty:   in.IsEmpty,
+		})
+	}
+	return filters, nil
+}
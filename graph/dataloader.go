@@ -4,15 +4,39 @@ import (
 	"context"
 	"strconv"
 	"trello-backend/graph/model"
+	"trello-backend/internal/repositories"
 	"trello-backend/internal/services"
 
-	"trello-backend/pkg/utils"
-
 	"github.com/graph-gophers/dataloader"
 )
 
 type Loaders struct {
-	CardsByListID *dataloader.Loader
+	CardsByListID       *dataloader.Loader
+	AttachmentsByCardID *dataloader.Loader
+	// 卡片的自訂欄位值
+	CustomFieldValuesByCardID *dataloader.Loader
+	// 卡片的阻擋與關聯關係
+	CardRelationsByCardID *dataloader.Loader
+	// 目前使用者是否關注看板、清單或卡片，key 為 watchKey 的格式
+	WatchingByKey *dataloader.Loader
+	// 清單中卡片估計工作量的總和
+	EstimateTotalByListID *dataloader.Loader
+	// 看板中未封存清單的估計工作量總和
+	EstimateTotalByBoardID *dataloader.Loader
+	// 卡片的時間紀錄與已花費的總秒數
+	TimeEntriesByCardID *dataloader.Loader
+	TimeSpentByCardID   *dataloader.Loader
+	// 以卡片為來源的重複規則
+	RecurrenceByCardID *dataloader.Loader
+	// 看板代號，用於組成卡片參照
+	BoardKeyByID *dataloader.Loader
+	// 卡片標題與內容的修改紀錄
+	RevisionsByCardID *dataloader.Loader
+	// 卡片的票數與目前使用者是否投過票
+	VoteCountByCardID   *dataloader.Loader
+	ViewerVotedByCardID *dataloader.Loader
+	// 目前使用者是否對看板加上星號
+	StarredByBoardID *dataloader.Loader
 }
 
 // CardsBatchFn 批次查詢多個 List 的 Cards
@@ -29,32 +53,309 @@ func CardsBatchFn(cardService services.CardService) dataloader.BatchFunc {
 		for i, k := range keys {
 			id, _ := strconv.ParseUint(k.String(), 10, 64)
 			cards := cardsMap[uint(id)]
-			modelCards := make([]*model.Card, 0, len(cards))
-			for _, c := range cards {
-				modelCards = append(modelCards, &model.Card{
-					ID:        strconv.FormatUint(uint64(c.ID), 10),
-					Title:     c.Title,
-					Content:   strToPtr(c.Content),
-					ListID:    strconv.FormatUint(uint64(c.ListID), 10),
-					CreatedAt: c.CreatedAt.Format(utils.TimeFormat),
-					UpdatedAt: c.UpdatedAt.Format(utils.TimeFormat),
-					Position:  int32(c.Position),
-				})
-			}
+			modelCards := toModelCards(cards)
 			results[i] = &dataloader.Result{Data: modelCards, Error: err}
 		}
 		return results
 	}
 }
 
+// AttachmentsBatchFn 批次查詢多張 Card 的附件，只有看板擁有者能取得附件與其簽章連結
+func AttachmentsBatchFn(attachmentService services.AttachmentService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		cardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			cardIDs[i] = uint(id)
+		}
+		userID, _ := UserIDFromContext(ctx)
+		attachmentsMap, err := attachmentService.GetAttachmentsByCardIDs(userID, cardIDs)
+		for i, id := range cardIDs {
+			if err != nil {
+				results[i] = &dataloader.Result{Error: err}
+				continue
+			}
+			attachments, ok := attachmentsMap[id]
+			if !ok {
+				// 不在使用者看板中的卡片不產生下載連結
+				results[i] = &dataloader.Result{Error: services.ErrForbidden}
+				continue
+			}
+			modelAttachments := make([]*model.Attachment, 0, len(attachments))
+			for j := range attachments {
+				modelAttachments = append(modelAttachments, toModelAttachment(&attachments[j], attachmentService))
+			}
+			results[i] = &dataloader.Result{Data: modelAttachments}
+		}
+		return results
+	}
+}
+
+// CustomFieldValuesBatchFn 批次查詢多張 Card 的自訂欄位值
+func CustomFieldValuesBatchFn(customFieldService services.CustomFieldService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		cardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			cardIDs[i] = uint(id)
+		}
+		valuesMap, err := customFieldService.GetValuesByCardIDs(cardIDs)
+		for i, id := range cardIDs {
+			values := valuesMap[id]
+			modelValues := make([]*model.CustomFieldValue, 0, len(values))
+			for j := range values {
+				modelValues = append(modelValues, toModelCustomFieldValue(&values[j]))
+			}
+			results[i] = &dataloader.Result{Data: modelValues, Error: err}
+		}
+		return results
+	}
+}
+
+// CardRelationsBatchFn 批次查詢多張 Card 的關係，結果型別為 services.CardRelations
+func CardRelationsBatchFn(cardRelationService services.CardRelationService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		cardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			cardIDs[i] = uint(id)
+		}
+		relationsMap, err := cardRelationService.GetRelationsByCardIDs(cardIDs)
+		for i, id := range cardIDs {
+			results[i] = &dataloader.Result{Data: relationsMap[id], Error: err}
+		}
+		return results
+	}
+}
+
+// EstimateTotalsBatchFn 批次計算多個 List 的估計工作量總和，結果型別為 float64
+func EstimateTotalsBatchFn(cardService services.CardService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		listIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			listIDs[i] = uint(id)
+		}
+		totals, err := cardService.GetEstimateTotals(listIDs)
+		for i, id := range listIDs {
+			results[i] = &dataloader.Result{Data: totals[id], Error: err}
+		}
+		return results
+	}
+}
+
+// BoardEstimateTotalsBatchFn 批次計算多個 Board 的估計工作量總和，結果型別為 float64
+func BoardEstimateTotalsBatchFn(cardService services.CardService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		boardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			boardIDs[i] = uint(id)
+		}
+		totals, err := cardService.GetBoardEstimateTotals(boardIDs)
+		for i, id := range boardIDs {
+			results[i] = &dataloader.Result{Data: totals[id], Error: err}
+		}
+		return results
+	}
+}
+
+// TimeEntriesBatchFn 批次查詢多張 Card 的時間紀錄
+func TimeEntriesBatchFn(timeTrackingService services.TimeTrackingService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		cardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			cardIDs[i] = uint(id)
+		}
+		entriesMap, err := timeTrackingService.GetEntriesByCardIDs(cardIDs)
+		for i, id := range cardIDs {
+			entries := entriesMap[id]
+			modelEntries := make([]*model.TimeEntry, 0, len(entries))
+			for j := range entries {
+				modelEntries = append(modelEntries, toModelTimeEntry(&entries[j]))
+			}
+			results[i] = &dataloader.Result{Data: modelEntries, Error: err}
+		}
+		return results
+	}
+}
+
+// TimeSpentBatchFn 批次計算多張 Card 已花費的總秒數，結果型別為 int32
+func TimeSpentBatchFn(timeTrackingService services.TimeTrackingService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		cardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			cardIDs[i] = uint(id)
+		}
+		totals, err := timeTrackingService.GetTotalsByCardIDs(cardIDs)
+		for i, id := range cardIDs {
+			results[i] = &dataloader.Result{Data: int32(totals[id]), Error: err}
+		}
+		return results
+	}
+}
+
+// RevisionsBatchFn 批次查詢多張 Card 的修改紀錄
+func RevisionsBatchFn(cardService services.CardService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		cardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			cardIDs[i] = uint(id)
+		}
+		revisionsMap, err := cardService.GetRevisionsByCardIDs(cardIDs)
+		for i, id := range cardIDs {
+			revisions := revisionsMap[id]
+			modelRevisions := make([]*model.CardRevision, 0, len(revisions))
+			for j := range revisions {
+				modelRevisions = append(modelRevisions, toModelCardRevision(&revisions[j]))
+			}
+			results[i] = &dataloader.Result{Data: modelRevisions, Error: err}
+		}
+		return results
+	}
+}
+
+// BoardKeyBatchFn 批次查詢多個看板的代號
+func BoardKeyBatchFn(boardService services.BoardService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		boardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			boardIDs[i] = uint(id)
+		}
+		boardKeys, err := boardService.GetBoardKeys(boardIDs)
+		for i, id := range boardIDs {
+			results[i] = &dataloader.Result{Data: boardKeys[id], Error: err}
+		}
+		return results
+	}
+}
+
+// RecurrenceBatchFn 批次查詢多張 Card 的重複規則，沒有規則時結果為 nil
+func RecurrenceBatchFn(recurrenceService services.RecurrenceService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		cardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			cardIDs[i] = uint(id)
+		}
+		recurrences, err := recurrenceService.GetRecurrencesByCardIDs(cardIDs)
+		for i, id := range cardIDs {
+			var data *model.CardRecurrence
+			if rec, ok := recurrences[id]; ok {
+				data = toModelCardRecurrence(&rec)
+			}
+			results[i] = &dataloader.Result{Data: data, Error: err}
+		}
+		return results
+	}
+}
+
+// WatchingBatchFn 批次查詢目前使用者是否關注多個項目，結果型別為 bool
+func WatchingBatchFn(watchService services.WatchService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		targets := make([]repositories.WatchTarget, len(keys))
+		for i, k := range keys {
+			targets[i] = parseWatchKey(k.String())
+		}
+		userID, _ := UserIDFromContext(ctx)
+		watched, err := watchService.GetWatched(userID, targets)
+		for i, t := range targets {
+			results[i] = &dataloader.Result{Data: watched[t], Error: err}
+		}
+		return results
+	}
+}
+
+// VoteCountsBatchFn 批次計算多張 Card 的票數，結果型別為 int32
+func VoteCountsBatchFn(voteService services.VoteService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		cardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			cardIDs[i] = uint(id)
+		}
+		counts, err := voteService.GetVoteCounts(cardIDs)
+		for i, id := range cardIDs {
+			results[i] = &dataloader.Result{Data: int32(counts[id]), Error: err}
+		}
+		return results
+	}
+}
+
+// ViewerVotedBatchFn 批次查詢目前使用者是否對多張 Card 投過票，結果型別為 bool
+func ViewerVotedBatchFn(voteService services.VoteService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		cardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			cardIDs[i] = uint(id)
+		}
+		userID, _ := UserIDFromContext(ctx)
+		voted, err := voteService.GetVoted(userID, cardIDs)
+		for i, id := range cardIDs {
+			results[i] = &dataloader.Result{Data: voted[id], Error: err}
+		}
+		return results
+	}
+}
+
+// StarredBatchFn 批次查詢目前使用者是否對多個看板加上星號，結果型別為 bool
+func StarredBatchFn(userBoardService services.UserBoardService) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		boardIDs := make([]uint, len(keys))
+		for i, k := range keys {
+			id, _ := strconv.ParseUint(k.String(), 10, 64)
+			boardIDs[i] = uint(id)
+		}
+		userID, _ := UserIDFromContext(ctx)
+		starred, err := userBoardService.GetStarred(userID, boardIDs)
+		for i, id := range boardIDs {
+			results[i] = &dataloader.Result{Data: starred[id], Error: err}
+		}
+		return results
+	}
+}
+
 // context key
 var loadersKey = &struct{}{}
 
 // Middleware: 將 dataloader 注入 context
-func DataloaderMiddleware(cardService services.CardService) func(ctx context.Context) context.Context {
+func DataloaderMiddleware(boardService services.BoardService, cardService services.CardService, attachmentService services.AttachmentService, customFieldService services.CustomFieldService, cardRelationService services.CardRelationService, watchService services.WatchService, timeTrackingService services.TimeTrackingService, recurrenceService services.RecurrenceService, voteService services.VoteService, userBoardService services.UserBoardService) func(ctx context.Context) context.Context {
 	return func(ctx context.Context) context.Context {
 		loaders := &Loaders{
-			CardsByListID: dataloader.NewBatchedLoader(CardsBatchFn(cardService)),
+			CardsByListID:             dataloader.NewBatchedLoader(CardsBatchFn(cardService)),
+			AttachmentsByCardID:       dataloader.NewBatchedLoader(AttachmentsBatchFn(attachmentService)),
+			CustomFieldValuesByCardID: dataloader.NewBatchedLoader(CustomFieldValuesBatchFn(customFieldService)),
+			CardRelationsByCardID:     dataloader.NewBatchedLoader(CardRelationsBatchFn(cardRelationService)),
+			WatchingByKey:             dataloader.NewBatchedLoader(WatchingBatchFn(watchService)),
+			EstimateTotalByListID:     dataloader.NewBatchedLoader(EstimateTotalsBatchFn(cardService)),
+			EstimateTotalByBoardID:    dataloader.NewBatchedLoader(BoardEstimateTotalsBatchFn(cardService)),
+			TimeEntriesByCardID:       dataloader.NewBatchedLoader(TimeEntriesBatchFn(timeTrackingService)),
+			TimeSpentByCardID:         dataloader.NewBatchedLoader(TimeSpentBatchFn(timeTrackingService)),
+			RecurrenceByCardID:        dataloader.NewBatchedLoader(RecurrenceBatchFn(recurrenceService)),
+			BoardKeyByID:              dataloader.NewBatchedLoader(BoardKeyBatchFn(boardService)),
+			RevisionsByCardID:         dataloader.NewBatchedLoader(RevisionsBatchFn(cardService)),
+			VoteCountByCardID:         dataloader.NewBatchedLoader(VoteCountsBatchFn(voteService)),
+			ViewerVotedByCardID:       dataloader.NewBatchedLoader(ViewerVotedBatchFn(voteService)),
+			StarredByBoardID:          dataloader.NewBatchedLoader(StarredBatchFn(userBoardService)),
 		}
 		return context.WithValue(ctx, loadersKey, loaders)
 	}
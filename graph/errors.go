@@ -0,0 +1,27 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"trello-backend/internal/services"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrorPresenter 將 service 的型別化錯誤加上 extensions.code 等資訊，讓前端可以辨識並顯示
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	var wipErr *services.WipLimitError
+	if errors.As(err, &wipErr) {
+		gqlErr.Extensions = map[string]interface{}{
+			"code":   "WIP_LIMIT_EXCEEDED",
+			"listId": strconv.FormatUint(uint64(wipErr.ListID), 10),
+			"limit":  wipErr.Limit,
+			"count":  wipErr.Count,
+		}
+	}
+	return gqlErr
+}
@@ -4,8 +4,9 @@ import (
 	"context"
 	"errors"
 	"strconv"
+	"strings"
 	"trello-backend/graph/model"
-	"trello-backend/pkg/utils"
+	"trello-backend/internal/models"
 
 	"github.com/graph-gophers/dataloader"
 )
@@ -21,14 +22,7 @@ func (r *mutationResolver) CreateList(ctx context.Context, input model.CreateLis
 	if err != nil {
 		return nil, err
 	}
-	return &model.List{
-		ID:        strconv.FormatUint(uint64(l.ID), 10),
-		Name:      l.Name,
-		BoardID:   strconv.FormatUint(uint64(l.BoardID), 10),
-		CreatedAt: l.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: l.UpdatedAt.Format(utils.TimeFormat),
-		Position:  int32(l.Position),
-	}, nil
+	return toModelList(l), nil
 }
 
 func (r *mutationResolver) UpdateList(ctx context.Context, input model.UpdateListInput) (*model.List, error) {
@@ -44,22 +38,55 @@ func (r *mutationResolver) UpdateList(ctx context.Context, input model.UpdateLis
 	if err != nil {
 		return nil, err
 	}
-	return &model.List{
-		ID:        strconv.FormatUint(uint64(l.ID), 10),
-		Name:      l.Name,
-		BoardID:   strconv.FormatUint(uint64(l.BoardID), 10),
-		CreatedAt: l.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: l.UpdatedAt.Format(utils.TimeFormat),
-		Position:  int32(l.Position),
-	}, nil
+	return toModelList(l), nil
+}
+
+func (r *mutationResolver) SetListDone(ctx context.Context, id string, isDone bool) (*model.List, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	lid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	l, err := r.ListService.SetListDone(userID, uint(lid), isDone)
+	if err != nil {
+		return nil, err
+	}
+	return toModelList(l), nil
+}
+
+func (r *mutationResolver) SetListWipLimit(ctx context.Context, input model.SetListWipLimitInput) (*model.List, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	lid, err := strconv.ParseUint(input.ID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	mode := models.WipLimitWarn
+	if input.Mode != nil {
+		mode = models.WipLimitMode(strings.ToLower(input.Mode.String()))
+	}
+	l, err := r.ListService.SetWipLimit(userID, uint(lid), int32ToIntPtr(input.Limit), mode)
+	if err != nil {
+		return nil, err
+	}
+	return toModelList(l), nil
 }
 
 func (r *mutationResolver) DeleteList(ctx context.Context, id string) (bool, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return false, errors.New("未驗證身份")
+	}
 	lid, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
 		return false, err
 	}
-	err = r.ListService.DeleteList(uint(lid))
+	err = r.ListService.DeleteList(userID, uint(lid))
 	return err == nil, err
 }
 
@@ -76,56 +103,71 @@ func (r *mutationResolver) MoveList(ctx context.Context, input model.MoveListInp
 	if err != nil {
 		return nil, err
 	}
-	return &model.List{
-		ID:        strconv.FormatUint(uint64(l.ID), 10),
-		Name:      l.Name,
-		BoardID:   strconv.FormatUint(uint64(l.BoardID), 10),
-		CreatedAt: l.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: l.UpdatedAt.Format(utils.TimeFormat),
-		Position:  int32(l.Position),
-	}, nil
+	return toModelList(l), nil
+}
+
+func (r *mutationResolver) ArchiveList(ctx context.Context, id string) (*model.List, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	lid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	l, err := r.ListService.ArchiveList(userID, uint(lid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelList(l), nil
+}
+
+func (r *mutationResolver) UnarchiveList(ctx context.Context, id string) (*model.List, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	lid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	l, err := r.ListService.UnarchiveList(userID, uint(lid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelList(l), nil
 }
 
 func (r *queryResolver) Lists(ctx context.Context, boardID string) ([]*model.List, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
 	bid, err := strconv.ParseUint(boardID, 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	lists, err := r.ListService.GetLists(uint(bid))
+	lists, err := r.ListService.GetReadableLists(userID, uint(bid))
 	if err != nil {
 		return nil, err
 	}
-	result := make([]*model.List, 0, len(lists))
-	for _, l := range lists {
-		result = append(result, &model.List{
-			ID:        strconv.FormatUint(uint64(l.ID), 10),
-			Name:      l.Name,
-			BoardID:   strconv.FormatUint(uint64(l.BoardID), 10),
-			CreatedAt: l.CreatedAt.Format(utils.TimeFormat),
-			UpdatedAt: l.UpdatedAt.Format(utils.TimeFormat),
-			Position:  int32(l.Position),
-		})
-	}
-	return result, nil
+	return toModelLists(lists), nil
 }
 
 func (r *queryResolver) List(ctx context.Context, id string) (*model.List, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
 	listID, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	l, err := r.ListService.GetListByID(uint(listID))
+	l, err := r.ListService.GetReadableList(userID, uint(listID))
 	if err != nil {
 		return nil, err
 	}
-	return &model.List{
-		ID:        strconv.FormatUint(uint64(l.ID), 10),
-		Name:      l.Name,
-		BoardID:   strconv.FormatUint(uint64(l.BoardID), 10),
-		CreatedAt: l.CreatedAt.Format(utils.TimeFormat),
-		UpdatedAt: l.UpdatedAt.Format(utils.TimeFormat),
-		Position:  int32(l.Position),
-	}, nil
+	return toModelList(l), nil
 }
 
 // Cards is the resolver for the cards field.
@@ -145,3 +187,46 @@ func (r *listResolver) Cards(ctx context.Context, obj *model.List) ([]*model.Car
 	}
 	return cards, nil
 }
+
+func (r *listResolver) EstimateTotal(ctx context.Context, obj *model.List) (float64, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return 0, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.EstimateTotalByListID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return 0, err
+	}
+	total, ok := result.(float64)
+	if !ok {
+		return 0, errors.New("unexpected dataloader result type")
+	}
+	return total, nil
+}
+
+// CardCount 以已載入的卡片計算，不含範本卡片
+func (r *listResolver) CardCount(ctx context.Context, obj *model.List) (int32, error) {
+	cards, err := r.Cards(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+	count := int32(0)
+	for _, c := range cards {
+		if !c.IsTemplate {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *listResolver) OverWipLimit(ctx context.Context, obj *model.List) (bool, error) {
+	if obj.WipLimit == nil {
+		return false, nil
+	}
+	count, err := r.CardCount(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+	return count > *obj.WipLimit, nil
+}
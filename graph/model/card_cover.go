@@ -0,0 +1,8 @@
+package model
+
+// CardCover 卡片封面，attachment 由 resolver 透過 dataloader 載入
+type CardCover struct {
+	CardID       string  `json:"-"`
+	AttachmentID *string `json:"-"`
+	Color        *string `json:"color,omitempty"`
+}
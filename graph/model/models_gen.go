@@ -2,36 +2,201 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+type AddTimeEntryInput struct {
+	CardID          string     `json:"cardId"`
+	StartedAt       time.Time  `json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt,omitempty"`
+	DurationMinutes *int32     `json:"durationMinutes,omitempty"`
+	Note            *string    `json:"note,omitempty"`
+}
+
+type ArchivedItems struct {
+	Lists []*List `json:"lists"`
+	Cards []*Card `json:"cards"`
+}
+
+type Attachment struct {
+	ID           string  `json:"id"`
+	CardID       string  `json:"cardId"`
+	FileName     string  `json:"fileName"`
+	ContentType  string  `json:"contentType"`
+	Size         int32   `json:"size"`
+	URL          string  `json:"url"`
+	ThumbnailURL *string `json:"thumbnailUrl,omitempty"`
+	CreatedAt    string  `json:"createdAt"`
+}
+
 type Board struct {
-	ID        string  `json:"id"`
-	Name      string  `json:"name"`
-	Position  int32   `json:"position"`
-	CreatedAt string  `json:"createdAt"`
-	UpdatedAt string  `json:"updatedAt"`
-	Lists     []*List `json:"lists"`
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Key           string         `json:"key"`
+	Position      int32          `json:"position"`
+	CreatedAt     string         `json:"createdAt"`
+	UpdatedAt     string         `json:"updatedAt"`
+	ArchivedAt    *time.Time     `json:"archivedAt,omitempty"`
+	DeletedAt     *time.Time     `json:"deletedAt,omitempty"`
+	ClosedAt      *time.Time     `json:"closedAt,omitempty"`
+	Lists         []*List        `json:"lists"`
+	CustomFields  []*CustomField `json:"customFields"`
+	IsWatching    bool           `json:"isWatching"`
+	EstimateTotal float64        `json:"estimateTotal"`
+	Settings      *BoardSettings `json:"settings"`
+	IsStarred     bool           `json:"isStarred"`
+	IsTemplate    bool           `json:"isTemplate"`
+}
+
+type BoardSettings struct {
+	Description        string          `json:"description"`
+	BackgroundColor    *string         `json:"backgroundColor,omitempty"`
+	BackgroundImageURL *string         `json:"backgroundImageUrl,omitempty"`
+	Visibility         BoardVisibility `json:"visibility"`
+	CommentsEnabled    bool            `json:"commentsEnabled"`
+	VotingEnabled      bool            `json:"votingEnabled"`
+	CardCoversEnabled  bool            `json:"cardCoversEnabled"`
+}
+
+type BoardSettingsInput struct {
+	Description        *string          `json:"description,omitempty"`
+	BackgroundColor    *string          `json:"backgroundColor,omitempty"`
+	BackgroundImageURL *string          `json:"backgroundImageUrl,omitempty"`
+	Visibility         *BoardVisibility `json:"visibility,omitempty"`
+	CommentsEnabled    *bool            `json:"commentsEnabled,omitempty"`
+	VotingEnabled      *bool            `json:"votingEnabled,omitempty"`
+	CardCoversEnabled  *bool            `json:"cardCoversEnabled,omitempty"`
+}
+
+type BulkCardResult struct {
+	CardID  string  `json:"cardId"`
+	Success bool    `json:"success"`
+	Error   *string `json:"error,omitempty"`
+	Card    *Card   `json:"card,omitempty"`
 }
 
 type Card struct {
-	ID        string  `json:"id"`
-	Title     string  `json:"title"`
-	Content   *string `json:"content,omitempty"`
-	ListID    string  `json:"listId"`
-	BoardID   string  `json:"boardId"`
-	CreatedAt string  `json:"createdAt"`
-	UpdatedAt string  `json:"updatedAt"`
-	Position  int32   `json:"position"`
+	ID                string              `json:"id"`
+	Title             string              `json:"title"`
+	Content           *string             `json:"content,omitempty"`
+	ContentHTML       *string             `json:"contentHtml,omitempty"`
+	ListID            string              `json:"listId"`
+	BoardID           string              `json:"boardId"`
+	Number            int32               `json:"number"`
+	Reference         string              `json:"reference"`
+	CreatedAt         string              `json:"createdAt"`
+	UpdatedAt         string              `json:"updatedAt"`
+	Position          int32               `json:"position"`
+	StartAt           *time.Time          `json:"startAt,omitempty"`
+	DueAt             *time.Time          `json:"dueAt,omitempty"`
+	CompletedAt       *time.Time          `json:"completedAt,omitempty"`
+	ReminderMinutes   *int32              `json:"reminderMinutes,omitempty"`
+	Attachments       []*Attachment       `json:"attachments"`
+	Cover             *CardCover          `json:"cover,omitempty"`
+	ArchivedAt        *time.Time          `json:"archivedAt,omitempty"`
+	DeletedAt         *time.Time          `json:"deletedAt,omitempty"`
+	CustomFieldValues []*CustomFieldValue `json:"customFieldValues"`
+	Blocks            []*Card             `json:"blocks"`
+	BlockedBy         []*Card             `json:"blockedBy"`
+	RelatedCards      []*Card             `json:"relatedCards"`
+	HasOpenBlockers   bool                `json:"hasOpenBlockers"`
+	BlockedWarning    bool                `json:"blockedWarning"`
+	IsTemplate        bool                `json:"isTemplate"`
+	IsWatching        bool                `json:"isWatching"`
+	Priority          CardPriority        `json:"priority"`
+	Estimate          *float64            `json:"estimate,omitempty"`
+	TimeEntries       []*TimeEntry        `json:"timeEntries"`
+	TotalTimeSpent    int32               `json:"totalTimeSpent"`
+	Recurrence        *CardRecurrence     `json:"recurrence,omitempty"`
+	Revisions         []*CardRevision     `json:"revisions"`
+	VoteCount         int32               `json:"voteCount"`
+	ViewerHasVoted    bool                `json:"viewerHasVoted"`
+}
+
+type CardRecurrence struct {
+	Rule             string     `json:"rule"`
+	ListID           string     `json:"listId"`
+	StartAt          time.Time  `json:"startAt"`
+	NextRunAt        *time.Time `json:"nextRunAt,omitempty"`
+	LastOccurrenceAt *time.Time `json:"lastOccurrenceAt,omitempty"`
+	LastCardID       *string    `json:"lastCardId,omitempty"`
+	OccurrenceCount  int32      `json:"occurrenceCount"`
+}
+
+type CardRelationInput struct {
+	FromCardID string           `json:"fromCardId"`
+	ToCardID   string           `json:"toCardId"`
+	Type       CardRelationType `json:"type"`
+}
+
+type CardRevision struct {
+	ID         string    `json:"id"`
+	CardID     string    `json:"cardId"`
+	UserID     string    `json:"userId"`
+	OldTitle   string    `json:"oldTitle"`
+	NewTitle   string    `json:"newTitle"`
+	OldContent *string   `json:"oldContent,omitempty"`
+	NewContent *string   `json:"newContent,omitempty"`
+	Diff       string    `json:"diff"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type CopyBoardInput struct {
+	BoardID string            `json:"boardId"`
+	Name    *string           `json:"name,omitempty"`
+	Options *CopyOptionsInput `json:"options,omitempty"`
+}
+
+type CopyCardInput struct {
+	CardID       string            `json:"cardId"`
+	TargetListID string            `json:"targetListId"`
+	Position     *int32            `json:"position,omitempty"`
+	Options      *CopyOptionsInput `json:"options,omitempty"`
+}
+
+type CopyListInput struct {
+	ListID        string            `json:"listId"`
+	TargetBoardID string            `json:"targetBoardId"`
+	Name          *string           `json:"name,omitempty"`
+	Position      *int32            `json:"position,omitempty"`
+	Options       *CopyOptionsInput `json:"options,omitempty"`
+}
+
+type CopyOptionsInput struct {
+	Attachments       *bool `json:"attachments,omitempty"`
+	CustomFieldValues *bool `json:"customFieldValues,omitempty"`
 }
 
 type CreateBoardInput struct {
-	Name     string `json:"name"`
-	Position *int32 `json:"position,omitempty"`
+	Name     string  `json:"name"`
+	Key      *string `json:"key,omitempty"`
+	Position *int32  `json:"position,omitempty"`
 }
 
 type CreateCardInput struct {
-	ListID  string  `json:"listId"`
-	Title   string  `json:"title"`
-	Content *string `json:"content,omitempty"`
-	BoardID string  `json:"boardId"`
+	ListID          string        `json:"listId"`
+	Title           string        `json:"title"`
+	Content         *string       `json:"content,omitempty"`
+	BoardID         *string       `json:"boardId,omitempty"`
+	StartAt         *time.Time    `json:"startAt,omitempty"`
+	DueAt           *time.Time    `json:"dueAt,omitempty"`
+	CompletedAt     *time.Time    `json:"completedAt,omitempty"`
+	ReminderMinutes *int32        `json:"reminderMinutes,omitempty"`
+	Priority        *CardPriority `json:"priority,omitempty"`
+	Estimate        *float64      `json:"estimate,omitempty"`
+}
+
+type CreateCustomFieldInput struct {
+	BoardID string          `json:"boardId"`
+	Name    string          `json:"name"`
+	Type    CustomFieldType `json:"type"`
+	Options []string        `json:"options,omitempty"`
 }
 
 type CreateListInput struct {
@@ -39,14 +204,63 @@ type CreateListInput struct {
 	Name    string `json:"name"`
 }
 
+type CustomField struct {
+	ID       string               `json:"id"`
+	BoardID  string               `json:"boardId"`
+	Name     string               `json:"name"`
+	Type     CustomFieldType      `json:"type"`
+	Position int32                `json:"position"`
+	Options  []*CustomFieldOption `json:"options"`
+}
+
+type CustomFieldFilterInput struct {
+	FieldID   string     `json:"fieldId"`
+	Text      *string    `json:"text,omitempty"`
+	Min       *float64   `json:"min,omitempty"`
+	Max       *float64   `json:"max,omitempty"`
+	From      *time.Time `json:"from,omitempty"`
+	To        *time.Time `json:"to,omitempty"`
+	Checked   *bool      `json:"checked,omitempty"`
+	OptionIds []string   `json:"optionIds,omitempty"`
+	IsEmpty   *bool      `json:"isEmpty,omitempty"`
+}
+
+type CustomFieldOption struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+type CustomFieldOptionInput struct {
+	ID    *string `json:"id,omitempty"`
+	Label string  `json:"label"`
+}
+
+type CustomFieldValue struct {
+	Field   *CustomField       `json:"field"`
+	Text    *string            `json:"text,omitempty"`
+	Number  *float64           `json:"number,omitempty"`
+	Date    *time.Time         `json:"date,omitempty"`
+	Checked *bool              `json:"checked,omitempty"`
+	Option  *CustomFieldOption `json:"option,omitempty"`
+}
+
 type List struct {
-	ID        string  `json:"id"`
-	Name      string  `json:"name"`
-	BoardID   string  `json:"boardId"`
-	CreatedAt string  `json:"createdAt"`
-	UpdatedAt string  `json:"updatedAt"`
-	Position  int32   `json:"position"`
-	Cards     []*Card `json:"cards"`
+	ID            string       `json:"id"`
+	Name          string       `json:"name"`
+	BoardID       string       `json:"boardId"`
+	CreatedAt     string       `json:"createdAt"`
+	UpdatedAt     string       `json:"updatedAt"`
+	Position      int32        `json:"position"`
+	IsDone        bool         `json:"isDone"`
+	ArchivedAt    *time.Time   `json:"archivedAt,omitempty"`
+	DeletedAt     *time.Time   `json:"deletedAt,omitempty"`
+	Cards         []*Card      `json:"cards"`
+	IsWatching    bool         `json:"isWatching"`
+	EstimateTotal float64      `json:"estimateTotal"`
+	WipLimit      *int32       `json:"wipLimit,omitempty"`
+	WipMode       WipLimitMode `json:"wipMode"`
+	CardCount     int32        `json:"cardCount"`
+	OverWipLimit  bool         `json:"overWipLimit"`
 }
 
 type MoveBoardInput struct {
@@ -60,6 +274,11 @@ type MoveCardInput struct {
 	NewPosition  int32  `json:"newPosition"`
 }
 
+type MoveCardsInput struct {
+	CardIds      []string `json:"cardIds"`
+	TargetListID string   `json:"targetListId"`
+}
+
 type MoveListInput struct {
 	ID          string `json:"id"`
 	NewPosition int32  `json:"newPosition"`
@@ -68,21 +287,569 @@ type MoveListInput struct {
 type Mutation struct {
 }
 
+type Notification struct {
+	ID        string           `json:"id"`
+	Type      NotificationType `json:"type"`
+	BoardID   string           `json:"boardId"`
+	CardID    string           `json:"cardId"`
+	Message   string           `json:"message"`
+	ReadAt    *time.Time       `json:"readAt,omitempty"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
 type Query struct {
 }
 
+type SetCardCoverInput struct {
+	CardID       string  `json:"cardId"`
+	AttachmentID *string `json:"attachmentId,omitempty"`
+	Color        *string `json:"color,omitempty"`
+}
+
+type SetCardRecurrenceInput struct {
+	CardID  string    `json:"cardId"`
+	ListID  string    `json:"listId"`
+	Rule    string    `json:"rule"`
+	StartAt time.Time `json:"startAt"`
+}
+
+type SetCustomFieldValueInput struct {
+	CardID   string     `json:"cardId"`
+	FieldID  string     `json:"fieldId"`
+	Text     *string    `json:"text,omitempty"`
+	Number   *float64   `json:"number,omitempty"`
+	Date     *time.Time `json:"date,omitempty"`
+	Checked  *bool      `json:"checked,omitempty"`
+	OptionID *string    `json:"optionId,omitempty"`
+}
+
+type SetListWipLimitInput struct {
+	ID    string        `json:"id"`
+	Limit *int32        `json:"limit,omitempty"`
+	Mode  *WipLimitMode `json:"mode,omitempty"`
+}
+
+type TimeEntry struct {
+	ID              string     `json:"id"`
+	CardID          string     `json:"cardId"`
+	UserID          string     `json:"userId"`
+	StartedAt       time.Time  `json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt,omitempty"`
+	DurationSeconds int32      `json:"durationSeconds"`
+	Note            *string    `json:"note,omitempty"`
+}
+
+type TimeReportRow struct {
+	UserID       string `json:"userId"`
+	UserName     string `json:"userName"`
+	TotalSeconds int32  `json:"totalSeconds"`
+	EntryCount   int32  `json:"entryCount"`
+}
+
+type TrashedItems struct {
+	Lists []*List `json:"lists"`
+	Cards []*Card `json:"cards"`
+}
+
 type UpdateBoardInput struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID       string              `json:"id"`
+	Name     *string             `json:"name,omitempty"`
+	Key      *string             `json:"key,omitempty"`
+	Settings *BoardSettingsInput `json:"settings,omitempty"`
 }
 
 type UpdateCardInput struct {
-	ID      string  `json:"id"`
-	Title   string  `json:"title"`
-	Content *string `json:"content,omitempty"`
+	ID              string                           `json:"id"`
+	Title           string                           `json:"title"`
+	Content         *string                          `json:"content,omitempty"`
+	StartAt         graphql.Omittable[*time.Time]    `json:"startAt,omitempty"`
+	DueAt           graphql.Omittable[*time.Time]    `json:"dueAt,omitempty"`
+	CompletedAt     graphql.Omittable[*time.Time]    `json:"completedAt,omitempty"`
+	ReminderMinutes graphql.Omittable[*int32]        `json:"reminderMinutes,omitempty"`
+	Priority        graphql.Omittable[*CardPriority] `json:"priority,omitempty"`
+	Estimate        graphql.Omittable[*float64]      `json:"estimate,omitempty"`
+}
+
+type UpdateCustomFieldInput struct {
+	ID      string                    `json:"id"`
+	Name    string                    `json:"name"`
+	Options []*CustomFieldOptionInput `json:"options,omitempty"`
 }
 
 type UpdateListInput struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
+
+type BoardVisibility string
+
+const (
+	BoardVisibilityPrivate   BoardVisibility = "PRIVATE"
+	BoardVisibilityWorkspace BoardVisibility = "WORKSPACE"
+	BoardVisibilityPublic    BoardVisibility = "PUBLIC"
+)
+
+var AllBoardVisibility = []BoardVisibility{
+	BoardVisibilityPrivate,
+	BoardVisibilityWorkspace,
+	BoardVisibilityPublic,
+}
+
+func (e BoardVisibility) IsValid() bool {
+	switch e {
+	case BoardVisibilityPrivate, BoardVisibilityWorkspace, BoardVisibilityPublic:
+		return true
+	}
+	return false
+}
+
+func (e BoardVisibility) String() string {
+	return string(e)
+}
+
+func (e *BoardVisibility) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BoardVisibility(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BoardVisibility", str)
+	}
+	return nil
+}
+
+func (e BoardVisibility) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CardPriority string
+
+const (
+	CardPriorityNone   CardPriority = "NONE"
+	CardPriorityLow    CardPriority = "LOW"
+	CardPriorityMedium CardPriority = "MEDIUM"
+	CardPriorityHigh   CardPriority = "HIGH"
+	CardPriorityUrgent CardPriority = "URGENT"
+)
+
+var AllCardPriority = []CardPriority{
+	CardPriorityNone,
+	CardPriorityLow,
+	CardPriorityMedium,
+	CardPriorityHigh,
+	CardPriorityUrgent,
+}
+
+func (e CardPriority) IsValid() bool {
+	switch e {
+	case CardPriorityNone, CardPriorityLow, CardPriorityMedium, CardPriorityHigh, CardPriorityUrgent:
+		return true
+	}
+	return false
+}
+
+func (e CardPriority) String() string {
+	return string(e)
+}
+
+func (e *CardPriority) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CardPriority(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CardPriority", str)
+	}
+	return nil
+}
+
+func (e CardPriority) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CardRelationType string
+
+const (
+	CardRelationTypeBlocks  CardRelationType = "BLOCKS"
+	CardRelationTypeRelates CardRelationType = "RELATES"
+)
+
+var AllCardRelationType = []CardRelationType{
+	CardRelationTypeBlocks,
+	CardRelationTypeRelates,
+}
+
+func (e CardRelationType) IsValid() bool {
+	switch e {
+	case CardRelationTypeBlocks, CardRelationTypeRelates:
+		return true
+	}
+	return false
+}
+
+func (e CardRelationType) String() string {
+	return string(e)
+}
+
+func (e *CardRelationType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CardRelationType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CardRelationType", str)
+	}
+	return nil
+}
+
+func (e CardRelationType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CardSortField string
+
+const (
+	CardSortFieldPosition CardSortField = "POSITION"
+	CardSortFieldPriority CardSortField = "PRIORITY"
+)
+
+var AllCardSortField = []CardSortField{
+	CardSortFieldPosition,
+	CardSortFieldPriority,
+}
+
+func (e CardSortField) IsValid() bool {
+	switch e {
+	case CardSortFieldPosition, CardSortFieldPriority:
+		return true
+	}
+	return false
+}
+
+func (e CardSortField) String() string {
+	return string(e)
+}
+
+func (e *CardSortField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CardSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CardSortField", str)
+	}
+	return nil
+}
+
+func (e CardSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CustomFieldType string
+
+const (
+	CustomFieldTypeText     CustomFieldType = "TEXT"
+	CustomFieldTypeNumber   CustomFieldType = "NUMBER"
+	CustomFieldTypeDate     CustomFieldType = "DATE"
+	CustomFieldTypeCheckbox CustomFieldType = "CHECKBOX"
+	CustomFieldTypeDropdown CustomFieldType = "DROPDOWN"
+)
+
+var AllCustomFieldType = []CustomFieldType{
+	CustomFieldTypeText,
+	CustomFieldTypeNumber,
+	CustomFieldTypeDate,
+	CustomFieldTypeCheckbox,
+	CustomFieldTypeDropdown,
+}
+
+func (e CustomFieldType) IsValid() bool {
+	switch e {
+	case CustomFieldTypeText, CustomFieldTypeNumber, CustomFieldTypeDate, CustomFieldTypeCheckbox, CustomFieldTypeDropdown:
+		return true
+	}
+	return false
+}
+
+func (e CustomFieldType) String() string {
+	return string(e)
+}
+
+func (e *CustomFieldType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CustomFieldType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CustomFieldType", str)
+	}
+	return nil
+}
+
+func (e CustomFieldType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ListSortField string
+
+const (
+	ListSortFieldDueDate   ListSortField = "DUE_DATE"
+	ListSortFieldCreatedAt ListSortField = "CREATED_AT"
+	ListSortFieldTitle     ListSortField = "TITLE"
+	ListSortFieldPriority  ListSortField = "PRIORITY"
+)
+
+var AllListSortField = []ListSortField{
+	ListSortFieldDueDate,
+	ListSortFieldCreatedAt,
+	ListSortFieldTitle,
+	ListSortFieldPriority,
+}
+
+func (e ListSortField) IsValid() bool {
+	switch e {
+	case ListSortFieldDueDate, ListSortFieldCreatedAt, ListSortFieldTitle, ListSortFieldPriority:
+		return true
+	}
+	return false
+}
+
+func (e ListSortField) String() string {
+	return string(e)
+}
+
+func (e *ListSortField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ListSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ListSortField", str)
+	}
+	return nil
+}
+
+func (e ListSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type NotificationType string
+
+const (
+	NotificationTypeCardMoved      NotificationType = "CARD_MOVED"
+	NotificationTypeDueDateChanged NotificationType = "DUE_DATE_CHANGED"
+)
+
+var AllNotificationType = []NotificationType{
+	NotificationTypeCardMoved,
+	NotificationTypeDueDateChanged,
+}
+
+func (e NotificationType) IsValid() bool {
+	switch e {
+	case NotificationTypeCardMoved, NotificationTypeDueDateChanged:
+		return true
+	}
+	return false
+}
+
+func (e NotificationType) String() string {
+	return string(e)
+}
+
+func (e *NotificationType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NotificationType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NotificationType", str)
+	}
+	return nil
+}
+
+func (e NotificationType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortDirection string
+
+const (
+	SortDirectionAsc  SortDirection = "ASC"
+	SortDirectionDesc SortDirection = "DESC"
+)
+
+var AllSortDirection = []SortDirection{
+	SortDirectionAsc,
+	SortDirectionDesc,
+}
+
+func (e SortDirection) IsValid() bool {
+	switch e {
+	case SortDirectionAsc, SortDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortDirection) String() string {
+	return string(e)
+}
+
+func (e *SortDirection) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortDirection", str)
+	}
+	return nil
+}
+
+func (e SortDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TrashItemType string
+
+const (
+	TrashItemTypeBoard TrashItemType = "BOARD"
+	TrashItemTypeList  TrashItemType = "LIST"
+	TrashItemTypeCard  TrashItemType = "CARD"
+)
+
+var AllTrashItemType = []TrashItemType{
+	TrashItemTypeBoard,
+	TrashItemTypeList,
+	TrashItemTypeCard,
+}
+
+func (e TrashItemType) IsValid() bool {
+	switch e {
+	case TrashItemTypeBoard, TrashItemTypeList, TrashItemTypeCard:
+		return true
+	}
+	return false
+}
+
+func (e TrashItemType) String() string {
+	return string(e)
+}
+
+func (e *TrashItemType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TrashItemType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TrashItemType", str)
+	}
+	return nil
+}
+
+func (e TrashItemType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type WatchableType string
+
+const (
+	WatchableTypeBoard WatchableType = "BOARD"
+	WatchableTypeList  WatchableType = "LIST"
+	WatchableTypeCard  WatchableType = "CARD"
+)
+
+var AllWatchableType = []WatchableType{
+	WatchableTypeBoard,
+	WatchableTypeList,
+	WatchableTypeCard,
+}
+
+func (e WatchableType) IsValid() bool {
+	switch e {
+	case WatchableTypeBoard, WatchableTypeList, WatchableTypeCard:
+		return true
+	}
+	return false
+}
+
+func (e WatchableType) String() string {
+	return string(e)
+}
+
+func (e *WatchableType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WatchableType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WatchableType", str)
+	}
+	return nil
+}
+
+func (e WatchableType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type WipLimitMode string
+
+const (
+	WipLimitModeWarn  WipLimitMode = "WARN"
+	WipLimitModeBlock WipLimitMode = "BLOCK"
+)
+
+var AllWipLimitMode = []WipLimitMode{
+	WipLimitModeWarn,
+	WipLimitModeBlock,
+}
+
+func (e WipLimitMode) IsValid() bool {
+	switch e {
+	case WipLimitModeWarn, WipLimitModeBlock:
+		return true
+	}
+	return false
+}
+
+func (e WipLimitMode) String() string {
+	return string(e)
+}
+
+func (e *WipLimitMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WipLimitMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WipLimitMode", str)
+	}
+	return nil
+}
+
+func (e WipLimitMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"trello-backend/graph/model"
+
+	"github.com/graph-gophers/dataloader"
+)
+
+// 週期性卡片相關 resolver function
+
+func (r *mutationResolver) SetCardRecurrence(ctx context.Context, input model.SetCardRecurrenceInput) (*model.CardRecurrence, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	cardID, err := strconv.ParseUint(input.CardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	listID, err := strconv.ParseUint(input.ListID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := r.RecurrenceService.SetRecurrence(userID, uint(cardID), uint(listID), input.Rule, input.StartAt)
+	if err != nil {
+		return nil, err
+	}
+	return toModelCardRecurrence(rec), nil
+}
+
+func (r *mutationResolver) RemoveCardRecurrence(ctx context.Context, cardID string) (bool, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return false, errors.New("未驗證身份")
+	}
+	cid, err := strconv.ParseUint(cardID, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	err = r.RecurrenceService.RemoveRecurrence(userID, uint(cid))
+	return err == nil, err
+}
+
+func (r *cardResolver) Recurrence(ctx context.Context, obj *model.Card) (*model.CardRecurrence, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return nil, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.RecurrenceByCardID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return nil, err
+	}
+	rec, ok := result.(*model.CardRecurrence)
+	if !ok {
+		return nil, errors.New("unexpected dataloader result type")
+	}
+	return rec, nil
+}
@@ -9,16 +9,36 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	BoardService services.BoardService
-	ListService  services.ListService
-	CardService  services.CardService
+	BoardService        services.BoardService
+	ListService         services.ListService
+	CardService         services.CardService
+	AttachmentService   services.AttachmentService
+	TrashService        services.TrashService
+	CustomFieldService  services.CustomFieldService
+	CardRelationService services.CardRelationService
+	CopyService         services.CopyService
+	WatchService        services.WatchService
+	TimeTrackingService services.TimeTrackingService
+	RecurrenceService   services.RecurrenceService
+	VoteService         services.VoteService
+	UserBoardService    services.UserBoardService
 }
 
-func NewResolver(boardService services.BoardService, listService services.ListService, cardService services.CardService) *Resolver {
+func NewResolver(boardService services.BoardService, listService services.ListService, cardService services.CardService, attachmentService services.AttachmentService, trashService services.TrashService, customFieldService services.CustomFieldService, cardRelationService services.CardRelationService, copyService services.CopyService, watchService services.WatchService, timeTrackingService services.TimeTrackingService, recurrenceService services.RecurrenceService, voteService services.VoteService, userBoardService services.UserBoardService) *Resolver {
 	return &Resolver{
-		BoardService: boardService,
-		ListService:  listService,
-		CardService:  cardService,
+		BoardService:        boardService,
+		ListService:         listService,
+		CardService:         cardService,
+		AttachmentService:   attachmentService,
+		TrashService:        trashService,
+		CustomFieldService:  customFieldService,
+		CardRelationService: cardRelationService,
+		CopyService:         copyService,
+		WatchService:        watchService,
+		TimeTrackingService: timeTrackingService,
+		RecurrenceService:   recurrenceService,
+		VoteService:         voteService,
+		UserBoardService:    userBoardService,
 	}
 }
 
@@ -26,10 +46,30 @@ func NewResolverFromAPI(api interface {
 	BoardService() services.BoardService
 	ListService() services.ListService
 	CardService() services.CardService
+	AttachmentService() services.AttachmentService
+	TrashService() services.TrashService
+	CustomFieldService() services.CustomFieldService
+	CardRelationService() services.CardRelationService
+	CopyService() services.CopyService
+	WatchService() services.WatchService
+	TimeTrackingService() services.TimeTrackingService
+	RecurrenceService() services.RecurrenceService
+	VoteService() services.VoteService
+	UserBoardService() services.UserBoardService
 }) *Resolver {
 	return &Resolver{
-		BoardService: api.BoardService(),
-		ListService:  api.ListService(),
-		CardService:  api.CardService(),
+		BoardService:        api.BoardService(),
+		ListService:         api.ListService(),
+		CardService:         api.CardService(),
+		AttachmentService:   api.AttachmentService(),
+		TrashService:        api.TrashService(),
+		CustomFieldService:  api.CustomFieldService(),
+		CardRelationService: api.CardRelationService(),
+		CopyService:         api.CopyService(),
+		WatchService:        api.WatchService(),
+		TimeTrackingService: api.TimeTrackingService(),
+		RecurrenceService:   api.RecurrenceService(),
+		VoteService:         api.VoteService(),
+		UserBoardService:    api.UserBoardService(),
 	}
 }
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"trello-backend/graph/model"
+
+	"github.com/graph-gophers/dataloader"
+)
+
+// 卡片修改紀錄相關 resolver function
+
+func (r *mutationResolver) RestoreCardRevision(ctx context.Context, revisionID string) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	id, err := strconv.ParseUint(revisionID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.CardService.RestoreRevision(userID, uint(id))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCard(c), nil
+}
+
+func (r *cardResolver) Revisions(ctx context.Context, obj *model.Card) ([]*model.CardRevision, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return nil, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.RevisionsByCardID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return nil, err
+	}
+	revisions, ok := result.([]*model.CardRevision)
+	if !ok {
+		return nil, errors.New("unexpected dataloader result type")
+	}
+	return revisions, nil
+}
@@ -11,6 +11,12 @@ import (
 // Board returns BoardResolver implementation.
 func (r *Resolver) Board() BoardResolver { return &boardResolver{r} }
 
+// Card returns CardResolver implementation.
+func (r *Resolver) Card() CardResolver { return &cardResolver{r} }
+
+// CardCover returns CardCoverResolver implementation.
+func (r *Resolver) CardCover() CardCoverResolver { return &cardCoverResolver{r} }
+
 // List returns ListResolver implementation.
 func (r *Resolver) List() ListResolver { return &listResolver{r} }
 
@@ -21,6 +27,8 @@ func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
 type boardResolver struct{ *Resolver }
+type cardResolver struct{ *Resolver }
+type cardCoverResolver struct{ *Resolver }
 type listResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+	"trello-backend/graph/model"
+	"trello-backend/internal/services"
+
+	"github.com/graph-gophers/dataloader"
+)
+
+// 時間紀錄相關 resolver function
+
+func (r *mutationResolver) AddTimeEntry(ctx context.Context, input model.AddTimeEntryInput) (*model.TimeEntry, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	cardID, err := strconv.ParseUint(input.CardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	details := services.TimeEntryDetails{
+		StartedAt: input.StartedAt,
+		EndedAt:   input.EndedAt,
+		Note:      ptrToStr(input.Note),
+	}
+	if input.DurationMinutes != nil {
+		duration := time.Duration(*input.DurationMinutes) * time.Minute
+		details.Duration = &duration
+	}
+	entry, err := r.TimeTrackingService.AddEntry(userID, uint(cardID), details)
+	if err != nil {
+		return nil, err
+	}
+	return toModelTimeEntry(entry), nil
+}
+
+func (r *mutationResolver) DeleteTimeEntry(ctx context.Context, id string) (bool, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return false, errors.New("未驗證身份")
+	}
+	entryID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	err = r.TimeTrackingService.DeleteEntry(userID, uint(entryID))
+	return err == nil, err
+}
+
+func (r *mutationResolver) StartTimer(ctx context.Context, cardID string, note *string) (*model.TimeEntry, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	cid, err := strconv.ParseUint(cardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := r.TimeTrackingService.StartTimer(userID, uint(cid), ptrToStr(note))
+	if err != nil {
+		return nil, err
+	}
+	return toModelTimeEntry(entry), nil
+}
+
+func (r *mutationResolver) StopTimer(ctx context.Context) (*model.TimeEntry, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	entry, err := r.TimeTrackingService.StopTimer(userID)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return toModelTimeEntry(entry), nil
+}
+
+func (r *queryResolver) RunningTimer(ctx context.Context) (*model.TimeEntry, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	entry, err := r.TimeTrackingService.GetRunningTimer(userID)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return toModelTimeEntry(entry), nil
+}
+
+func (r *queryResolver) TimeReport(ctx context.Context, boardID string, from time.Time, to time.Time) ([]*model.TimeReportRow, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(boardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.TimeTrackingService.GetBoardReport(userID, uint(bid), from, to)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.TimeReportRow, len(rows))
+	for i, row := range rows {
+		result[i] = &model.TimeReportRow{
+			UserID:       row.UserID,
+			UserName:     row.UserName,
+			TotalSeconds: int32(row.TotalSeconds),
+			EntryCount:   int32(row.EntryCount),
+		}
+	}
+	return result, nil
+}
+
+func (r *cardResolver) TimeEntries(ctx context.Context, obj *model.Card) ([]*model.TimeEntry, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return nil, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.TimeEntriesByCardID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return nil, err
+	}
+	entries, ok := result.([]*model.TimeEntry)
+	if !ok {
+		return nil, errors.New("unexpected dataloader result type")
+	}
+	return entries, nil
+}
+
+func (r *cardResolver) TotalTimeSpent(ctx context.Context, obj *model.Card) (int32, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return 0, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.TimeSpentByCardID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return 0, err
+	}
+	total, ok := result.(int32)
+	if !ok {
+		return 0, errors.New("unexpected dataloader result type")
+	}
+	return total, nil
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"trello-backend/graph/model"
+	"trello-backend/internal/services"
+)
+
+// 垃圾桶相關 resolver function
+
+func (r *mutationResolver) RestoreFromTrash(ctx context.Context, typeArg model.TrashItemType, id string) (bool, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return false, errors.New("未驗證身份")
+	}
+	itemID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	itemType := services.TrashItemType(strings.ToLower(typeArg.String()))
+	err = r.TrashService.Restore(userID, itemType, uint(itemID))
+	return err == nil, err
+}
+
+// TrashedItems 取得看板垃圾桶中的清單與卡片
+func (r *queryResolver) TrashedItems(ctx context.Context, boardID string) (*model.TrashedItems, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(boardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := r.TrashService.GetTrashedLists(userID, uint(bid))
+	if err != nil {
+		return nil, err
+	}
+	cards, err := r.TrashService.GetTrashedCards(userID, uint(bid))
+	if err != nil {
+		return nil, err
+	}
+	return &model.TrashedItems{
+		Lists: toModelLists(lists),
+		Cards: toModelCards(cards),
+	}, nil
+}
+
+func (r *queryResolver) TrashedBoards(ctx context.Context) ([]*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	boards, err := r.TrashService.GetTrashedBoards(userID)
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoards(boards), nil
+}
@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"trello-backend/graph/model"
+	"trello-backend/internal/models"
+
+	"github.com/graph-gophers/dataloader"
+)
+
+// 星號看板與最近瀏覽看板相關 resolver function
+
+func (r *mutationResolver) StarBoard(ctx context.Context, id string) (*model.Board, error) {
+	return r.changeStar(ctx, id, r.UserBoardService.StarBoard)
+}
+
+func (r *mutationResolver) UnstarBoard(ctx context.Context, id string) (*model.Board, error) {
+	return r.changeStar(ctx, id, r.UserBoardService.UnstarBoard)
+}
+
+func (r *mutationResolver) changeStar(ctx context.Context, id string, change func(string, uint) (*models.Board, error)) (*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	b, err := change(userID, uint(bid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoard(b), nil
+}
+
+func (r *mutationResolver) MoveStarredBoard(ctx context.Context, id string, position int32) ([]*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	boards, err := r.UserBoardService.MoveStarredBoard(userID, uint(bid), int(position))
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoards(boards), nil
+}
+
+func (r *queryResolver) StarredBoards(ctx context.Context) ([]*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	boards, err := r.UserBoardService.GetStarredBoards(userID)
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoards(boards), nil
+}
+
+func (r *queryResolver) RecentBoards(ctx context.Context) ([]*model.Board, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	boards, err := r.UserBoardService.GetRecentBoards(userID)
+	if err != nil {
+		return nil, err
+	}
+	return toModelBoards(boards), nil
+}
+
+func (r *boardResolver) IsStarred(ctx context.Context, obj *model.Board) (bool, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return false, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.StarredByBoardID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return false, err
+	}
+	starred, ok := result.(bool)
+	if !ok {
+		return false, errors.New("unexpected dataloader result type")
+	}
+	return starred, nil
+}
+
+// recordBoardView 記錄目前使用者瀏覽了看板；記錄失敗不影響查詢結果，僅記錄錯誤
+func (r *queryResolver) recordBoardView(ctx context.Context, boardID uint) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := r.UserBoardService.RecordView(userID, boardID); err != nil {
+		log.Printf("記錄看板瀏覽紀錄失敗: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"trello-backend/graph/model"
+
+	"github.com/graph-gophers/dataloader"
+)
+
+// 卡片投票相關 resolver function
+
+func (r *mutationResolver) ToggleCardVote(ctx context.Context, cardID string) (*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	cid, err := strconv.ParseUint(cardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	c, err := r.VoteService.ToggleVote(userID, uint(cid))
+	if err != nil {
+		return nil, err
+	}
+	return toModelCard(c), nil
+}
+
+func (r *queryResolver) CardsByVotes(ctx context.Context, boardID string, limit *int32) ([]*model.Card, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	bid, err := strconv.ParseUint(boardID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+	if limit != nil {
+		n = int(*limit)
+	}
+	cards, err := r.VoteService.RankCardsByVotes(userID, uint(bid), n)
+	if err != nil {
+		return nil, err
+	}
+	return toModelCards(cards), nil
+}
+
+func (r *cardResolver) VoteCount(ctx context.Context, obj *model.Card) (int32, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return 0, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.VoteCountByCardID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return 0, err
+	}
+	count, ok := result.(int32)
+	if !ok {
+		return 0, errors.New("unexpected dataloader result type")
+	}
+	return count, nil
+}
+
+func (r *cardResolver) ViewerHasVoted(ctx context.Context, obj *model.Card) (bool, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return false, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.ViewerVotedByCardID.Load(ctx, dataloader.StringKey(obj.ID))
+	result, err := thunk()
+	if err != nil {
+		return false, err
+	}
+	voted, ok := result.(bool)
+	if !ok {
+		return false, errors.New("unexpected dataloader result type")
+	}
+	return voted, nil
+}
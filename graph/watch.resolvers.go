@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"trello-backend/graph/model"
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+
+	"github.com/graph-gophers/dataloader"
+)
+
+// 關注與通知相關 resolver function
+
+func (r *mutationResolver) Watch(ctx context.Context, typeArg model.WatchableType, id string) (bool, error) {
+	return r.changeWatch(ctx, typeArg, id, r.WatchService.Watch)
+}
+
+func (r *mutationResolver) Unwatch(ctx context.Context, typeArg model.WatchableType, id string) (bool, error) {
+	return r.changeWatch(ctx, typeArg, id, r.WatchService.Unwatch)
+}
+
+func (r *mutationResolver) changeWatch(ctx context.Context, typeArg model.WatchableType, id string, change func(string, models.WatchEntityType, uint) error) (bool, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return false, errors.New("未驗證身份")
+	}
+	entityID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	err = change(userID, models.WatchEntityType(strings.ToLower(typeArg.String())), uint(entityID))
+	return err == nil, err
+}
+
+func (r *mutationResolver) MarkNotificationRead(ctx context.Context, id string) (bool, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return false, errors.New("未驗證身份")
+	}
+	nid, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	err = r.WatchService.MarkNotificationRead(userID, uint(nid))
+	return err == nil, err
+}
+
+func (r *mutationResolver) MarkAllNotificationsRead(ctx context.Context) (bool, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return false, errors.New("未驗證身份")
+	}
+	err := r.WatchService.MarkAllNotificationsRead(userID)
+	return err == nil, err
+}
+
+func (r *queryResolver) Notifications(ctx context.Context, unreadOnly *bool) ([]*model.Notification, error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil, errors.New("未驗證身份")
+	}
+	notifications, err := r.WatchService.GetNotifications(userID, unreadOnly != nil && *unreadOnly)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.Notification, len(notifications))
+	for i := range notifications {
+		result[i] = toModelNotification(&notifications[i])
+	}
+	return result, nil
+}
+
+func (r *boardResolver) IsWatching(ctx context.Context, obj *model.Board) (bool, error) {
+	return loadWatching(ctx, models.WatchBoard, obj.ID)
+}
+
+func (r *listResolver) IsWatching(ctx context.Context, obj *model.List) (bool, error) {
+	return loadWatching(ctx, models.WatchList, obj.ID)
+}
+
+func (r *cardResolver) IsWatching(ctx context.Context, obj *model.Card) (bool, error) {
+	return loadWatching(ctx, models.WatchCard, obj.ID)
+}
+
+func loadWatching(ctx context.Context, entityType models.WatchEntityType, id string) (bool, error) {
+	loaders := For(ctx)
+	if loaders == nil {
+		return false, errors.New("dataloader not found in context")
+	}
+	thunk := loaders.WatchingByKey.Load(ctx, dataloader.StringKey(fmt.Sprintf("%s:%s", entityType, id)))
+	result, err := thunk()
+	if err != nil {
+		return false, err
+	}
+	watching, ok := result.(bool)
+	if !ok {
+		return false, errors.New("unexpected dataloader result type")
+	}
+	return watching, nil
+}
+
+// parseWatchKey 解析 "card:12" 格式的 dataloader key
+func parseWatchKey(key string) repositories.WatchTarget {
+	entityType, id, _ := strings.Cut(key, ":")
+	entityID, _ := strconv.ParseUint(id, 10, 64)
+	return repositories.WatchTarget{Type: models.WatchEntityType(entityType), ID: uint(entityID)}
+}
+
+// notifyWatchers 以目前使用者為觸發者發送通知；通知失敗不影響已完成的變更，僅記錄
+func notifyWatchers(ctx context.Context, notify func(actorID string) error) {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := notify(userID); err != nil {
+		log.Printf("發送關注通知失敗: %v", err)
+	}
+}
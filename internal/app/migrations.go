@@ -17,10 +17,52 @@ func Migrate(db *gorm.DB) {
 		&models.Board{},
 		&models.List{},
 		&models.Card{},
+		&models.Attachment{},
+		&models.CustomField{},
+		&models.CustomFieldOption{},
+		&models.CustomFieldValue{},
+		&models.CardRelation{},
+		&models.Watch{},
+		&models.Notification{},
+		&models.TimeEntry{},
+		&models.CardRecurrence{},
+		&models.CardRevision{},
+		&models.CardVote{},
+		&models.BoardStar{},
+		&models.BoardView{},
 	)
 	if err != nil {
 		log.Fatalf("Migration failed: %v", err)
 	}
+	if err := backfillCardReferences(db); err != nil {
+		log.Fatalf("Backfilling card references failed: %v", err)
+	}
 
 	log.Println("Migrations completed successfully.")
 }
+
+// backfillCardReferences 為加入卡片參照前建立的看板與卡片補上代號與編號，
+// 並在補齊後建立唯一索引。舊看板的代號為 B 加上看板 ID，卡片依建立順序編號
+func backfillCardReferences(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE boards SET key = 'B' || id WHERE key = ''`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`UPDATE cards c SET number = b.card_seq + n.rn
+			FROM (SELECT id, board_id, ROW_NUMBER() OVER (PARTITION BY board_id ORDER BY id) AS rn
+				FROM cards WHERE number = 0) n
+			JOIN boards b ON b.id = n.board_id
+			WHERE c.id = n.id`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`UPDATE boards b SET card_seq = m.max_number
+			FROM (SELECT board_id, MAX(number) AS max_number FROM cards GROUP BY board_id) m
+			WHERE b.id = m.board_id AND b.card_seq < m.max_number`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_boards_user_key ON boards (user_id, key)`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_cards_board_number ON cards (board_id, number)`).Error
+	})
+}
@@ -13,22 +13,45 @@ import (
 	"trello-backend/internal/handlers"
 	"trello-backend/internal/repositories"
 	"trello-backend/internal/services"
+	"trello-backend/internal/storage"
 )
 
 // Injectors from wire.go:
 
 // InitializeAPI 初始化 API 相依性
-func InitializeAPI(db *gorm.DB, jwtSecret string) (*API, error) {
+func InitializeAPI(db *gorm.DB, jwtSecret string, blobStore storage.BlobStore, attachmentOpts services.AttachmentOptions) (*API, error) {
 	userRepository := repositories.NewUserRepository(db)
 	authService := services.NewAuthService(userRepository, jwtSecret)
 	authHandler := handlers.NewAuthHandler(authService)
+	attachmentRepository := repositories.NewAttachmentRepository(db)
+	cardRepository := repositories.NewCardRepository(db)
 	boardRepository := repositories.NewBoardRepository(db)
+	attachmentService := services.NewAttachmentService(attachmentRepository, cardRepository, boardRepository, blobStore, attachmentOpts)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
 	boardService := services.NewBoardService(boardRepository)
 	listRepository := repositories.NewListRepository(db)
-	listService := services.NewListService(listRepository)
-	cardRepository := repositories.NewCardRepository(db)
-	cardService := services.NewCardService(cardRepository)
-	api := NewAPI(authHandler, boardService, listService, cardService)
+	listService := services.NewListService(listRepository, boardRepository)
+	cardService := services.NewCardService(cardRepository, listRepository, boardRepository)
+	trashRepository := repositories.NewTrashRepository(db)
+	trashService := services.NewTrashService(trashRepository, boardRepository, listRepository, cardRepository, attachmentService)
+	customFieldRepository := repositories.NewCustomFieldRepository(db)
+	customFieldService := services.NewCustomFieldService(customFieldRepository, boardRepository, listRepository, cardRepository)
+	cardRelationRepository := repositories.NewCardRelationRepository(db)
+	cardRelationService := services.NewCardRelationService(cardRelationRepository, cardRepository, boardRepository)
+	copyRepository := repositories.NewCopyRepository(db)
+	copyService := services.NewCopyService(copyRepository, boardRepository, listRepository, cardRepository)
+	watchRepository := repositories.NewWatchRepository(db)
+	notificationRepository := repositories.NewNotificationRepository(db)
+	watchService := services.NewWatchService(watchRepository, notificationRepository, boardRepository, listRepository, cardRepository)
+	timeEntryRepository := repositories.NewTimeEntryRepository(db)
+	timeTrackingService := services.NewTimeTrackingService(timeEntryRepository, boardRepository, cardRepository)
+	recurrenceRepository := repositories.NewRecurrenceRepository(db)
+	recurrenceService := services.NewRecurrenceService(recurrenceRepository, boardRepository, listRepository, cardRepository)
+	voteRepository := repositories.NewVoteRepository(db)
+	voteService := services.NewVoteService(voteRepository, boardRepository, cardRepository)
+	userBoardRepository := repositories.NewUserBoardRepository(db)
+	userBoardService := services.NewUserBoardService(userBoardRepository, boardRepository)
+	api := NewAPI(authHandler, attachmentHandler, boardService, listService, cardService, attachmentService, trashService, customFieldService, cardRelationService, copyService, watchService, timeTrackingService, recurrenceService, voteService, userBoardService)
 	return api, nil
 }
 
@@ -39,10 +62,20 @@ type Handler interface{}
 
 // API 包含所有的 handlers
 type API struct {
-	handlers map[string]Handler
-	BoardSvc services.BoardService
-	ListSvc  services.ListService
-	CardSvc  services.CardService
+	handlers        map[string]Handler
+	BoardSvc        services.BoardService
+	ListSvc         services.ListService
+	CardSvc         services.CardService
+	AttachmentSvc   services.AttachmentService
+	TrashSvc        services.TrashService
+	CustomFieldSvc  services.CustomFieldService
+	CardRelationSvc services.CardRelationService
+	CopySvc         services.CopyService
+	WatchSvc        services.WatchService
+	TimeTrackingSvc services.TimeTrackingService
+	RecurrenceSvc   services.RecurrenceService
+	VoteSvc         services.VoteService
+	UserBoardSvc    services.UserBoardService
 }
 
 func (a *API) BoardService() services.BoardService {
@@ -57,15 +90,82 @@ func (a *API) CardService() services.CardService {
 	return a.CardSvc
 }
 
+func (a *API) AttachmentService() services.AttachmentService {
+	return a.AttachmentSvc
+}
+
+func (a *API) TrashService() services.TrashService {
+	return a.TrashSvc
+}
+
+func (a *API) CustomFieldService() services.CustomFieldService {
+	return a.CustomFieldSvc
+}
+
+func (a *API) CardRelationService() services.CardRelationService {
+	return a.CardRelationSvc
+}
+
+func (a *API) CopyService() services.CopyService {
+	return a.CopySvc
+}
+
+func (a *API) WatchService() services.WatchService {
+	return a.WatchSvc
+}
+
+func (a *API) TimeTrackingService() services.TimeTrackingService {
+	return a.TimeTrackingSvc
+}
+
+func (a *API) RecurrenceService() services.RecurrenceService {
+	return a.RecurrenceSvc
+}
+
+func (a *API) VoteService() services.VoteService {
+	return a.VoteSvc
+}
+
+func (a *API) UserBoardService() services.UserBoardService {
+	return a.UserBoardSvc
+}
+
 // NewAPI 建立新的 API 實例
-func NewAPI(authHandler *handlers.AuthHandler, boardService services.BoardService, listService services.ListService, cardService services.CardService) *API {
+func NewAPI(
+	authHandler *handlers.AuthHandler,
+	attachmentHandler *handlers.AttachmentHandler,
+	boardService services.BoardService,
+	listService services.ListService,
+	cardService services.CardService,
+	attachmentService services.AttachmentService,
+	trashService services.TrashService,
+	customFieldService services.CustomFieldService,
+	cardRelationService services.CardRelationService,
+	copyService services.CopyService,
+	watchService services.WatchService,
+	timeTrackingService services.TimeTrackingService,
+	recurrenceService services.RecurrenceService,
+	voteService services.VoteService,
+	userBoardService services.UserBoardService,
+) *API {
 	api := &API{
-		handlers: make(map[string]Handler),
-		BoardSvc: boardService,
-		ListSvc:  listService,
-		CardSvc:  cardService,
+		handlers:        make(map[string]Handler),
+		BoardSvc:        boardService,
+		ListSvc:         listService,
+		CardSvc:         cardService,
+		AttachmentSvc:   attachmentService,
+		TrashSvc:        trashService,
+		CustomFieldSvc:  customFieldService,
+		CardRelationSvc: cardRelationService,
+		CopySvc:         copyService,
+		WatchSvc:        watchService,
+		TimeTrackingSvc: timeTrackingService,
+		RecurrenceSvc:   recurrenceService,
+		VoteSvc:         voteService,
+		UserBoardSvc:    userBoardService,
 	}
 	api.RegisterHandler("auth", authHandler)
+	api.RegisterHandler("attachment", attachmentHandler)
 	return api
 }
 
@@ -89,11 +189,51 @@ var listDomainSet = wire.NewSet(repositories.NewListRepository, services.NewList
 
 var cardDomainSet = wire.NewSet(repositories.NewCardRepository, services.NewCardService)
 
+// 附件 Provider Set
+var attachmentDomainSet = wire.NewSet(repositories.NewAttachmentRepository, services.NewAttachmentService, handlers.NewAttachmentHandler)
+
+// 垃圾桶 Provider Set
+var trashDomainSet = wire.NewSet(repositories.NewTrashRepository, services.NewTrashService)
+
+// 自訂欄位 Provider Set
+var customFieldDomainSet = wire.NewSet(repositories.NewCustomFieldRepository, services.NewCustomFieldService)
+
+// 卡片關係 Provider Set
+var cardRelationDomainSet = wire.NewSet(repositories.NewCardRelationRepository, services.NewCardRelationService)
+
+// 複製 Provider Set
+var copyDomainSet = wire.NewSet(repositories.NewCopyRepository, services.NewCopyService)
+
+// 關注與通知 Provider Set
+var watchDomainSet = wire.NewSet(repositories.NewWatchRepository, repositories.NewNotificationRepository, services.NewWatchService)
+
+// 時間紀錄 Provider Set
+var timeTrackingDomainSet = wire.NewSet(repositories.NewTimeEntryRepository, services.NewTimeTrackingService)
+
+// 週期性卡片 Provider Set
+var recurrenceDomainSet = wire.NewSet(repositories.NewRecurrenceRepository, services.NewRecurrenceService)
+
+// 卡片投票 Provider Set
+var voteDomainSet = wire.NewSet(repositories.NewVoteRepository, services.NewVoteService)
+
+// 星號與最近瀏覽看板 Provider Set
+var userBoardDomainSet = wire.NewSet(repositories.NewUserBoardRepository, services.NewUserBoardService)
+
 // GraphQL Resolver Provider
 var resolverSet = wire.NewSet(
 	boardDomainSet,
 	listDomainSet,
-	cardDomainSet, graph.NewResolver,
+	cardDomainSet,
+	attachmentDomainSet,
+	trashDomainSet,
+	customFieldDomainSet,
+	cardRelationDomainSet,
+	copyDomainSet,
+	watchDomainSet,
+	timeTrackingDomainSet,
+	recurrenceDomainSet,
+	voteDomainSet,
+	userBoardDomainSet, graph.NewResolver,
 )
 
 // API Provider Set
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -14,6 +18,20 @@ type Config struct {
 	DBPort           string
 	JWTSecret        string
 	CORSAllowOrigins []string
+
+	// 附件儲存設定
+	StorageDriver       string // local 或 s3
+	StorageLocalDir     string
+	S3Endpoint          string
+	S3Region            string
+	S3Bucket            string
+	S3AccessKey         string
+	S3SecretKey         string
+	AttachmentMaxSizeMB int64
+	AttachmentURLSecret string // 附件下載連結簽章用的密鑰，未設定時由 JWT 密鑰衍生
+
+	// 垃圾桶中的項目保留天數，逾期後永久刪除；至少為 1，啟動時檢查
+	TrashRetentionDays int64
 }
 
 func LoadConfig() *Config {
@@ -25,7 +43,45 @@ func LoadConfig() *Config {
 		DBPort:           os.Getenv("POSTGRES_PORT"),
 		JWTSecret:        os.Getenv("JWT_SECRET"),
 		CORSAllowOrigins: strings.Split(os.Getenv("CORS_ALLOW_ORIGINS"), ","),
+
+		StorageDriver:       getEnv("STORAGE_DRIVER", "local"),
+		StorageLocalDir:     getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+		S3Endpoint:          os.Getenv("S3_ENDPOINT"),
+		S3Region:            os.Getenv("S3_REGION"),
+		S3Bucket:            os.Getenv("S3_BUCKET"),
+		S3AccessKey:         os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:         os.Getenv("S3_SECRET_KEY"),
+		AttachmentMaxSizeMB: getEnvInt("ATTACHMENT_MAX_SIZE_MB", 10),
+		AttachmentURLSecret: os.Getenv("ATTACHMENT_URL_SECRET"),
+
+		TrashRetentionDays: getEnvInt("TRASH_RETENTION_DAYS", 30),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int64) int64 {
+	v, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// GetAttachmentURLSecret 取得附件連結的簽章密鑰。未設定時以 JWT 密鑰加上用途標籤做 HMAC 衍生，
+// 簽章連結與 JWT 不會直接共用同一把密鑰
+func (c *Config) GetAttachmentURLSecret() string {
+	if c.AttachmentURLSecret != "" {
+		return c.AttachmentURLSecret
 	}
+	mac := hmac.New(sha256.New, []byte(c.JWTSecret))
+	mac.Write([]byte("attachment-url-signing"))
+	return hex.EncodeToString(mac.Sum(nil))
 }
 
 func (c *Config) GetDBConnString() string {
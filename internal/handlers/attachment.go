@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/services"
+)
+
+// multipartOverhead 上傳請求中檔案以外的 multipart 邊界與標頭所保留的空間
+const multipartOverhead = 1 << 20
+
+// AttachmentHandler 處理附件上傳與下載
+type AttachmentHandler struct {
+	attachmentSvc services.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentSvc services.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentSvc: attachmentSvc,
+	}
+}
+
+// Upload godoc
+// @Summary 上傳卡片附件
+// @Description 以 multipart/form-data 上傳檔案至指定卡片
+// @Tags 附件
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "卡片 ID"
+// @Param file formData file true "附件檔案"
+// @Success 201 {object} models.AttachmentResponse "上傳成功"
+// @Failure 400 {object} models.APIResponse "無效的請求資料"
+// @Failure 401 {object} models.APIResponse "認證失敗"
+// @Failure 403 {object} models.APIResponse "無權限"
+// @Failure 413 {object} models.APIResponse "檔案過大"
+// @Router /cards/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{Error: "未認證"})
+		return
+	}
+	cardID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{Error: "無效的卡片 ID"})
+		return
+	}
+	// 在解析 multipart 之前限制請求大小，過大的請求不會被完整讀入
+	if maxSize := h.attachmentSvc.MaxSize(); maxSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+	}
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, models.APIResponse{Error: services.ErrFileTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.APIResponse{Error: err.Error()})
+		return
+	}
+	file, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{Error: err.Error()})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentSvc.UploadAttachment(c.Request.Context(), userID.(uuid.UUID).String(), uint(cardID), fh.Filename, fh.Size, file)
+	if err != nil {
+		c.JSON(attachmentErrorStatus(err), models.APIResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, models.AttachmentResponse{
+		ID:          attachment.ID,
+		CardID:      attachment.CardID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		URL:         h.attachmentSvc.DownloadURL(attachment),
+		CreatedAt:   attachment.CreatedAt,
+	})
+}
+
+// Download godoc
+// @Summary 下載附件
+// @Description 以簽章連結下載附件，連結由 GraphQL 或上傳 API 回傳的 url 取得
+// @Tags 附件
+// @Produce octet-stream
+// @Param id path int true "附件 ID"
+// @Param expires query int true "連結到期時間 (Unix 秒)"
+// @Param signature query string true "連結簽章"
+// @Success 200 {file} file "檔案內容"
+// @Failure 403 {object} models.APIResponse "連結無效或已過期"
+// @Failure 404 {object} models.APIResponse "檔案不存在"
+// @Router /attachments/{id}/download [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	h.serve(c, services.AttachmentVariantOriginal)
+}
+
+// Thumbnail godoc
+// @Summary 下載附件縮圖
+// @Description 以簽章連結取得圖片附件的縮圖，連結由 GraphQL 的 thumbnailUrl 取得
+// @Tags 附件
+// @Produce png,jpeg
+// @Param id path int true "附件 ID"
+// @Param expires query int true "連結到期時間 (Unix 秒)"
+// @Param signature query string true "連結簽章"
+// @Success 200 {file} file "縮圖內容"
+// @Failure 403 {object} models.APIResponse "連結無效或已過期"
+// @Failure 404 {object} models.APIResponse "縮圖不存在"
+// @Router /attachments/{id}/thumbnail [get]
+func (h *AttachmentHandler) Thumbnail(c *gin.Context) {
+	h.serve(c, services.AttachmentVariantThumbnail)
+}
+
+func (h *AttachmentHandler) serve(c *gin.Context, variant string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{Error: "無效的附件 ID"})
+		return
+	}
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusForbidden, models.APIResponse{Error: services.ErrInvalidSignature.Error()})
+		return
+	}
+	if err := h.attachmentSvc.VerifyDownload(uint(id), variant, expires, c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, models.APIResponse{Error: err.Error()})
+		return
+	}
+	attachment, rc, err := h.attachmentSvc.OpenAttachment(c.Request.Context(), uint(id), variant)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{Error: "檔案不存在"})
+		return
+	}
+	defer rc.Close()
+
+	if variant == services.AttachmentVariantThumbnail {
+		c.DataFromReader(http.StatusOK, -1, mime.TypeByExtension(filepath.Ext(attachment.ThumbnailKey)), rc, map[string]string{
+			"Cache-Control":          "private, max-age=3600",
+			"X-Content-Type-Options": "nosniff",
+		})
+		return
+	}
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, rc, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func attachmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
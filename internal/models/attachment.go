@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// Attachment represents a file attached to a card
+type Attachment struct {
+	ID           uint   `gorm:"primaryKey"`
+	CardID       uint   `gorm:"not null;index"`
+	UserID       string `gorm:"type:uuid;not null"` // 上傳者
+	FileName     string `gorm:"not null"`
+	ContentType  string `gorm:"not null"`
+	Size         int64  `gorm:"not null"`
+	StorageKey   string `gorm:"not null"` // BlobStore 中的 key
+	ThumbnailKey string // 縮圖的 key，非圖片或尚未產生時為空
+	CreatedAt    time.Time
+}
+
+// AttachmentResponse 上傳附件回應
+// swagger:model
+type AttachmentResponse struct {
+	ID          uint      `json:"id" example:"1"`
+	CardID      uint      `json:"cardId" example:"12"`
+	FileName    string    `json:"fileName" example:"spec.pdf"`
+	ContentType string    `json:"contentType" example:"application/pdf"`
+	Size        int64     `json:"size" example:"102400"`
+	URL         string    `json:"url" example:"/api/attachments/1/download?expires=1700000000&signature=..."`
+	CreatedAt   time.Time `json:"createdAt"`
+}
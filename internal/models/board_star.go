@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// BoardStar 使用者加上星號的看板，Position 為使用者自訂的星號看板順序
+type BoardStar struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    string `gorm:"type:uuid;not null;uniqueIndex:idx_board_stars_user_board"`
+	BoardID   uint   `gorm:"not null;uniqueIndex:idx_board_stars_user_board"`
+	Position  int    `gorm:"not null;default:0"`
+	CreatedAt time.Time
+}
+
+// BoardView 使用者最後一次瀏覽看板的時間，每位使用者只保留最近的幾筆
+type BoardView struct {
+	ID       uint      `gorm:"primaryKey"`
+	UserID   string    `gorm:"type:uuid;not null;uniqueIndex:idx_board_views_user_board;index:idx_board_views_user_viewed"`
+	BoardID  uint      `gorm:"not null;uniqueIndex:idx_board_views_user_board"`
+	ViewedAt time.Time `gorm:"not null;index:idx_board_views_user_viewed"`
+}
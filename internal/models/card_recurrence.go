@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// CardRecurrence 週期性卡片：依重複規則定期以來源卡片（通常是範本）在目標清單建立新卡片
+type CardRecurrence struct {
+	ID     uint   `gorm:"primaryKey"`
+	CardID uint   `gorm:"not null;uniqueIndex"` // 來源卡片
+	ListID uint   `gorm:"not null;index"`       // 新卡片建立的清單
+	Rule   string `gorm:"not null"`             // RRULE，例如 FREQ=WEEKLY;BYDAY=MO
+	// 規則的起始時間（DTSTART），發生時間沿用其時刻
+	StartAt time.Time `gorm:"not null"`
+	// 下一次應建立卡片的時間，規則結束後為 nil
+	NextRunAt        *time.Time `gorm:"index"`
+	LastOccurrenceAt *time.Time
+	LastCardID       *uint
+	OccurrenceCount  int `gorm:"not null;default:0"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
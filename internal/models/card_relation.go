@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// CardRelationType 卡片之間的關係
+type CardRelationType string
+
+const (
+	// CardRelationBlocks FromCard 阻擋 ToCard，ToCard 需等 FromCard 完成
+	CardRelationBlocks CardRelationType = "blocks"
+	// CardRelationRelates 雙向的關聯，儲存時 FromCardID 為較小的 ID
+	CardRelationRelates CardRelationType = "relates"
+)
+
+// CardRelation represents a directed relation between two cards, possibly on different boards
+type CardRelation struct {
+	ID         uint             `gorm:"primaryKey"`
+	FromCardID uint             `gorm:"not null;uniqueIndex:idx_card_relations_edge"`
+	ToCardID   uint             `gorm:"not null;uniqueIndex:idx_card_relations_edge;index"`
+	Type       CardRelationType `gorm:"not null;uniqueIndex:idx_card_relations_edge"`
+	CreatedAt  time.Time
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// CardRevision 卡片標題或內容的一次修改，保留修改前後的值以便比對與還原
+type CardRevision struct {
+	ID         uint   `gorm:"primaryKey"`
+	CardID     uint   `gorm:"not null;index"`
+	UserID     string `gorm:"type:uuid;not null"` // 修改者
+	OldTitle   string `gorm:"not null"`
+	NewTitle   string `gorm:"not null"`
+	OldContent string
+	NewContent string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+// CardVote 使用者對卡片投的一票，每位使用者對同一張卡片只能投一票
+type CardVote struct {
+	ID        uint   `gorm:"primaryKey"`
+	CardID    uint   `gorm:"not null;uniqueIndex:idx_card_votes_card_user"`
+	UserID    string `gorm:"type:uuid;not null;uniqueIndex:idx_card_votes_card_user"`
+	CreatedAt time.Time
+}
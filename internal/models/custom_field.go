@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// CustomFieldType 自訂欄位的型別
+type CustomFieldType string
+
+const (
+	CustomFieldText     CustomFieldType = "text"
+	CustomFieldNumber   CustomFieldType = "number"
+	CustomFieldDate     CustomFieldType = "date"
+	CustomFieldCheckbox CustomFieldType = "checkbox"
+	CustomFieldDropdown CustomFieldType = "dropdown"
+)
+
+// CustomField represents a field definition shared by all cards of a board
+type CustomField struct {
+	ID        uint            `gorm:"primaryKey"`
+	BoardID   uint            `gorm:"not null;index"`
+	Name      string          `gorm:"not null"`
+	Type      CustomFieldType `gorm:"not null"`
+	Position  int             `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Options   []CustomFieldOption `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"` // 僅下拉選單使用
+	Values    []CustomFieldValue  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// CustomFieldOption 下拉選單的選項
+type CustomFieldOption struct {
+	ID            uint   `gorm:"primaryKey"`
+	CustomFieldID uint   `gorm:"not null;index"`
+	Label         string `gorm:"not null"`
+	Position      int    `gorm:"not null;default:0"`
+}
+
+// CustomFieldValue 卡片在某個自訂欄位的值，依欄位型別只會使用其中一個欄位
+type CustomFieldValue struct {
+	ID            uint `gorm:"primaryKey"`
+	CardID        uint `gorm:"not null;uniqueIndex:idx_custom_field_values_card_field"`
+	CustomFieldID uint `gorm:"not null;uniqueIndex:idx_custom_field_values_card_field"`
+	TextValue     *string
+	NumberValue   *float64
+	DateValue     *time.Time
+	CheckboxValue *bool
+	OptionID      *uint
+	UpdatedAt     time.Time
+	CustomField   CustomField // 讀取時預載欄位定義以判斷型別
+}
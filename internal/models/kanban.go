@@ -2,28 +2,74 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Board represents a Kanban board
 type Board struct {
-	ID        uint   `gorm:"primaryKey"`
-	Name      string `gorm:"not null"`
-	UserID    string `gorm:"type:uuid;not null"` // 新增，關聯 User
-	Position  int    `gorm:"not null;default:0"` // 新增 position 欄位
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Lists     []List `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ID         uint   `gorm:"primaryKey"`
+	Name       string `gorm:"not null"`
+	UserID     string `gorm:"type:uuid;not null"` // 新增，關聯 User
+	Position   int    `gorm:"not null;default:0"` // 新增 position 欄位
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ArchivedAt *time.Time     `gorm:"index"` // 封存時間，nil 表示未封存
+	DeletedAt  gorm.DeletedAt `gorm:"index"` // 移至垃圾桶的時間，逾保留期限後永久刪除
+	Lists      []List         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	// 關閉時間，已關閉的看板為唯讀，不出現在看板列表中
+	ClosedAt *time.Time `gorm:"index"`
+	// 看板代號，與卡片編號組成卡片參照（例如 WEB-142），同一使用者的看板間不重複
+	Key string `gorm:"size:10;not null;default:''"`
+	// 最後一張卡片的編號，只能透過 nextCardNumber 遞增
+	CardSeq int `gorm:"not null;default:0"`
+	// 看板定義的自訂欄位
+	CustomFields []CustomField `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Settings     BoardSettings `gorm:"embedded"`
+	// 範本看板出現在範本庫中，用來建立具有相同結構的新看板；公開的範本其他使用者也能使用
+	IsTemplate bool `gorm:"not null;default:false"`
+	// 使用者的星號與瀏覽紀錄，看板刪除時一併移除
+	Stars []BoardStar `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Views []BoardView `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// BoardVisibility 看板的可見範圍
+type BoardVisibility string
+
+const (
+	BoardPrivate   BoardVisibility = "private"
+	BoardWorkspace BoardVisibility = "workspace"
+	BoardPublic    BoardVisibility = "public"
+)
+
+// BoardSettings 看板的外觀與功能設定
+type BoardSettings struct {
+	Description string
+	// 背景為純色（#rrggbb）或圖片網址擇一，皆空時使用預設背景
+	BackgroundColor    string
+	BackgroundImageURL string
+	Visibility         BoardVisibility `gorm:"not null;default:'private'"`
+	// 功能開關以停用旗標儲存，零值即為預設的啟用狀態
+	CommentsDisabled   bool `gorm:"not null;default:false"`
+	VotingDisabled     bool `gorm:"not null;default:false"`
+	CardCoversDisabled bool `gorm:"not null;default:false"`
 }
 
 // List represents a list in a Kanban board
 type List struct {
-	ID        uint   `gorm:"primaryKey"`
-	Name      string `gorm:"not null"`
-	BoardID   uint   `gorm:"not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Position  int    `gorm:"not null;default:0"`
-	Cards     []Card `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ID         uint   `gorm:"primaryKey"`
+	Name       string `gorm:"not null"`
+	BoardID    uint   `gorm:"not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Position   int            `gorm:"not null;default:0"`
+	IsDone     bool           `gorm:"not null;default:false"` // 完成清單，移入的卡片視為已完成的工作
+	ArchivedAt *time.Time     `gorm:"index"`
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+	Cards      []Card         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	// 在製品上限，nil 表示不限制；WipMode 決定超過上限時只警告或拒絕
+	WipLimit *int
+	WipMode  WipLimitMode `gorm:"not null;default:'warn'"`
 }
 
 // Card represents a card in a Kanban list
@@ -36,4 +82,48 @@ type Card struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Position  int `gorm:"not null;default:0"`
+	// 看板內遞增的卡片編號，與 BoardID 組成唯一索引，刪除後也不會重複使用
+	Number int `gorm:"not null;default:0"`
+	// 日期相關欄位，皆可為空
+	StartAt         *time.Time
+	DueAt           *time.Time `gorm:"index"`
+	CompletedAt     *time.Time
+	ReminderMinutes *int         // 到期前幾分鐘提醒
+	Attachments     []Attachment `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	// 封面：圖片附件或純色（#rrggbb）擇一
+	CoverAttachmentID *uint
+	CoverColor        string
+	ArchivedAt        *time.Time     `gorm:"index"`
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
+	// 範本卡片用來快速建立相同格式的卡片，不列入到期提醒
+	IsTemplate        bool               `gorm:"not null;default:false"`
+	Priority          CardPriority       `gorm:"not null;default:'none'"`
+	Estimate          *float64           // 估計工作量（例如故事點數），可有小數
+	CustomFieldValues []CustomFieldValue `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	// 與其他卡片的關係
+	OutgoingRelations []CardRelation  `gorm:"foreignKey:FromCardID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	IncomingRelations []CardRelation  `gorm:"foreignKey:ToCardID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	TimeEntries       []TimeEntry     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Recurrence        *CardRecurrence `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Revisions         []CardRevision  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Votes             []CardVote      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 }
+
+// WipLimitMode 清單在製品上限的執行方式
+type WipLimitMode string
+
+const (
+	WipLimitWarn  WipLimitMode = "warn"  // 允許超過上限，只標示清單超量
+	WipLimitBlock WipLimitMode = "block" // 拒絕會讓清單超過上限的建立與移動
+)
+
+// CardPriority 卡片優先順序
+type CardPriority string
+
+const (
+	PriorityNone   CardPriority = "none"
+	PriorityLow    CardPriority = "low"
+	PriorityMedium CardPriority = "medium"
+	PriorityHigh   CardPriority = "high"
+	PriorityUrgent CardPriority = "urgent"
+)
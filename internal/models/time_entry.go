@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// TimeEntry 使用者在卡片上花費的時間。EndedAt 為 nil 表示計時器仍在進行中，
+// 每位使用者同時只能有一個進行中的計時器
+type TimeEntry struct {
+	ID              uint       `gorm:"primaryKey"`
+	CardID          uint       `gorm:"not null;index"`
+	UserID          string     `gorm:"type:uuid;not null;index;uniqueIndex:idx_time_entries_running,where:ended_at IS NULL"`
+	StartedAt       time.Time  `gorm:"not null"`
+	EndedAt         *time.Time `gorm:"index"`
+	DurationSeconds int64      `gorm:"not null;default:0"` // 計時中為 0，結束時計算
+	Note            string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+)
+
+// WatchEntityType 可關注的項目種類
+type WatchEntityType string
+
+const (
+	WatchBoard WatchEntityType = "board"
+	WatchList  WatchEntityType = "list"
+	WatchCard  WatchEntityType = "card"
+)
+
+// Watch 使用者關注看板、清單或卡片，項目有變動時會收到通知
+type Watch struct {
+	ID         uint            `gorm:"primaryKey"`
+	UserID     string          `gorm:"type:uuid;not null;uniqueIndex:idx_watches_user_entity"`
+	EntityType WatchEntityType `gorm:"not null;uniqueIndex:idx_watches_user_entity;index:idx_watches_entity"`
+	EntityID   uint            `gorm:"not null;uniqueIndex:idx_watches_user_entity;index:idx_watches_entity"`
+	CreatedAt  time.Time
+}
+
+// NotificationType 通知種類
+type NotificationType string
+
+const (
+	NotificationCardMoved      NotificationType = "card_moved"
+	NotificationDueDateChanged NotificationType = "due_date_changed"
+)
+
+// Notification 發送給關注者的通知
+type Notification struct {
+	ID        uint             `gorm:"primaryKey"`
+	UserID    string           `gorm:"type:uuid;not null;index"` // 接收者
+	ActorID   string           `gorm:"type:uuid;not null"`       // 觸發變動的使用者
+	Type      NotificationType `gorm:"not null"`
+	BoardID   uint             `gorm:"not null"`
+	CardID    uint             `gorm:"not null"`
+	Message   string           `gorm:"not null"`
+	ReadAt    *time.Time
+	CreatedAt time.Time `gorm:"index"`
+}
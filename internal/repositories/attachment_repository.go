@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"trello-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	CreateAttachment(attachment *models.Attachment) error
+	GetAttachmentByID(id uint) (*models.Attachment, error)
+	GetAttachmentsByCardIDs(cardIDs []uint) (map[uint][]models.Attachment, error)
+	SetThumbnailKey(id uint, key string) error
+	DeleteAttachment(id uint) error
+	DeleteAttachmentsByCardIDs(cardIDs []uint) error
+	CountByStorageKey(key string) (int64, error)
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) CreateAttachment(attachment *models.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *attachmentRepository) GetAttachmentByID(id uint) (*models.Attachment, error) {
+	var attachment models.Attachment
+	if err := r.db.First(&attachment, id).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) GetAttachmentsByCardIDs(cardIDs []uint) (map[uint][]models.Attachment, error) {
+	if len(cardIDs) == 0 {
+		return map[uint][]models.Attachment{}, nil
+	}
+	var attachments []models.Attachment
+	err := r.db.Where("card_id IN ?", cardIDs).Order("created_at").Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint][]models.Attachment)
+	for _, a := range attachments {
+		result[a.CardID] = append(result[a.CardID], a)
+	}
+	return result, nil
+}
+
+// SetThumbnailKey 只更新縮圖欄位；附件已被刪除時回傳 gorm.ErrRecordNotFound
+func (r *attachmentRepository) SetThumbnailKey(id uint, key string) error {
+	result := r.db.Model(&models.Attachment{}).Where("id = ?", id).Update("thumbnail_key", key)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *attachmentRepository) DeleteAttachment(id uint) error {
+	return r.db.Delete(&models.Attachment{}, id).Error
+}
+
+func (r *attachmentRepository) DeleteAttachmentsByCardIDs(cardIDs []uint) error {
+	if len(cardIDs) == 0 {
+		return nil
+	}
+	return r.db.Where("card_id IN ?", cardIDs).Delete(&models.Attachment{}).Error
+}
+
+// CountByStorageKey 計算仍引用該檔案（原檔或縮圖）的附件數量，複製卡片時附件會共用同一份檔案
+func (r *attachmentRepository) CountByStorageKey(key string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Attachment{}).Where("storage_key = ? OR thumbnail_key = ?", key, key).Count(&count).Error
+	return count, err
+}
@@ -1,17 +1,28 @@
 package repositories
 
 import (
+	"errors"
+
 	"trello-backend/internal/models"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
+// ErrBoardKeyConflict 代號與使用者其他看板的代號重複，通常是兩個看板同時取得了相同的代號
+var ErrBoardKeyConflict = errors.New("看板代號重複")
+
 type BoardRepository interface {
 	CreateBoard(board *models.Board) error
 	GetBoardByID(id uint) (*models.Board, error)
 	UpdateBoard(board *models.Board) error
 	DeleteBoard(id uint) error
 	FindBoardsByUserID(userID string, boards *[]models.Board) error
+	FindArchivedBoardsByUserID(userID string, boards *[]models.Board) error
+	FindClosedBoardsByUserID(userID string, boards *[]models.Board) error
+	KeyExists(userID, key string) (bool, error)
+	GetBoardKeysByIDs(ids []uint) (map[uint]string, error)
+	FindTemplateBoards(userID string, boards *[]models.Board) error
 }
 
 type boardRepository struct {
@@ -23,7 +34,7 @@ func NewBoardRepository(db *gorm.DB) BoardRepository {
 }
 
 func (r *boardRepository) CreateBoard(board *models.Board) error {
-	return r.db.Create(board).Error
+	return boardKeyError(r.db.Create(board).Error)
 }
 
 func (r *boardRepository) GetBoardByID(id uint) (*models.Board, error) {
@@ -34,14 +45,81 @@ func (r *boardRepository) GetBoardByID(id uint) (*models.Board, error) {
 	return &board, nil
 }
 
+// UpdateBoard 更新看板，不覆寫卡片序號以免與同時建立的卡片衝突
 func (r *boardRepository) UpdateBoard(board *models.Board) error {
-	return r.db.Save(board).Error
+	return boardKeyError(r.db.Omit("card_seq").Save(board).Error)
+}
+
+// boardKeyError 將違反 idx_boards_user_key 唯一索引的錯誤轉為 ErrBoardKeyConflict
+func boardKeyError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_boards_user_key" {
+		return ErrBoardKeyConflict
+	}
+	return err
 }
 
+// DeleteBoard 將看板連同其清單與卡片移至垃圾桶，子項目使用相同的刪除時間以便一併還原；
+// 看板、清單與卡片的關注與通知會直接刪除，還原後不會恢復
 func (r *boardRepository) DeleteBoard(id uint) error {
-	return r.db.Delete(&models.Board{}, id).Error
+	now := trashTimestamp()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := deleteWatchData(tx,
+			tx.Model(&models.Board{}).Select("id").Where("id = ?", id),
+			tx.Unscoped().Model(&models.List{}).Select("id").Where("board_id = ?", id),
+			tx.Unscoped().Model(&models.Card{}).Select("id").Where("board_id = ?", id))
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Card{}).Where("board_id = ?", id).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.List{}).Where("board_id = ?", id).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return softDelete(tx.Model(&models.Board{}).Where("id = ?", id), now)
+	})
 }
 
+// FindBoardsByUserID 取得使用者未封存且未關閉的看板
 func (r *boardRepository) FindBoardsByUserID(userID string, boards *[]models.Board) error {
-	return r.db.Where("user_id = ?", userID).Find(boards).Error
+	return r.db.Where("user_id = ? AND archived_at IS NULL AND closed_at IS NULL", userID).Find(boards).Error
+}
+
+func (r *boardRepository) FindArchivedBoardsByUserID(userID string, boards *[]models.Board) error {
+	return r.db.Where("user_id = ? AND archived_at IS NOT NULL", userID).Order("archived_at DESC").Find(boards).Error
+}
+
+// FindClosedBoardsByUserID 取得使用者已關閉且未封存的看板，最近關閉的在前
+func (r *boardRepository) FindClosedBoardsByUserID(userID string, boards *[]models.Board) error {
+	return r.db.Where("user_id = ? AND closed_at IS NOT NULL AND archived_at IS NULL", userID).Order("closed_at DESC").Find(boards).Error
+}
+
+// KeyExists 檢查使用者是否已有使用該代號的看板，包含垃圾桶中的看板
+func (r *boardRepository) KeyExists(userID, key string) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Board{}).Where("user_id = ? AND key = ?", userID, key).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *boardRepository) GetBoardKeysByIDs(ids []uint) (map[uint]string, error) {
+	result := make(map[uint]string)
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var boards []models.Board
+	if err := r.db.Unscoped().Select("id", "key").Where("id IN ?", ids).Find(&boards).Error; err != nil {
+		return nil, err
+	}
+	for _, b := range boards {
+		result[b.ID] = b.Key
+	}
+	return result, nil
+}
+
+// FindTemplateBoards 取得使用者自己的範本看板與其他使用者公開的範本看板，不含已封存或已關閉的看板
+func (r *boardRepository) FindTemplateBoards(userID string, boards *[]models.Board) error {
+	return r.db.Where("is_template = ? AND archived_at IS NULL AND closed_at IS NULL", true).
+		Where("user_id = ? OR visibility = ?", userID, models.BoardPublic).
+		Order("name").Find(boards).Error
 }
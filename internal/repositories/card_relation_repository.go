@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"trello-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CardRelationRepository interface {
+	CreateRelation(relation *models.CardRelation) error
+	DeleteRelation(fromCardID, toCardID uint, relationType models.CardRelationType) error
+	GetRelationsByCardIDs(cardIDs []uint) ([]models.CardRelation, error)
+	GetBlockedCardIDs(blockerIDs []uint) ([]uint, error)
+	CountOpenBlockers(cardIDs []uint) (map[uint]int, error)
+	FindCardIDsInDoneLists(cardIDs []uint) ([]uint, error)
+}
+
+type cardRelationRepository struct {
+	db *gorm.DB
+}
+
+func NewCardRelationRepository(db *gorm.DB) CardRelationRepository {
+	return &cardRelationRepository{db: db}
+}
+
+func (r *cardRelationRepository) CreateRelation(relation *models.CardRelation) error {
+	return r.db.Create(relation).Error
+}
+
+// DeleteRelation 刪除關係，不存在時回傳 gorm.ErrRecordNotFound
+func (r *cardRelationRepository) DeleteRelation(fromCardID, toCardID uint, relationType models.CardRelationType) error {
+	result := r.db.Where("from_card_id = ? AND to_card_id = ? AND type = ?", fromCardID, toCardID, relationType).
+		Delete(&models.CardRelation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetRelationsByCardIDs 取得任一端為指定卡片的所有關係
+func (r *cardRelationRepository) GetRelationsByCardIDs(cardIDs []uint) ([]models.CardRelation, error) {
+	var relations []models.CardRelation
+	if len(cardIDs) == 0 {
+		return relations, nil
+	}
+	err := r.db.Where("from_card_id IN ? OR to_card_id IN ?", cardIDs, cardIDs).Order("created_at").Find(&relations).Error
+	return relations, err
+}
+
+// GetBlockedCardIDs 取得被 blockerIDs 中任一卡片阻擋的卡片 ID
+func (r *cardRelationRepository) GetBlockedCardIDs(blockerIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(blockerIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.CardRelation{}).
+		Where("type = ? AND from_card_id IN ?", models.CardRelationBlocks, blockerIDs).
+		Distinct().Pluck("to_card_id", &ids).Error
+	return ids, err
+}
+
+// CountOpenBlockers 計算每張卡片尚未解除的阻擋卡片數量。
+// 阻擋卡片未完成、未封存、未刪除且不在完成清單中時視為未解除
+func (r *cardRelationRepository) CountOpenBlockers(cardIDs []uint) (map[uint]int, error) {
+	result := make(map[uint]int)
+	if len(cardIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		CardID uint
+		Count  int
+	}
+	err := r.db.Table("card_relations r").
+		Select("r.to_card_id AS card_id, COUNT(*) AS count").
+		Joins("JOIN cards c ON c.id = r.from_card_id").
+		Joins("JOIN lists l ON l.id = c.list_id").
+		Where("r.type = ? AND r.to_card_id IN ?", models.CardRelationBlocks, cardIDs).
+		Where("c.completed_at IS NULL AND c.archived_at IS NULL AND c.deleted_at IS NULL AND NOT l.is_done").
+		Group("r.to_card_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.CardID] = row.Count
+	}
+	return result, nil
+}
+
+// FindCardIDsInDoneLists 取得位於完成清單中的卡片 ID
+func (r *cardRelationRepository) FindCardIDsInDoneLists(cardIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(cardIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.Card{}).
+		Joins("JOIN lists ON lists.id = cards.list_id").
+		Where("cards.id IN ? AND lists.is_done", cardIDs).
+		Pluck("cards.id", &ids).Error
+	return ids, err
+}
@@ -1,19 +1,41 @@
 package repositories
 
 import (
+	"fmt"
+	"time"
+
 	"trello-backend/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CardRepository interface {
 	CreateCard(card *models.Card) error
+	CreateCardFromTemplate(card *models.Card, templateID uint) error
 	GetCardsByListID(listID uint) ([]models.Card, error)
 	GetCardByID(id uint) (*models.Card, error)
+	GetCardsByIDs(ids []uint) ([]models.Card, error)
 	UpdateCard(card *models.Card) error
 	DeleteCard(id uint) error
 	GetCardsByBoardID(boardID uint) ([]models.Card, error)
 	GetCardsByListIDs(listIDs []uint) (map[uint][]models.Card, error)
+	GetOverdueCards(boardID uint, now time.Time) ([]models.Card, error)
+	GetCardsDueBetween(boardID uint, from, to time.Time) ([]models.Card, error)
+	ClearCoverAttachment(attachmentID uint) error
+	GetArchivedCardsByBoardID(boardID uint) ([]models.Card, error)
+	GetTemplatesByBoardID(boardID uint) ([]models.Card, error)
+	SumEstimatesByListIDs(listIDs []uint) (map[uint]float64, error)
+	SumEstimatesByBoardIDs(boardIDs []uint) (map[uint]float64, error)
+	GetCardByReference(userID, boardKey string, number int) (*models.Card, error)
+	UpdateCardWithRevision(card *models.Card, revision *models.CardRevision) error
+	MoveCard(card *models.Card, list *models.List, position int) error
+	UnarchiveCard(card *models.Card) error
+	MoveCardsToList(cards []models.Card, list *models.List) error
+	ArchiveCardsInList(listID uint, at time.Time) ([]models.Card, error)
+	UpdateCardPositions(cardIDs []uint) error
+	GetRevisionByID(id uint) (*models.CardRevision, error)
+	GetRevisionsByCardIDs(cardIDs []uint) (map[uint][]models.CardRevision, error)
 }
 
 type cardRepository struct {
@@ -24,13 +46,73 @@ func NewCardRepository(db *gorm.DB) CardRepository {
 	return &cardRepository{db: db}
 }
 
+// CreateCard 在單一交易中將卡片建立在清單最上方，並取得看板內的下一個卡片編號；
+// 清單為阻擋模式且已達在製品上限時回傳 *WipLimitError
 func (r *cardRepository) CreateCard(card *models.Card) error {
-	return r.db.Create(card).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return insertAtTop(tx, card)
+	})
+}
+
+// CreateCardFromTemplate 與 CreateCard 相同，並在同一交易中複製範本卡片的自訂欄位值；
+// 範本必須與卡片在同一個看板
+func (r *cardRepository) CreateCardFromTemplate(card *models.Card, templateID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := insertAtTop(tx, card); err != nil {
+			return err
+		}
+		return copyCustomFieldValues(tx, templateID, card.ID, nil)
+	})
 }
 
+func insertAtTop(tx *gorm.DB, card *models.Card) error {
+	if err := checkWipLimit(tx, card.ListID, wipCount(*card)); err != nil {
+		return err
+	}
+	err := tx.Model(&models.Card{}).Where("list_id = ? AND archived_at IS NULL", card.ListID).
+		Update("position", gorm.Expr("position + 1")).Error
+	if err != nil {
+		return err
+	}
+	number, err := nextCardNumber(tx, card.BoardID)
+	if err != nil {
+		return err
+	}
+	card.Number = number
+	card.Position = 0
+	return tx.Create(card).Error
+}
+
+// nextCardNumber 遞增看板的卡片序號並回傳新編號。
+// UPDATE 會鎖住看板資料列直到交易結束，同時建立的卡片不會取得相同編號
+func nextCardNumber(tx *gorm.DB, boardID uint) (int, error) {
+	var number int
+	result := tx.Raw("UPDATE boards SET card_seq = card_seq + 1 WHERE id = ? RETURNING card_seq", boardID).Scan(&number)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return number, nil
+}
+
+// GetCardByReference 以使用者看板的代號與卡片編號取得卡片
+func (r *cardRepository) GetCardByReference(userID, boardKey string, number int) (*models.Card, error) {
+	var card models.Card
+	err := r.db.Joins("JOIN boards b ON b.id = cards.board_id AND b.deleted_at IS NULL").
+		Where("b.user_id = ? AND b.key = ? AND cards.number = ?", userID, boardKey, number).
+		First(&card).Error
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// GetCardsByListID 取得清單中未封存的卡片
 func (r *cardRepository) GetCardsByListID(listID uint) ([]models.Card, error) {
 	var cards []models.Card
-	err := r.db.Where("list_id = ?", listID).Order("position").Find(&cards).Error
+	err := r.db.Where("list_id = ? AND archived_at IS NULL", listID).Order("position").Find(&cards).Error
 	return cards, err
 }
 
@@ -42,14 +124,30 @@ func (r *cardRepository) GetCardByID(id uint) (*models.Card, error) {
 	return &card, nil
 }
 
+func (r *cardRepository) GetCardsByIDs(ids []uint) ([]models.Card, error) {
+	var cards []models.Card
+	if len(ids) == 0 {
+		return cards, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&cards).Error
+	return cards, err
+}
+
 func (r *cardRepository) UpdateCard(card *models.Card) error {
 	return r.db.Save(card).Error
 }
 
+// DeleteCard 將卡片移至垃圾桶；卡片的關注與通知會直接刪除，還原後不會恢復
 func (r *cardRepository) DeleteCard(id uint) error {
-	return r.db.Delete(&models.Card{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteWatchData(tx, nil, nil, tx.Model(&models.Card{}).Select("id").Where("id = ?", id)); err != nil {
+			return err
+		}
+		return softDelete(tx.Model(&models.Card{}).Where("id = ?", id), trashTimestamp())
+	})
 }
 
+// GetCardsByBoardID 取得看板中所有卡片，包含已封存的卡片
 func (r *cardRepository) GetCardsByBoardID(boardID uint) ([]models.Card, error) {
 	var cards []models.Card
 	err := r.db.Where("board_id = ?", boardID).Order("position").Find(&cards).Error
@@ -61,7 +159,7 @@ func (r *cardRepository) GetCardsByListIDs(listIDs []uint) (map[uint][]models.Ca
 	if len(listIDs) == 0 {
 		return map[uint][]models.Card{}, nil
 	}
-	err := r.db.Where("list_id IN ?", listIDs).Order("position").Find(&cards).Error
+	err := r.db.Where("list_id IN ? AND archived_at IS NULL", listIDs).Order("position").Find(&cards).Error
 	if err != nil {
 		return nil, err
 	}
@@ -71,3 +169,387 @@ func (r *cardRepository) GetCardsByListIDs(listIDs []uint) (map[uint][]models.Ca
 	}
 	return result, nil
 }
+
+// GetOverdueCards 取得已過期且尚未完成的卡片
+func (r *cardRepository) GetOverdueCards(boardID uint, now time.Time) ([]models.Card, error) {
+	var cards []models.Card
+	err := r.db.Scopes(activeCards).
+		Where("board_id = ? AND due_at < ? AND completed_at IS NULL", boardID, now).
+		Order("due_at").Find(&cards).Error
+	return cards, err
+}
+
+// GetCardsDueBetween 取得到期時間落在 [from, to) 且尚未完成的卡片
+func (r *cardRepository) GetCardsDueBetween(boardID uint, from, to time.Time) ([]models.Card, error) {
+	var cards []models.Card
+	err := r.db.Scopes(activeCards).
+		Where("board_id = ? AND due_at >= ? AND due_at < ? AND completed_at IS NULL", boardID, from, to).
+		Order("due_at").Find(&cards).Error
+	return cards, err
+}
+
+// ClearCoverAttachment 移除所有以該附件為封面的卡片封面
+func (r *cardRepository) ClearCoverAttachment(attachmentID uint) error {
+	return r.db.Model(&models.Card{}).Where("cover_attachment_id = ?", attachmentID).
+		Update("cover_attachment_id", nil).Error
+}
+
+// GetArchivedCardsByBoardID 取得看板中已封存的卡片，最近封存的在前
+func (r *cardRepository) GetArchivedCardsByBoardID(boardID uint) ([]models.Card, error) {
+	var cards []models.Card
+	err := r.db.Where("board_id = ? AND archived_at IS NOT NULL", boardID).Order("archived_at DESC").Find(&cards).Error
+	return cards, err
+}
+
+// GetTemplatesByBoardID 取得看板中未封存的範本卡片
+func (r *cardRepository) GetTemplatesByBoardID(boardID uint) ([]models.Card, error) {
+	var cards []models.Card
+	err := r.db.Where("board_id = ? AND is_template AND archived_at IS NULL", boardID).Order("title").Find(&cards).Error
+	return cards, err
+}
+
+// SumEstimatesByListIDs 計算各清單中卡片估計工作量的總和，未估計的卡片不計入
+func (r *cardRepository) SumEstimatesByListIDs(listIDs []uint) (map[uint]float64, error) {
+	return r.sumEstimates("list_id", listIDs)
+}
+
+// SumEstimatesByBoardIDs 計算各看板中未封存清單的卡片估計工作量總和
+func (r *cardRepository) SumEstimatesByBoardIDs(boardIDs []uint) (map[uint]float64, error) {
+	return r.sumEstimates("board_id", boardIDs)
+}
+
+// sumEstimates 依 column 分組加總估計工作量，column 只能是固定的欄位名稱
+func (r *cardRepository) sumEstimates(column string, ids []uint) (map[uint]float64, error) {
+	result := make(map[uint]float64)
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		GroupID uint
+		Total   float64
+	}
+	err := r.db.Model(&models.Card{}).Scopes(activeCards).
+		Select(column+" AS group_id, COALESCE(SUM(estimate), 0) AS total").
+		Where(column+" IN ?", ids).Group(column).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.GroupID] = row.Total
+	}
+	return result, nil
+}
+
+// UpdateCardWithRevision 在同一交易中更新卡片並新增修改紀錄
+func (r *cardRepository) UpdateCardWithRevision(card *models.Card, revision *models.CardRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(card).Error; err != nil {
+			return err
+		}
+		revision.CardID = card.ID
+		return tx.Create(revision).Error
+	})
+}
+
+func (r *cardRepository) GetRevisionByID(id uint) (*models.CardRevision, error) {
+	var revision models.CardRevision
+	if err := r.db.First(&revision, id).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetRevisionsByCardIDs 取得多張卡片的修改紀錄，由新到舊排序
+func (r *cardRepository) GetRevisionsByCardIDs(cardIDs []uint) (map[uint][]models.CardRevision, error) {
+	result := make(map[uint][]models.CardRevision)
+	if len(cardIDs) == 0 {
+		return result, nil
+	}
+	var revisions []models.CardRevision
+	if err := r.db.Where("card_id IN ?", cardIDs).Order("created_at DESC, id DESC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	for _, rev := range revisions {
+		result[rev.CardID] = append(result[rev.CardID], rev)
+	}
+	return result, nil
+}
+
+// MoveCard 在單一交易中將未封存的卡片移到清單的 position（超出範圍時放在最後），原清單與目標清單中的其他卡片隨之補位。
+// 移到其他清單時檢查目標清單的在製品上限；移到其他看板時重新取得目標看板的卡片編號，
+// 自訂欄位值改用目標看板中名稱與型別相同的欄位（下拉選單再依選項名稱對應），對應不到的值會被移除
+func (r *cardRepository) MoveCard(card *models.Card, list *models.List, position int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if list.ID != card.ListID {
+			if err := checkWipLimit(tx, list.ID, wipCount(*card)); err != nil {
+				return err
+			}
+		}
+		err := tx.Model(&models.Card{}).
+			Where("list_id = ? AND archived_at IS NULL AND id <> ? AND position > ?", card.ListID, card.ID, card.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+		if err != nil {
+			return err
+		}
+		position, err = makeRoom(tx.Model(&models.Card{}).Where("list_id = ? AND archived_at IS NULL AND id <> ?", list.ID, card.ID), max(position, 0))
+		if err != nil {
+			return err
+		}
+		card.ListID = list.ID
+		card.Position = position
+		if list.BoardID == card.BoardID {
+			return tx.Save(card).Error
+		}
+		mapping, err := matchCustomFields(tx, card.BoardID, list.BoardID)
+		if err != nil {
+			return err
+		}
+		if card.Number, err = nextCardNumber(tx, list.BoardID); err != nil {
+			return err
+		}
+		card.BoardID = list.BoardID
+		if err := tx.Save(card).Error; err != nil {
+			return err
+		}
+		return remapCustomFieldValues(tx, card.ID, mapping)
+	})
+}
+
+// UnarchiveCard 在單一交易中還原封存的卡片，盡量放回封存前的位置；
+// 清單為阻擋模式且已達在製品上限時回傳 *WipLimitError
+func (r *cardRepository) UnarchiveCard(card *models.Card) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		card.ArchivedAt = nil
+		if err := checkWipLimit(tx, card.ListID, wipCount(*card)); err != nil {
+			return err
+		}
+		position, err := makeRoom(tx.Model(&models.Card{}).Where("list_id = ? AND archived_at IS NULL AND id <> ?", card.ListID, card.ID), max(card.Position, 0))
+		if err != nil {
+			return err
+		}
+		card.Position = position
+		return tx.Save(card).Error
+	})
+}
+
+// MoveCardsToList 在單一交易中依序將卡片放到清單最後，已在清單中的卡片維持原位。
+// 來自其他看板的卡片與 MoveCard 相同，重新取得編號並對應自訂欄位；卡片移出的清單會重新整理位置。
+// 移入的卡片會使阻擋模式的清單超過在製品上限時回傳 *WipLimitError，不移動任何卡片
+func (r *cardRepository) MoveCardsToList(cards []models.Card, list *models.List) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		adding := 0
+		for _, c := range cards {
+			if c.ListID != list.ID {
+				adding += wipCount(c)
+			}
+		}
+		if err := checkWipLimit(tx, list.ID, adding); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.Card{}).Where("list_id = ? AND archived_at IS NULL", list.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		position := int(count)
+		sourceLists := make(map[uint]bool)
+		// 依來源看板快取自訂欄位的對應
+		mappings := make(map[uint]*fieldMapping)
+		for i := range cards {
+			card := &cards[i]
+			if card.ListID == list.ID {
+				continue
+			}
+			sourceLists[card.ListID] = true
+			card.ListID = list.ID
+			card.Position = position
+			position++
+			var mapping *fieldMapping
+			if card.BoardID != list.BoardID {
+				var err error
+				if mapping = mappings[card.BoardID]; mapping == nil {
+					if mapping, err = matchCustomFields(tx, card.BoardID, list.BoardID); err != nil {
+						return err
+					}
+					mappings[card.BoardID] = mapping
+				}
+				if card.Number, err = nextCardNumber(tx, list.BoardID); err != nil {
+					return err
+				}
+				card.BoardID = list.BoardID
+			}
+			if err := tx.Save(card).Error; err != nil {
+				return err
+			}
+			if mapping != nil {
+				if err := remapCustomFieldValues(tx, card.ID, mapping); err != nil {
+					return err
+				}
+			}
+		}
+		for listID := range sourceLists {
+			if err := compactPositions(tx, listID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ArchiveCardsInList 在單一交易中封存清單中所有未封存的卡片，回傳被封存的卡片
+func (r *cardRepository) ArchiveCardsInList(listID uint, at time.Time) ([]models.Card, error) {
+	var cards []models.Card
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("list_id = ? AND archived_at IS NULL", listID).Order("position").Find(&cards).Error; err != nil {
+			return err
+		}
+		if len(cards) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(cards))
+		for i := range cards {
+			ids[i] = cards[i].ID
+			cards[i].ArchivedAt = &at
+		}
+		return tx.Model(&models.Card{}).Where("id IN ?", ids).Update("archived_at", at).Error
+	})
+	return cards, err
+}
+
+// UpdateCardPositions 在單一交易中依 cardIDs 的順序將卡片位置設為 0..n-1
+func (r *cardRepository) UpdateCardPositions(cardIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range cardIDs {
+			if err := tx.Model(&models.Card{}).Where("id = ? AND position <> ?", id, i).Update("position", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// compactPositions 將清單中未封存卡片的位置重新編為連續的 0..n-1
+func compactPositions(tx *gorm.DB, listID uint) error {
+	var cards []models.Card
+	if err := tx.Select("id", "position").Where("list_id = ? AND archived_at IS NULL", listID).Order("position").Find(&cards).Error; err != nil {
+		return err
+	}
+	for i, c := range cards {
+		if c.Position == i {
+			continue
+		}
+		if err := tx.Model(&models.Card{}).Where("id = ?", c.ID).Update("position", i).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchCustomFields 依名稱與型別對應兩個看板的自訂欄位，下拉選單的選項依名稱對應
+func matchCustomFields(tx *gorm.DB, fromBoardID, toBoardID uint) (*fieldMapping, error) {
+	mapping := &fieldMapping{fields: map[uint]uint{}, options: map[uint]uint{}}
+	var from, to []models.CustomField
+	if err := tx.Preload("Options").Where("board_id = ?", fromBoardID).Find(&from).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Preload("Options").Where("board_id = ?", toBoardID).Order("position").Find(&to).Error; err != nil {
+		return nil, err
+	}
+	for _, f := range from {
+		for _, t := range to {
+			if f.Name != t.Name || f.Type != t.Type {
+				continue
+			}
+			mapping.fields[f.ID] = t.ID
+			for _, o := range f.Options {
+				for _, target := range t.Options {
+					if o.Label == target.Label {
+						mapping.options[o.ID] = target.ID
+						break
+					}
+				}
+			}
+			break
+		}
+	}
+	return mapping, nil
+}
+
+// remapCustomFieldValues 將卡片的自訂欄位值改指向對應的欄位與選項，沒有對應的值會被刪除
+func remapCustomFieldValues(tx *gorm.DB, cardID uint, mapping *fieldMapping) error {
+	var values []models.CustomFieldValue
+	if err := tx.Where("card_id = ?", cardID).Find(&values).Error; err != nil {
+		return err
+	}
+	for _, v := range values {
+		fieldID, ok := mapping.fields[v.CustomFieldID]
+		optionID := v.OptionID
+		if ok && v.OptionID != nil {
+			id, found := mapping.options[*v.OptionID]
+			ok, optionID = found, &id
+		}
+		if !ok {
+			if err := tx.Delete(&models.CustomFieldValue{}, v.ID).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tx.Model(&models.CustomFieldValue{}).Where("id = ?", v.ID).Updates(map[string]interface{}{
+			"custom_field_id": fieldID,
+			"option_id":       optionID,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// activeCards 排除範本、已封存的卡片，以及位於已封存清單中的卡片
+func activeCards(db *gorm.DB) *gorm.DB {
+	return db.Where("NOT is_template AND archived_at IS NULL AND list_id NOT IN (SELECT id FROM lists WHERE archived_at IS NOT NULL)")
+}
+
+// WipLimitError 清單已達在製品上限且設定為阻擋時，建立或移入卡片會回傳此錯誤
+type WipLimitError struct {
+	ListID uint
+	Limit  int
+	Count  int // 清單目前的卡片數量
+}
+
+func (e *WipLimitError) Error() string {
+	return fmt.Sprintf("清單已達在製品上限（%d/%d），無法再加入卡片", e.Count, e.Limit)
+}
+
+// checkWipLimit 以 SELECT ... FOR UPDATE 鎖定清單到交易結束，確認再加入 adding 張卡片不會超過阻擋模式的在製品上限；
+// 同時加入同一清單的交易因此依序執行，不會一起超過上限。設定為警告時一律允許
+func checkWipLimit(tx *gorm.DB, listID uint, adding int) error {
+	if adding == 0 {
+		return nil
+	}
+	var list models.List
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&list, listID).Error; err != nil {
+		return err
+	}
+	if list.WipLimit == nil || list.WipMode != models.WipLimitBlock {
+		return nil
+	}
+	var count int64
+	err := tx.Model(&models.Card{}).Where("list_id = ? AND archived_at IS NULL AND NOT is_template", listID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if int(count)+adding > *list.WipLimit {
+		return &WipLimitError{ListID: listID, Limit: *list.WipLimit, Count: int(count)}
+	}
+	return nil
+}
+
+// wipCount 計算計入在製品上限的卡片數量，封存卡片與範本卡片不計入
+func wipCount(cards ...models.Card) int {
+	count := 0
+	for _, c := range cards {
+		if c.ArchivedAt == nil && !c.IsTemplate {
+			count++
+		}
+	}
+	return count
+}
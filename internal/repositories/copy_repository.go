@@ -0,0 +1,302 @@
+package repositories
+
+import (
+	"trello-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CopyOptions 複製時要一併帶入的子項目
+type CopyOptions struct {
+	Attachments       bool // 附件與封面；新附件與原附件共用同一份檔案
+	CustomFieldValues bool
+	TemplateCardsOnly bool // 只複製範本卡片，由範本看板建立新看板時使用
+}
+
+// CopyRepository 在單一交易中深層複製卡片、清單與看板，
+// 封存或已移至垃圾桶的清單與卡片不會被複製
+type CopyRepository interface {
+	CopyCard(cardID, targetListID uint, position int, opts CopyOptions) (*models.Card, error)
+	CopyList(listID, targetBoardID uint, name string, position int, opts CopyOptions) (*models.List, error)
+	CopyBoard(boardID uint, userID, name, key string, opts CopyOptions) (*models.Board, error)
+}
+
+type copyRepository struct {
+	db *gorm.DB
+}
+
+func NewCopyRepository(db *gorm.DB) CopyRepository {
+	return &copyRepository{db: db}
+}
+
+// fieldMapping 記錄自訂欄位與選項在來源與目的看板間的對應；
+// 同一看板內複製時為 nil，沿用原本的欄位
+type fieldMapping struct {
+	fields  map[uint]uint
+	options map[uint]uint
+}
+
+// CopyCard 將卡片複製到目標清單的 position（超出範圍時放在最後），其後的卡片依序後移；
+// 目標清單為阻擋模式且已達在製品上限時回傳 *WipLimitError
+func (r *copyRepository) CopyCard(cardID, targetListID uint, position int, opts CopyOptions) (*models.Card, error) {
+	var copied *models.Card
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var source models.Card
+		if err := tx.First(&source, cardID).Error; err != nil {
+			return err
+		}
+		var list models.List
+		if err := tx.First(&list, targetListID).Error; err != nil {
+			return err
+		}
+		// 副本一律為未封存的卡片
+		source.ArchivedAt = nil
+		if err := checkWipLimit(tx, list.ID, wipCount(source)); err != nil {
+			return err
+		}
+		position, err := makeRoom(tx.Model(&models.Card{}).Where("list_id = ? AND archived_at IS NULL", list.ID), position)
+		if err != nil {
+			return err
+		}
+		mapping, err := copyMapping(tx, source.BoardID, list.BoardID, opts)
+		if err != nil {
+			return err
+		}
+		source.Position = position
+		cards, err := copyCards(tx, []models.Card{source}, list.ID, list.BoardID, mapping, opts)
+		if err != nil {
+			return err
+		}
+		copied = &cards[0]
+		return nil
+	})
+	return copied, err
+}
+
+// CopyList 將清單與其中未封存的卡片複製到目標看板的 position，其後的清單依序後移
+func (r *copyRepository) CopyList(listID, targetBoardID uint, name string, position int, opts CopyOptions) (*models.List, error) {
+	var copied *models.List
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var source models.List
+		if err := tx.First(&source, listID).Error; err != nil {
+			return err
+		}
+		position, err := makeRoom(tx.Model(&models.List{}).Where("board_id = ? AND archived_at IS NULL", targetBoardID), position)
+		if err != nil {
+			return err
+		}
+		mapping, err := copyMapping(tx, source.BoardID, targetBoardID, opts)
+		if err != nil {
+			return err
+		}
+		list, err := copyList(tx, source, targetBoardID, name, position, mapping, opts)
+		if err != nil {
+			return err
+		}
+		copied = list
+		return nil
+	})
+	return copied, err
+}
+
+// CopyBoard 以代號 key 複製看板的自訂欄位、未封存的清單與卡片，新看板放在使用者看板的最後；
+// 卡片在新看板中重新編號；看板設定沿用來源看板，但可見範圍一律為私人，新看板也不會是範本
+func (r *copyRepository) CopyBoard(boardID uint, userID, name, key string, opts CopyOptions) (*models.Board, error) {
+	var board *models.Board
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var source models.Board
+		if err := tx.First(&source, boardID).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.Board{}).Where("user_id = ? AND archived_at IS NULL AND closed_at IS NULL", userID).Count(&count).Error; err != nil {
+			return err
+		}
+		settings := source.Settings
+		settings.Visibility = models.BoardPrivate
+		board = &models.Board{Name: name, Key: key, UserID: userID, Position: int(count), Settings: settings}
+		if err := tx.Create(board).Error; err != nil {
+			return boardKeyError(err)
+		}
+		mapping, err := copyCustomFields(tx, source.ID, board.ID)
+		if err != nil {
+			return err
+		}
+		var lists []models.List
+		if err := tx.Where("board_id = ? AND archived_at IS NULL", source.ID).Order("position").Find(&lists).Error; err != nil {
+			return err
+		}
+		for i, l := range lists {
+			if _, err := copyList(tx, l, board.ID, l.Name, i, mapping, opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return board, err
+}
+
+// copyMapping 跨看板複製時依名稱與型別對應目的看板的自訂欄位，沒有對應欄位的值不會帶入；
+// 同一看板內複製或不複製自訂欄位值時回傳 nil
+func copyMapping(tx *gorm.DB, fromBoardID, toBoardID uint, opts CopyOptions) (*fieldMapping, error) {
+	if fromBoardID == toBoardID || !opts.CustomFieldValues {
+		return nil, nil
+	}
+	return matchCustomFields(tx, fromBoardID, toBoardID)
+}
+
+// makeRoom 將 position 限制在 [0, 數量] 內，並把該位置之後的項目 position +1
+func makeRoom(scope *gorm.DB, position int) (int, error) {
+	var count int64
+	if err := scope.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if position < 0 || position > int(count) {
+		position = int(count)
+	}
+	err := scope.Session(&gorm.Session{}).Where("position >= ?", position).
+		Update("position", gorm.Expr("position + 1")).Error
+	return position, err
+}
+
+// copyList 建立清單副本並複製其中未封存的卡片；副本不沿用在製品上限，因此複製卡片時不需檢查上限
+func copyList(tx *gorm.DB, source models.List, boardID uint, name string, position int, mapping *fieldMapping, opts CopyOptions) (*models.List, error) {
+	list := &models.List{Name: name, BoardID: boardID, Position: position, IsDone: source.IsDone}
+	if err := tx.Create(list).Error; err != nil {
+		return nil, err
+	}
+	query := tx.Where("list_id = ? AND archived_at IS NULL", source.ID)
+	if opts.TemplateCardsOnly {
+		query = query.Where("is_template")
+	}
+	var cards []models.Card
+	if err := query.Order("position").Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	for i := range cards {
+		cards[i].Position = i
+	}
+	if _, err := copyCards(tx, cards, list.ID, boardID, mapping, opts); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// copyCards 建立卡片副本，沿用 sources 中的 position，並依序取得目標看板的卡片編號
+func copyCards(tx *gorm.DB, sources []models.Card, listID, boardID uint, mapping *fieldMapping, opts CopyOptions) ([]models.Card, error) {
+	copies := make([]models.Card, 0, len(sources))
+	for _, src := range sources {
+		card := models.Card{
+			Title:           src.Title,
+			Content:         src.Content,
+			ListID:          listID,
+			BoardID:         boardID,
+			Position:        src.Position,
+			StartAt:         src.StartAt,
+			DueAt:           src.DueAt,
+			CompletedAt:     src.CompletedAt,
+			ReminderMinutes: src.ReminderMinutes,
+			CoverColor:      src.CoverColor,
+			IsTemplate:      src.IsTemplate,
+			Priority:        src.Priority,
+			Estimate:        src.Estimate,
+		}
+		number, err := nextCardNumber(tx, boardID)
+		if err != nil {
+			return nil, err
+		}
+		card.Number = number
+		if err := tx.Create(&card).Error; err != nil {
+			return nil, err
+		}
+		if opts.Attachments {
+			if err := copyAttachments(tx, src, &card); err != nil {
+				return nil, err
+			}
+		}
+		if opts.CustomFieldValues {
+			if err := copyCustomFieldValues(tx, src.ID, card.ID, mapping); err != nil {
+				return nil, err
+			}
+		}
+		copies = append(copies, card)
+	}
+	return copies, nil
+}
+
+// copyAttachments 複製附件資料列，新附件與原附件共用儲存空間中的檔案
+func copyAttachments(tx *gorm.DB, src models.Card, card *models.Card) error {
+	var attachments []models.Attachment
+	if err := tx.Where("card_id = ?", src.ID).Order("created_at").Find(&attachments).Error; err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		oldID := a.ID
+		a.ID = 0
+		a.CardID = card.ID
+		if err := tx.Create(&a).Error; err != nil {
+			return err
+		}
+		if src.CoverAttachmentID != nil && *src.CoverAttachmentID == oldID {
+			card.CoverAttachmentID = &a.ID
+			if err := tx.Model(card).Update("cover_attachment_id", a.ID).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func copyCustomFieldValues(tx *gorm.DB, fromCardID, toCardID uint, mapping *fieldMapping) error {
+	var values []models.CustomFieldValue
+	if err := tx.Where("card_id = ?", fromCardID).Find(&values).Error; err != nil {
+		return err
+	}
+	for _, v := range values {
+		if mapping != nil {
+			fieldID, ok := mapping.fields[v.CustomFieldID]
+			if !ok {
+				continue
+			}
+			v.CustomFieldID = fieldID
+			if v.OptionID != nil {
+				optionID, ok := mapping.options[*v.OptionID]
+				if !ok {
+					continue
+				}
+				v.OptionID = &optionID
+			}
+		}
+		v.ID = 0
+		v.CardID = toCardID
+		if err := tx.Omit(clause.Associations).Create(&v).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyCustomFields 複製看板的自訂欄位定義與選項，回傳新舊 ID 的對應
+func copyCustomFields(tx *gorm.DB, fromBoardID, toBoardID uint) (*fieldMapping, error) {
+	mapping := &fieldMapping{fields: map[uint]uint{}, options: map[uint]uint{}}
+	var fields []models.CustomField
+	if err := tx.Preload("Options").Where("board_id = ?", fromBoardID).Order("position").Find(&fields).Error; err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		field := models.CustomField{BoardID: toBoardID, Name: f.Name, Type: f.Type, Position: f.Position}
+		if err := tx.Create(&field).Error; err != nil {
+			return nil, err
+		}
+		mapping.fields[f.ID] = field.ID
+		for _, o := range f.Options {
+			option := models.CustomFieldOption{CustomFieldID: field.ID, Label: o.Label, Position: o.Position}
+			if err := tx.Create(&option).Error; err != nil {
+				return nil, err
+			}
+			mapping.options[o.ID] = option.ID
+		}
+	}
+	return mapping, nil
+}
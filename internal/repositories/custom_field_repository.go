@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"trello-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CustomFieldFilter 依自訂欄位值篩選卡片的條件，未設定的條件不套用
+type CustomFieldFilter struct {
+	FieldID   uint
+	Text      *string // 包含此字串，不分大小寫
+	Min       *float64
+	Max       *float64
+	From      *time.Time
+	To        *time.Time
+	Checked   *bool
+	OptionIDs []uint // 符合任一選項
+	IsEmpty   *bool  // true: 未填值；false: 已填值
+}
+
+type CustomFieldRepository interface {
+	CreateField(field *models.CustomField) error
+	GetFieldByID(id uint) (*models.CustomField, error)
+	GetFieldsByBoardID(boardID uint) ([]models.CustomField, error)
+	UpdateField(field *models.CustomField) error
+	SaveOptions(fieldID uint, options []models.CustomFieldOption) error
+	DeleteField(id uint) error
+	GetValuesByCardIDs(cardIDs []uint) (map[uint][]models.CustomFieldValue, error)
+	UpsertValue(value *models.CustomFieldValue) error
+	DeleteValue(cardID, fieldID uint) error
+	FindCardsByListID(listID uint, filters []CustomFieldFilter) ([]models.Card, error)
+}
+
+type customFieldRepository struct {
+	db *gorm.DB
+}
+
+func NewCustomFieldRepository(db *gorm.DB) CustomFieldRepository {
+	return &customFieldRepository{db: db}
+}
+
+func orderedOptions(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+func (r *customFieldRepository) CreateField(field *models.CustomField) error {
+	return r.db.Create(field).Error
+}
+
+func (r *customFieldRepository) GetFieldByID(id uint) (*models.CustomField, error) {
+	var field models.CustomField
+	if err := r.db.Preload("Options", orderedOptions).First(&field, id).Error; err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (r *customFieldRepository) GetFieldsByBoardID(boardID uint) ([]models.CustomField, error) {
+	var fields []models.CustomField
+	err := r.db.Preload("Options", orderedOptions).Where("board_id = ?", boardID).Order("position").Find(&fields).Error
+	return fields, err
+}
+
+// UpdateField 只更新欄位本身，選項請使用 SaveOptions
+func (r *customFieldRepository) UpdateField(field *models.CustomField) error {
+	return r.db.Omit(clause.Associations).Save(field).Error
+}
+
+// SaveOptions 以 options 取代欄位的所有選項：有 ID 的更新、沒有 ID 的新增，
+// 不在清單中的選項會被刪除，並清除卡片上選到該選項的值
+func (r *customFieldRepository) SaveOptions(fieldID uint, options []models.CustomFieldOption) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		keep := make([]uint, 0, len(options))
+		for i := range options {
+			options[i].CustomFieldID = fieldID
+			if err := tx.Save(&options[i]).Error; err != nil {
+				return err
+			}
+			keep = append(keep, options[i].ID)
+		}
+		removed := tx.Model(&models.CustomFieldOption{}).Select("id").Where("custom_field_id = ?", fieldID)
+		if len(keep) > 0 {
+			removed = removed.Where("id NOT IN ?", keep)
+		}
+		if err := tx.Where("custom_field_id = ? AND option_id IN (?)", fieldID, removed).
+			Delete(&models.CustomFieldValue{}).Error; err != nil {
+			return err
+		}
+		del := tx.Where("custom_field_id = ?", fieldID)
+		if len(keep) > 0 {
+			del = del.Where("id NOT IN ?", keep)
+		}
+		return del.Delete(&models.CustomFieldOption{}).Error
+	})
+}
+
+func (r *customFieldRepository) DeleteField(id uint) error {
+	return r.db.Delete(&models.CustomField{}, id).Error
+}
+
+// GetValuesByCardIDs 取得多張卡片的自訂欄位值，並預載欄位定義與選項
+func (r *customFieldRepository) GetValuesByCardIDs(cardIDs []uint) (map[uint][]models.CustomFieldValue, error) {
+	if len(cardIDs) == 0 {
+		return map[uint][]models.CustomFieldValue{}, nil
+	}
+	var values []models.CustomFieldValue
+	err := r.db.Select("custom_field_values.*").Preload("CustomField").Preload("CustomField.Options", orderedOptions).
+		Joins("JOIN custom_fields ON custom_fields.id = custom_field_values.custom_field_id").
+		Where("custom_field_values.card_id IN ?", cardIDs).
+		Order("custom_fields.position").Find(&values).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint][]models.CustomFieldValue)
+	for _, v := range values {
+		result[v.CardID] = append(result[v.CardID], v)
+	}
+	return result, nil
+}
+
+// UpsertValue 新增或覆寫卡片在該欄位的值
+func (r *customFieldRepository) UpsertValue(value *models.CustomFieldValue) error {
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "card_id"}, {Name: "custom_field_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"text_value", "number_value", "date_value", "checkbox_value", "option_id", "updated_at",
+		}),
+	}).Create(value).Error
+}
+
+func (r *customFieldRepository) DeleteValue(cardID, fieldID uint) error {
+	return r.db.Where("card_id = ? AND custom_field_id = ?", cardID, fieldID).Delete(&models.CustomFieldValue{}).Error
+}
+
+// FindCardsByListID 取得清單中符合所有篩選條件的未封存卡片
+func (r *customFieldRepository) FindCardsByListID(listID uint, filters []CustomFieldFilter) ([]models.Card, error) {
+	query := r.db.Where("list_id = ? AND archived_at IS NULL", listID)
+	for _, f := range filters {
+		query = applyCustomFieldFilter(query, f)
+	}
+	var cards []models.Card
+	err := query.Order("position").Find(&cards).Error
+	return cards, err
+}
+
+func applyCustomFieldFilter(query *gorm.DB, f CustomFieldFilter) *gorm.DB {
+	const valueOf = "SELECT 1 FROM custom_field_values v WHERE v.card_id = cards.id AND v.custom_field_id = ?"
+	if f.IsEmpty != nil {
+		if *f.IsEmpty {
+			query = query.Where("NOT EXISTS ("+valueOf+")", f.FieldID)
+		} else {
+			query = query.Where("EXISTS ("+valueOf+")", f.FieldID)
+		}
+	}
+	if f.Text != nil {
+		query = query.Where("EXISTS ("+valueOf+" AND v.text_value ILIKE ?)", f.FieldID, "%"+escapeLike(*f.Text)+"%")
+	}
+	if f.Min != nil {
+		query = query.Where("EXISTS ("+valueOf+" AND v.number_value >= ?)", f.FieldID, *f.Min)
+	}
+	if f.Max != nil {
+		query = query.Where("EXISTS ("+valueOf+" AND v.number_value <= ?)", f.FieldID, *f.Max)
+	}
+	if f.From != nil {
+		query = query.Where("EXISTS ("+valueOf+" AND v.date_value >= ?)", f.FieldID, *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("EXISTS ("+valueOf+" AND v.date_value < ?)", f.FieldID, *f.To)
+	}
+	if f.Checked != nil {
+		// 未填值的核取方塊視為未勾選
+		if *f.Checked {
+			query = query.Where("EXISTS ("+valueOf+" AND v.checkbox_value)", f.FieldID)
+		} else {
+			query = query.Where("NOT EXISTS ("+valueOf+" AND v.checkbox_value)", f.FieldID)
+		}
+	}
+	if len(f.OptionIDs) > 0 {
+		query = query.Where("EXISTS ("+valueOf+" AND v.option_id IN ?)", f.FieldID, f.OptionIDs)
+	}
+	return query
+}
+
+// escapeLike 跳脫 LIKE 的萬用字元
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
@@ -12,6 +12,7 @@ type ListRepository interface {
 	GetListByID(id uint) (*models.List, error)
 	UpdateList(list *models.List) error
 	DeleteList(id uint) error
+	GetArchivedListsByBoardID(boardID uint) ([]models.List, error)
 }
 
 type listRepository struct {
@@ -26,9 +27,10 @@ func (r *listRepository) CreateList(list *models.List) error {
 	return r.db.Create(list).Error
 }
 
+// GetListsByBoardID 取得看板中未封存的清單
 func (r *listRepository) GetListsByBoardID(boardID uint) ([]models.List, error) {
 	var lists []models.List
-	err := r.db.Where("board_id = ?", boardID).Order("position").Find(&lists).Error
+	err := r.db.Where("board_id = ? AND archived_at IS NULL", boardID).Order("position").Find(&lists).Error
 	return lists, err
 }
 
@@ -44,6 +46,25 @@ func (r *listRepository) UpdateList(list *models.List) error {
 	return r.db.Save(list).Error
 }
 
+// DeleteList 將清單連同其卡片移至垃圾桶；清單與卡片的關注與通知會直接刪除，還原後不會恢復
 func (r *listRepository) DeleteList(id uint) error {
-	return r.db.Delete(&models.List{}, id).Error
+	now := trashTimestamp()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := deleteWatchData(tx, nil,
+			tx.Model(&models.List{}).Select("id").Where("id = ?", id),
+			tx.Unscoped().Model(&models.Card{}).Select("id").Where("list_id = ?", id))
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Card{}).Where("list_id = ?", id).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return softDelete(tx.Model(&models.List{}).Where("id = ?", id), now)
+	})
+}
+
+func (r *listRepository) GetArchivedListsByBoardID(boardID uint) ([]models.List, error) {
+	var lists []models.List
+	err := r.db.Where("board_id = ? AND archived_at IS NOT NULL", boardID).Order("archived_at DESC").Find(&lists).Error
+	return lists, err
 }
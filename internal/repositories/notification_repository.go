@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"time"
+
+	"trello-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	CreateNotifications(notifications []models.Notification) error
+	FindByUserID(userID string, unreadOnly bool, limit int) ([]models.Notification, error)
+	MarkRead(userID string, id uint, at time.Time) error
+	MarkAllRead(userID string, at time.Time) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) CreateNotifications(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.Create(&notifications).Error
+}
+
+// FindByUserID 取得使用者的通知，最新的在前
+func (r *notificationRepository) FindByUserID(userID string, unreadOnly bool, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	query := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+// MarkRead 將通知標為已讀，通知不存在或不屬於使用者時回傳 gorm.ErrRecordNotFound
+func (r *notificationRepository) MarkRead(userID string, id uint, at time.Time) error {
+	var notification models.Notification
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		return err
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return r.db.Model(&notification).Update("read_at", at).Error
+}
+
+func (r *notificationRepository) MarkAllRead(userID string, at time.Time) error {
+	return r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at).Error
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"time"
+
+	"trello-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurrenceRepository interface {
+	SaveRecurrence(recurrence *models.CardRecurrence) error
+	GetByCardIDs(cardIDs []uint) (map[uint]models.CardRecurrence, error)
+	DeleteByCardID(cardID uint) error
+	FindDue(now time.Time, limit int) ([]models.CardRecurrence, error)
+	CreateOccurrence(recurrence *models.CardRecurrence, occurrence time.Time, next *time.Time) (*models.Card, error)
+	SkipOccurrence(recurrence *models.CardRecurrence, next *time.Time) error
+}
+
+type recurrenceRepository struct {
+	db *gorm.DB
+}
+
+func NewRecurrenceRepository(db *gorm.DB) RecurrenceRepository {
+	return &recurrenceRepository{db: db}
+}
+
+// SaveRecurrence 新增或取代卡片的重複規則，重新設定時清除先前的發生紀錄
+func (r *recurrenceRepository) SaveRecurrence(recurrence *models.CardRecurrence) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "card_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"list_id", "rule", "start_at", "next_run_at", "last_occurrence_at", "last_card_id", "occurrence_count", "updated_at",
+		}),
+	}).Create(recurrence).Error
+}
+
+func (r *recurrenceRepository) GetByCardIDs(cardIDs []uint) (map[uint]models.CardRecurrence, error) {
+	result := make(map[uint]models.CardRecurrence)
+	if len(cardIDs) == 0 {
+		return result, nil
+	}
+	var recurrences []models.CardRecurrence
+	if err := r.db.Where("card_id IN ?", cardIDs).Find(&recurrences).Error; err != nil {
+		return nil, err
+	}
+	for _, rec := range recurrences {
+		result[rec.CardID] = rec
+	}
+	return result, nil
+}
+
+// DeleteByCardID 移除卡片的重複規則，不存在時回傳 gorm.ErrRecordNotFound
+func (r *recurrenceRepository) DeleteByCardID(cardID uint) error {
+	result := r.db.Where("card_id = ?", cardID).Delete(&models.CardRecurrence{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindDue 取得已到期的重複規則；來源卡片已封存或刪除、目標清單已封存或刪除、看板已關閉時略過
+func (r *recurrenceRepository) FindDue(now time.Time, limit int) ([]models.CardRecurrence, error) {
+	var recurrences []models.CardRecurrence
+	err := r.db.Model(&models.CardRecurrence{}).
+		Joins("JOIN cards c ON c.id = card_recurrences.card_id AND c.deleted_at IS NULL AND c.archived_at IS NULL").
+		Joins("JOIN lists l ON l.id = card_recurrences.list_id AND l.deleted_at IS NULL AND l.archived_at IS NULL").
+		Joins("JOIN boards b ON b.id = l.board_id AND b.closed_at IS NULL").
+		Where("card_recurrences.next_run_at <= ?", now).
+		Order("card_recurrences.next_run_at").Limit(limit).
+		Find(&recurrences).Error
+	return recurrences, err
+}
+
+// CreateOccurrence 以來源卡片在目標清單最上方建立一次發生的卡片，並將規則推進到 next。
+// 以 next_run_at 作為條件更新，其他程序已處理同一次發生時回傳 nil，確保不會重複建立。
+// 發生紀錄與次數在卡片建立後才更新；目標清單為阻擋模式且已達在製品上限時整個交易取消並回傳 *WipLimitError
+func (r *recurrenceRepository) CreateOccurrence(recurrence *models.CardRecurrence, occurrence time.Time, next *time.Time) (*models.Card, error) {
+	var created *models.Card
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		claimed, err := advanceRecurrence(tx, recurrence, next)
+		if err != nil || !claimed {
+			return err
+		}
+		var source models.Card
+		if err := tx.First(&source, recurrence.CardID).Error; err != nil {
+			return err
+		}
+		var list models.List
+		if err := tx.First(&list, recurrence.ListID).Error; err != nil {
+			return err
+		}
+		if err := checkWipLimit(tx, list.ID, 1); err != nil {
+			return err
+		}
+		if _, err := makeRoom(tx.Model(&models.Card{}).Where("list_id = ? AND archived_at IS NULL", list.ID), 0); err != nil {
+			return err
+		}
+		opts := CopyOptions{CustomFieldValues: true}
+		mapping, err := copyMapping(tx, source.BoardID, list.BoardID, opts)
+		if err != nil {
+			return err
+		}
+		// 新卡片以發生時間作為開始時間，不沿用來源卡片的日期與範本標記
+		source.Position = 0
+		source.IsTemplate = false
+		source.StartAt = &occurrence
+		source.DueAt = nil
+		source.CompletedAt = nil
+		source.ReminderMinutes = nil
+		cards, err := copyCards(tx, []models.Card{source}, list.ID, list.BoardID, mapping, opts)
+		if err != nil {
+			return err
+		}
+		created = &cards[0]
+		return tx.Model(&models.CardRecurrence{}).Where("id = ?", recurrence.ID).
+			Updates(map[string]interface{}{
+				"last_occurrence_at": occurrence,
+				"occurrence_count":   gorm.Expr("occurrence_count + 1"),
+				"last_card_id":       created.ID,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// SkipOccurrence 略過這次發生，只將規則推進到 next，不更新發生紀錄與次數；
+// 與 CreateOccurrence 相同以 next_run_at 作為條件，其他程序已處理時不做任何事
+func (r *recurrenceRepository) SkipOccurrence(recurrence *models.CardRecurrence, next *time.Time) error {
+	_, err := advanceRecurrence(r.db, recurrence, next)
+	return err
+}
+
+// advanceRecurrence 在 next_run_at 仍為 recurrence 讀取時的值時將其改為 next，回傳是否由這次呼叫推進
+func advanceRecurrence(db *gorm.DB, recurrence *models.CardRecurrence, next *time.Time) (bool, error) {
+	result := db.Model(&models.CardRecurrence{}).
+		Where("id = ? AND next_run_at = ?", recurrence.ID, recurrence.NextRunAt).
+		Update("next_run_at", next)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"time"
+
+	"trello-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TimeReportRow 看板時間報表中一位使用者的統計
+type TimeReportRow struct {
+	UserID       string
+	UserName     string
+	TotalSeconds int64
+	EntryCount   int
+}
+
+type TimeEntryRepository interface {
+	CreateEntry(entry *models.TimeEntry) error
+	GetEntryByID(id uint) (*models.TimeEntry, error)
+	DeleteEntry(id uint) error
+	GetEntriesByCardIDs(cardIDs []uint) (map[uint][]models.TimeEntry, error)
+	SumDurationsByCardIDs(cardIDs []uint) (map[uint]int64, error)
+	GetRunningEntry(userID string) (*models.TimeEntry, error)
+	StartTimer(entry *models.TimeEntry) (*models.TimeEntry, error)
+	StopRunningEntry(userID string, at time.Time) (*models.TimeEntry, error)
+	GetBoardReport(boardID uint, from, to time.Time) ([]TimeReportRow, error)
+}
+
+type timeEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewTimeEntryRepository(db *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{db: db}
+}
+
+func (r *timeEntryRepository) CreateEntry(entry *models.TimeEntry) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *timeEntryRepository) GetEntryByID(id uint) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	if err := r.db.First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *timeEntryRepository) DeleteEntry(id uint) error {
+	return r.db.Delete(&models.TimeEntry{}, id).Error
+}
+
+// GetEntriesByCardIDs 取得卡片的時間紀錄，最新的在前
+func (r *timeEntryRepository) GetEntriesByCardIDs(cardIDs []uint) (map[uint][]models.TimeEntry, error) {
+	result := make(map[uint][]models.TimeEntry)
+	if len(cardIDs) == 0 {
+		return result, nil
+	}
+	var entries []models.TimeEntry
+	if err := r.db.Where("card_id IN ?", cardIDs).Order("started_at DESC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		result[e.CardID] = append(result[e.CardID], e)
+	}
+	return result, nil
+}
+
+// SumDurationsByCardIDs 加總卡片已結束的時間紀錄（秒）
+func (r *timeEntryRepository) SumDurationsByCardIDs(cardIDs []uint) (map[uint]int64, error) {
+	result := make(map[uint]int64)
+	if len(cardIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		CardID uint
+		Total  int64
+	}
+	err := r.db.Model(&models.TimeEntry{}).
+		Select("card_id, SUM(duration_seconds) AS total").
+		Where("card_id IN ? AND ended_at IS NOT NULL", cardIDs).
+		Group("card_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.CardID] = row.Total
+	}
+	return result, nil
+}
+
+// GetRunningEntry 取得使用者進行中的計時器，沒有時回傳 nil
+func (r *timeEntryRepository) GetRunningEntry(userID string) (*models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	if err := r.db.Where("user_id = ? AND ended_at IS NULL", userID).Limit(1).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return &entries[0], nil
+}
+
+// StartTimer 在同一個交易中停止使用者進行中的計時器並開始新的計時，回傳被停止的紀錄（沒有時為 nil）
+func (r *timeEntryRepository) StartTimer(entry *models.TimeEntry) (*models.TimeEntry, error) {
+	var stopped *models.TimeEntry
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		stopped, err = stopRunning(tx, entry.UserID, entry.StartedAt)
+		if err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+	return stopped, err
+}
+
+// StopRunningEntry 停止使用者進行中的計時器，沒有時回傳 nil
+func (r *timeEntryRepository) StopRunningEntry(userID string, at time.Time) (*models.TimeEntry, error) {
+	var stopped *models.TimeEntry
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		stopped, err = stopRunning(tx, userID, at)
+		return err
+	})
+	return stopped, err
+}
+
+func stopRunning(tx *gorm.DB, userID string, at time.Time) (*models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	if err := tx.Where("user_id = ? AND ended_at IS NULL", userID).Limit(1).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	entry := &entries[0]
+	entry.EndedAt = &at
+	entry.DurationSeconds = int64(at.Sub(entry.StartedAt).Seconds())
+	if entry.DurationSeconds < 0 {
+		entry.DurationSeconds = 0
+	}
+	err := tx.Model(entry).Updates(map[string]interface{}{
+		"ended_at":         entry.EndedAt,
+		"duration_seconds": entry.DurationSeconds,
+	}).Error
+	return entry, err
+}
+
+// GetBoardReport 依使用者統計看板在 [from, to) 期間開始的已結束時間紀錄，
+// 已刪除卡片上的紀錄不計入
+func (r *timeEntryRepository) GetBoardReport(boardID uint, from, to time.Time) ([]TimeReportRow, error) {
+	var rows []TimeReportRow
+	err := r.db.Table("time_entries t").
+		Select("t.user_id, COALESCE(u.name, '') AS user_name, SUM(t.duration_seconds) AS total_seconds, COUNT(*) AS entry_count").
+		Joins("JOIN cards c ON c.id = t.card_id AND c.deleted_at IS NULL").
+		Joins("LEFT JOIN users u ON u.id = t.user_id").
+		Where("c.board_id = ? AND t.ended_at IS NOT NULL AND t.started_at >= ? AND t.started_at < ?", boardID, from, to).
+		Group("t.user_id, u.name").
+		Order("total_seconds DESC").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"time"
+
+	"trello-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TrashRepository 存取垃圾桶（已軟刪除）中的看板、清單與卡片
+type TrashRepository interface {
+	FindDeletedBoards(userID string) ([]models.Board, error)
+	FindDeletedLists(boardID uint) ([]models.List, error)
+	FindDeletedCards(boardID uint) ([]models.Card, error)
+	GetDeletedBoard(id uint) (*models.Board, error)
+	GetDeletedList(id uint) (*models.List, error)
+	GetDeletedCard(id uint) (*models.Card, error)
+	RestoreBoard(board *models.Board) error
+	RestoreList(list *models.List) error
+	RestoreCard(card *models.Card) error
+	FindCardIDsDeletedBefore(cutoff time.Time) ([]uint, error)
+	PurgeDeletedBefore(cutoff time.Time) (int64, error)
+}
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+func NewTrashRepository(db *gorm.DB) TrashRepository {
+	return &trashRepository{db: db}
+}
+
+// trashTimestamp 取得刪除時間。PostgreSQL 只保存到微秒，
+// 先截斷才能在還原時以相同時間比對出一併刪除的子項目
+func trashTimestamp() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+// softDelete 為尚未刪除的紀錄設定刪除時間，沒有符合的紀錄時回傳 gorm.ErrRecordNotFound
+func softDelete(db *gorm.DB, deletedAt time.Time) error {
+	result := db.Update("deleted_at", deletedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *trashRepository) FindDeletedBoards(userID string) ([]models.Board, error) {
+	var boards []models.Board
+	err := r.db.Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").Find(&boards).Error
+	return boards, err
+}
+
+func (r *trashRepository) FindDeletedLists(boardID uint) ([]models.List, error) {
+	var lists []models.List
+	err := r.db.Unscoped().Where("board_id = ? AND deleted_at IS NOT NULL", boardID).
+		Order("deleted_at DESC").Find(&lists).Error
+	return lists, err
+}
+
+// FindDeletedCards 取得看板中被刪除的卡片，不含隨清單一起刪除的卡片（還原清單時會一併還原）
+func (r *trashRepository) FindDeletedCards(boardID uint) ([]models.Card, error) {
+	var cards []models.Card
+	err := r.db.Unscoped().
+		Where("board_id = ? AND deleted_at IS NOT NULL", boardID).
+		Where("list_id NOT IN (SELECT id FROM lists WHERE deleted_at IS NOT NULL)").
+		Order("deleted_at DESC").Find(&cards).Error
+	return cards, err
+}
+
+func (r *trashRepository) GetDeletedBoard(id uint) (*models.Board, error) {
+	var board models.Board
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&board, id).Error; err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+func (r *trashRepository) GetDeletedList(id uint) (*models.List, error) {
+	var list models.List
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&list, id).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *trashRepository) GetDeletedCard(id uint) (*models.Card, error) {
+	var card models.Card
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&card, id).Error; err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// RestoreBoard 還原看板，以及與看板同時被刪除的清單與卡片
+func (r *trashRepository) RestoreBoard(board *models.Board) error {
+	deletedAt := board.DeletedAt.Time
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Card{}).
+			Where("board_id = ? AND deleted_at = ?", board.ID, deletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.List{}).
+			Where("board_id = ? AND deleted_at = ?", board.ID, deletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		board.DeletedAt = gorm.DeletedAt{}
+		return tx.Unscoped().Omit("card_seq").Save(board).Error
+	})
+}
+
+// RestoreList 還原清單，以及與清單同時被刪除的卡片
+func (r *trashRepository) RestoreList(list *models.List) error {
+	deletedAt := list.DeletedAt.Time
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Card{}).
+			Where("list_id = ? AND deleted_at = ?", list.ID, deletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		list.DeletedAt = gorm.DeletedAt{}
+		return tx.Unscoped().Save(list).Error
+	})
+}
+
+// RestoreCard 還原卡片；所在清單為阻擋模式且已達在製品上限時回傳 *WipLimitError
+func (r *trashRepository) RestoreCard(card *models.Card) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkWipLimit(tx, card.ListID, wipCount(*card)); err != nil {
+			return err
+		}
+		card.DeletedAt = gorm.DeletedAt{}
+		return tx.Unscoped().Save(card).Error
+	})
+}
+
+// FindCardIDsDeletedBefore 取得刪除時間早於 cutoff 的卡片 ID，供永久刪除前清理附件
+func (r *trashRepository) FindCardIDsDeletedBefore(cutoff time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Unscoped().Model(&models.Card{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// PurgeDeletedBefore 永久刪除刪除時間早於 cutoff 的卡片、清單與看板及其關注與通知，回傳刪除的筆數
+func (r *trashRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		expired := func(model any) *gorm.DB {
+			return tx.Unscoped().Model(model).Select("id").Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+		}
+		if err := deleteWatchData(tx, expired(&models.Board{}), expired(&models.List{}), expired(&models.Card{})); err != nil {
+			return err
+		}
+		for _, model := range []any{&models.Card{}, &models.List{}, &models.Board{}} {
+			result := tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Delete(model)
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+		}
+		return nil
+	})
+	return purged, err
+}
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"time"
+
+	"trello-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserBoardRepository 存取使用者個人的看板資料：星號看板與最近瀏覽紀錄
+type UserBoardRepository interface {
+	StarBoard(userID string, boardID uint) error
+	UnstarBoard(userID string, boardID uint) error
+	MoveStar(userID string, boardID uint, position int) error
+	GetStarredBoards(userID string) ([]models.Board, error)
+	FindStarredBoardIDs(userID string, boardIDs []uint) ([]uint, error)
+	RecordView(userID string, boardID uint, at time.Time, keep int) error
+	GetRecentBoards(userID string, limit int) ([]models.Board, error)
+}
+
+type userBoardRepository struct {
+	db *gorm.DB
+}
+
+func NewUserBoardRepository(db *gorm.DB) UserBoardRepository {
+	return &userBoardRepository{db: db}
+}
+
+// StarBoard 將看板加上星號並放在星號看板的最後，已加上星號時不做任何事。
+// 先鎖定使用者資料列，同時加上的星號才不會取得相同的位置
+func (r *userBoardRepository) StarBoard(userID string, boardID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", userID).Take(&user).Error; err != nil {
+			return err
+		}
+		var position int
+		err := tx.Model(&models.BoardStar{}).Where("user_id = ?", userID).
+			Select("COALESCE(MAX(position) + 1, 0)").Scan(&position).Error
+		if err != nil {
+			return err
+		}
+		star := &models.BoardStar{UserID: userID, BoardID: boardID, Position: position}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(star).Error
+	})
+}
+
+// UnstarBoard 移除星號，並將後面的星號看板往前補位
+func (r *userBoardRepository) UnstarBoard(userID string, boardID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var star models.BoardStar
+		err := tx.Where("user_id = ? AND board_id = ?", userID, boardID).First(&star).Error
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&star).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.BoardStar{}).
+			Where("user_id = ? AND position > ?", userID, star.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+// MoveStar 將星號看板移到 position，超出範圍時放在最後；看板沒有星號時回傳 gorm.ErrRecordNotFound
+func (r *userBoardRepository) MoveStar(userID string, boardID uint, position int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var stars []models.BoardStar
+		if err := tx.Where("user_id = ?", userID).Order("position, id").Find(&stars).Error; err != nil {
+			return err
+		}
+		from := -1
+		for i, s := range stars {
+			if s.BoardID == boardID {
+				from = i
+				break
+			}
+		}
+		if from < 0 {
+			return gorm.ErrRecordNotFound
+		}
+		moved := stars[from]
+		stars = append(stars[:from], stars[from+1:]...)
+		position = min(max(position, 0), len(stars))
+		stars = append(stars[:position], append([]models.BoardStar{moved}, stars[position:]...)...)
+		for i, s := range stars {
+			if s.Position == i {
+				continue
+			}
+			if err := tx.Model(&models.BoardStar{}).Where("id = ?", s.ID).Update("position", i).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetStarredBoards 依使用者自訂的順序取得星號看板，不含已封存或已關閉的看板
+func (r *userBoardRepository) GetStarredBoards(userID string) ([]models.Board, error) {
+	var boards []models.Board
+	err := r.db.
+		Joins("JOIN board_stars s ON s.board_id = boards.id AND s.user_id = ?", userID).
+		Where("boards.archived_at IS NULL AND boards.closed_at IS NULL").
+		Order("s.position").
+		Find(&boards).Error
+	return boards, err
+}
+
+// FindStarredBoardIDs 回傳 boardIDs 中使用者加上星號的看板
+func (r *userBoardRepository) FindStarredBoardIDs(userID string, boardIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(boardIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.BoardStar{}).
+		Where("user_id = ? AND board_id IN ?", userID, boardIDs).
+		Pluck("board_id", &ids).Error
+	return ids, err
+}
+
+// RecordView 記錄使用者瀏覽看板的時間，並只保留最近瀏覽的 keep 個未封存且未關閉的看板；
+// 已封存、已關閉或在垃圾桶中看板的紀錄一併清除，不佔用保留名額
+func (r *userBoardRepository) RecordView(userID string, boardID uint, at time.Time, keep int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		view := &models.BoardView{UserID: userID, BoardID: boardID, ViewedAt: at}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "board_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"viewed_at"}),
+		}).Create(view).Error
+		if err != nil {
+			return err
+		}
+		recent := tx.Model(&models.BoardView{}).Select("board_views.id").
+			Joins("JOIN boards ON boards.id = board_views.board_id").
+			Where("board_views.user_id = ? AND boards.archived_at IS NULL AND boards.closed_at IS NULL AND boards.deleted_at IS NULL", userID).
+			Order("board_views.viewed_at DESC, board_views.id DESC").Limit(keep)
+		return tx.Where("user_id = ? AND id NOT IN (?)", userID, recent).Delete(&models.BoardView{}).Error
+	})
+}
+
+// GetRecentBoards 取得使用者最近瀏覽的看板，最新的在前，不含已封存或已關閉的看板
+func (r *userBoardRepository) GetRecentBoards(userID string, limit int) ([]models.Board, error) {
+	var boards []models.Board
+	err := r.db.
+		Joins("JOIN board_views v ON v.board_id = boards.id AND v.user_id = ?", userID).
+		Where("boards.archived_at IS NULL AND boards.closed_at IS NULL").
+		Order("v.viewed_at DESC").
+		Limit(limit).
+		Find(&boards).Error
+	return boards, err
+}
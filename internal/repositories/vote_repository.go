@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"trello-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VoteRepository interface {
+	ToggleVote(userID string, cardID uint) (bool, error)
+	CountByCardIDs(cardIDs []uint) (map[uint]int, error)
+	FindVotedCardIDs(userID string, cardIDs []uint) ([]uint, error)
+	RankCardsByVotes(boardID uint, limit int) ([]models.Card, error)
+}
+
+type voteRepository struct {
+	db *gorm.DB
+}
+
+func NewVoteRepository(db *gorm.DB) VoteRepository {
+	return &voteRepository{db: db}
+}
+
+// ToggleVote 已投票時取消，否則投票，回傳切換後是否已投票
+func (r *voteRepository) ToggleVote(userID string, cardID uint) (bool, error) {
+	voted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("card_id = ? AND user_id = ?", cardID, userID).Delete(&models.CardVote{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			return nil
+		}
+		voted = true
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.CardVote{CardID: cardID, UserID: userID}).Error
+	})
+	return voted, err
+}
+
+// CountByCardIDs 計算卡片的票數，沒有票的卡片不會出現在結果中
+func (r *voteRepository) CountByCardIDs(cardIDs []uint) (map[uint]int, error) {
+	result := make(map[uint]int)
+	if len(cardIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		CardID uint
+		Votes  int
+	}
+	err := r.db.Model(&models.CardVote{}).
+		Select("card_id, COUNT(*) AS votes").
+		Where("card_id IN ?", cardIDs).
+		Group("card_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.CardID] = row.Votes
+	}
+	return result, nil
+}
+
+// FindVotedCardIDs 回傳 cardIDs 中使用者有投票的卡片
+func (r *voteRepository) FindVotedCardIDs(userID string, cardIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(cardIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.CardVote{}).
+		Where("user_id = ? AND card_id IN ?", userID, cardIDs).
+		Pluck("card_id", &ids).Error
+	return ids, err
+}
+
+// RankCardsByVotes 取得看板中至少有一票的卡片，依票數由多到少排列，同票時較早建立的在前；
+// 不含封存卡片與範本
+func (r *voteRepository) RankCardsByVotes(boardID uint, limit int) ([]models.Card, error) {
+	var cards []models.Card
+	err := r.db.
+		Joins("JOIN (SELECT card_id, COUNT(*) AS votes FROM card_votes GROUP BY card_id) v ON v.card_id = cards.id").
+		Where("cards.board_id = ? AND cards.archived_at IS NULL AND cards.is_template = ?", boardID, false).
+		Order("v.votes DESC, cards.created_at, cards.id").
+		Limit(limit).
+		Find(&cards).Error
+	return cards, err
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"trello-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WatchTarget 一個可關注的項目
+type WatchTarget struct {
+	Type models.WatchEntityType
+	ID   uint
+}
+
+type WatchRepository interface {
+	CreateWatch(watch *models.Watch) error
+	DeleteWatch(userID string, entityType models.WatchEntityType, entityID uint) error
+	FindWatchedTargets(userID string, targets []WatchTarget) ([]WatchTarget, error)
+	FindWatcherIDs(targets []WatchTarget) ([]string, error)
+}
+
+type watchRepository struct {
+	db *gorm.DB
+}
+
+func NewWatchRepository(db *gorm.DB) WatchRepository {
+	return &watchRepository{db: db}
+}
+
+// CreateWatch 新增關注，已關注時不做任何事
+func (r *watchRepository) CreateWatch(watch *models.Watch) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(watch).Error
+}
+
+func (r *watchRepository) DeleteWatch(userID string, entityType models.WatchEntityType, entityID uint) error {
+	return r.db.Where("user_id = ? AND entity_type = ? AND entity_id = ?", userID, entityType, entityID).
+		Delete(&models.Watch{}).Error
+}
+
+// FindWatchedTargets 回傳 targets 中使用者有關注的項目
+func (r *watchRepository) FindWatchedTargets(userID string, targets []WatchTarget) ([]WatchTarget, error) {
+	var watched []WatchTarget
+	if len(targets) == 0 {
+		return watched, nil
+	}
+	var watches []models.Watch
+	err := r.db.Where("user_id = ?", userID).Where(targetsCondition(r.db, targets)).Find(&watches).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range watches {
+		watched = append(watched, WatchTarget{Type: w.EntityType, ID: w.EntityID})
+	}
+	return watched, nil
+}
+
+// FindWatcherIDs 取得關注任一項目的使用者，不重複
+func (r *watchRepository) FindWatcherIDs(targets []WatchTarget) ([]string, error) {
+	var ids []string
+	if len(targets) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.Watch{}).Where(targetsCondition(r.db, targets)).
+		Distinct().Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// targetsCondition 組出 (entity_type = ? AND entity_id IN ?) OR ... 的條件
+func targetsCondition(db *gorm.DB, targets []WatchTarget) *gorm.DB {
+	idsByType := make(map[models.WatchEntityType][]uint)
+	var types []models.WatchEntityType
+	for _, t := range targets {
+		if _, ok := idsByType[t.Type]; !ok {
+			types = append(types, t.Type)
+		}
+		idsByType[t.Type] = append(idsByType[t.Type], t.ID)
+	}
+	cond := db.Session(&gorm.Session{NewDB: true})
+	for _, t := range types {
+		cond = cond.Or("entity_type = ? AND entity_id IN ?", t, idsByType[t])
+	}
+	return cond
+}
+
+// deleteWatchData 刪除所選看板、清單與卡片的關注，以及這些看板與卡片的通知；
+// 參數為選出 ID 的子查詢，nil 表示不刪除該種類。在刪除項目的交易中呼叫
+func deleteWatchData(tx *gorm.DB, boardIDs, listIDs, cardIDs *gorm.DB) error {
+	for _, t := range []struct {
+		entityType models.WatchEntityType
+		ids        *gorm.DB
+	}{{models.WatchBoard, boardIDs}, {models.WatchList, listIDs}, {models.WatchCard, cardIDs}} {
+		if t.ids == nil {
+			continue
+		}
+		err := tx.Where("entity_type = ? AND entity_id IN (?)", t.entityType, t.ids).Delete(&models.Watch{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if boardIDs != nil {
+		if err := tx.Where("board_id IN (?)", boardIDs).Delete(&models.Notification{}).Error; err != nil {
+			return err
+		}
+	}
+	if cardIDs != nil {
+		if err := tx.Where("card_id IN (?)", cardIDs).Delete(&models.Notification{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
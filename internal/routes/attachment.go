@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"trello-backend/internal/handlers"
+	"trello-backend/internal/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *Router) setupAttachmentRoutes(api *gin.RouterGroup) {
+	attachmentHandler := r.handlers["attachment"].(*handlers.AttachmentHandler)
+
+	// 下載連結本身帶有簽章，不需要 Authorization header
+	api.GET("/attachments/:id/download", attachmentHandler.Download)
+	api.GET("/attachments/:id/thumbnail", attachmentHandler.Thumbnail)
+
+	protected := api.Group("")
+	protected.Use(middlewares.AuthMiddleware(r.jwtSecret))
+	{
+		protected.POST("/cards/:id/attachments", attachmentHandler.Upload)
+	}
+}
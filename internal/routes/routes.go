@@ -47,6 +47,7 @@ func (r *Router) SetupRoutes() {
 
 	// 設定各個功能模組的路由
 	r.setupAuthRoutes(api)
+	r.setupAttachmentRoutes(api)
 	// 之後可以輕鬆添加其他模組的路由
 	// r.setupBoardRoutes(api)
 	// r.setupCardRoutes(api)
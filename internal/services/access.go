@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+)
+
+var (
+	// ErrForbidden 使用者無權存取該看板
+	ErrForbidden = errors.New("無權限存取此看板")
+	// ErrBoardClosed 已關閉的看板為唯讀，其中的清單與卡片都不能變更
+	ErrBoardClosed = errors.New("看板已關閉，無法變更其中的清單與卡片")
+)
+
+// ensureBoardOwner 確認看板屬於該使用者
+func ensureBoardOwner(boardRepo repositories.BoardRepository, boardID uint, userID string) (*models.Board, error) {
+	board, err := boardRepo.GetBoardByID(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if board.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return board, nil
+}
+
+// ensureBoardReadable 確認使用者可以讀取看板：看板屬於該使用者，或看板為公開
+func ensureBoardReadable(boardRepo repositories.BoardRepository, boardID uint, userID string) (*models.Board, error) {
+	board, err := boardRepo.GetBoardByID(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if board.UserID != userID && board.Settings.Visibility != models.BoardPublic {
+		return nil, ErrForbidden
+	}
+	return board, nil
+}
+
+// ensureBoardEditable 確認看板屬於該使用者且未關閉，變更看板中的清單與卡片前使用
+func ensureBoardEditable(boardRepo repositories.BoardRepository, boardID uint, userID string) (*models.Board, error) {
+	board, err := ensureBoardOwner(boardRepo, boardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if board.ClosedAt != nil {
+		return nil, ErrBoardClosed
+	}
+	return board, nil
+}
+
+// ensureBoardOpen 確認看板未關閉，用於不檢查擁有者的清單與卡片變更
+func ensureBoardOpen(boardRepo repositories.BoardRepository, boardID uint) error {
+	board, err := boardRepo.GetBoardByID(boardID)
+	if err != nil {
+		return err
+	}
+	if board.ClosedAt != nil {
+		return ErrBoardClosed
+	}
+	return nil
+}
+
+// boardOwnerCache 批次變更時快取各看板的權限與唯讀檢查結果
+type boardOwnerCache struct {
+	boardRepo repositories.BoardRepository
+	userID    string
+	results   map[uint]error
+}
+
+func (c *boardOwnerCache) check(boardID uint) error {
+	if err, ok := c.results[boardID]; ok {
+		return err
+	}
+	if c.results == nil {
+		c.results = make(map[uint]error)
+	}
+	_, err := ensureBoardEditable(c.boardRepo, boardID, c.userID)
+	c.results[boardID] = err
+	return err
+}
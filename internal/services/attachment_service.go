@@ -0,0 +1,406 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+	"trello-backend/internal/storage"
+	"trello-backend/pkg/thumbnail"
+)
+
+var (
+	ErrFileTooLarge     = errors.New("檔案大小超過上限")
+	ErrInvalidSignature = errors.New("下載連結無效或已過期")
+	ErrInvalidCover     = errors.New("封面需指定一張圖片附件或一個 #rrggbb 顏色")
+	ErrCoversDisabled   = errors.New("此看板已停用卡片封面")
+)
+
+// 附件下載的版本
+const (
+	AttachmentVariantOriginal  = "original"
+	AttachmentVariantThumbnail = "thumbnail"
+)
+
+var coverColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// AttachmentOptions 附件相關設定
+type AttachmentOptions struct {
+	MaxSize       int64         // 單一檔案大小上限（bytes），<= 0 表示不限制
+	URLSecret     string        // 下載連結簽章用的密鑰
+	URLTTL        time.Duration // 下載連結有效時間
+	ThumbnailSize int           // 縮圖最長邊（px）
+}
+
+type AttachmentService interface {
+	MaxSize() int64
+	UploadAttachment(ctx context.Context, userID string, cardID uint, fileName string, size int64, r io.Reader) (*models.Attachment, error)
+	GetAttachment(id uint) (*models.Attachment, error)
+	GetAttachmentsByCardIDs(userID string, cardIDs []uint) (map[uint][]models.Attachment, error)
+	OpenAttachment(ctx context.Context, id uint, variant string) (*models.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, userID string, id uint) error
+	DeleteCardAttachments(ctx context.Context, cardIDs []uint) error
+	DownloadURL(attachment *models.Attachment) string
+	ThumbnailURL(attachment *models.Attachment) string
+	VerifyDownload(id uint, variant string, expires int64, signature string) error
+	SetCardCover(userID string, cardID uint, attachmentID *uint, color string) (*models.Card, error)
+}
+
+type attachmentService struct {
+	attachmentRepo repositories.AttachmentRepository
+	cardRepo       repositories.CardRepository
+	boardRepo      repositories.BoardRepository
+	store          storage.BlobStore
+	opts           AttachmentOptions
+	// 限制同時產生縮圖的數量
+	thumbnailSlots chan struct{}
+	// 縮圖產生完成後呼叫，供測試等待背景工作
+	onThumbnailDone func(attachmentID uint, err error)
+}
+
+func NewAttachmentService(
+	repo repositories.AttachmentRepository,
+	cardRepo repositories.CardRepository,
+	boardRepo repositories.BoardRepository,
+	store storage.BlobStore,
+	opts AttachmentOptions,
+) AttachmentService {
+	if opts.URLTTL <= 0 {
+		opts.URLTTL = 15 * time.Minute
+	}
+	if opts.ThumbnailSize <= 0 {
+		opts.ThumbnailSize = 256
+	}
+	return &attachmentService{
+		attachmentRepo: repo,
+		cardRepo:       cardRepo,
+		boardRepo:      boardRepo,
+		store:          store,
+		opts:           opts,
+		thumbnailSlots: make(chan struct{}, 2),
+	}
+}
+
+func (s *attachmentService) UploadAttachment(ctx context.Context, userID string, cardID uint, fileName string, size int64, r io.Reader) (*models.Attachment, error) {
+	if s.opts.MaxSize > 0 && size > s.opts.MaxSize {
+		return nil, ErrFileTooLarge
+	}
+	if _, err := s.ensureCardAccess(cardID, userID); err != nil {
+		return nil, err
+	}
+	fileName = sanitizeFileName(fileName)
+
+	// 讀取檔頭判斷實際的 content type，不信任用戶端提供的值
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+	contentType := detectContentType(head, fileName)
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head), r), size)
+
+	key := fmt.Sprintf("cards/%d/%s%s", cardID, uuid.NewString(), strings.ToLower(filepath.Ext(fileName)))
+	if err := s.store.Put(ctx, key, body, size, contentType); err != nil {
+		return nil, err
+	}
+	attachment := &models.Attachment{
+		CardID:      cardID,
+		UserID:      userID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+	}
+	if err := s.attachmentRepo.CreateAttachment(attachment); err != nil {
+		_ = s.store.Delete(ctx, key)
+		return nil, err
+	}
+	if thumbnail.Supported(contentType) {
+		go s.generateThumbnail(*attachment)
+	}
+	return attachment, nil
+}
+
+// generateThumbnail 於背景產生縮圖，失敗時僅記錄，附件本身不受影響
+func (s *attachmentService) generateThumbnail(attachment models.Attachment) {
+	s.thumbnailSlots <- struct{}{}
+	defer func() { <-s.thumbnailSlots }()
+
+	err := s.createThumbnail(context.Background(), attachment)
+	if err != nil {
+		log.Printf("產生縮圖失敗 (attachment=%d): %v", attachment.ID, err)
+	}
+	if s.onThumbnailDone != nil {
+		s.onThumbnailDone(attachment.ID, err)
+	}
+}
+
+func (s *attachmentService) createThumbnail(ctx context.Context, attachment models.Attachment) error {
+	rc, err := s.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return err
+	}
+	data, contentType, err := thumbnail.Generate(rc, s.opts.ThumbnailSize)
+	_ = rc.Close()
+	if err != nil {
+		return err
+	}
+	ext := ".png"
+	if contentType == "image/jpeg" {
+		ext = ".jpg"
+	}
+	key := attachment.StorageKey + ".thumb" + ext
+	if err := s.store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return err
+	}
+	if err := s.attachmentRepo.SetThumbnailKey(attachment.ID, key); err != nil {
+		// 附件在產生縮圖期間被刪除，清掉剛寫入的縮圖
+		_ = s.store.Delete(ctx, key)
+		return err
+	}
+	return nil
+}
+
+func (s *attachmentService) GetAttachment(id uint) (*models.Attachment, error) {
+	return s.attachmentRepo.GetAttachmentByID(id)
+}
+
+// GetAttachmentsByCardIDs 取得多張卡片的附件。結果只包含使用者看板中的卡片，
+// 其他卡片不會出現在回傳的 map 中，呼叫端不應為其產生下載連結
+func (s *attachmentService) GetAttachmentsByCardIDs(userID string, cardIDs []uint) (map[uint][]models.Attachment, error) {
+	cards, err := s.cardRepo.GetCardsByIDs(cardIDs)
+	if err != nil {
+		return nil, err
+	}
+	owners := make(map[uint]bool)
+	var allowed []uint
+	for _, c := range cards {
+		owned, ok := owners[c.BoardID]
+		if !ok {
+			_, err := ensureBoardOwner(s.boardRepo, c.BoardID, userID)
+			if err != nil && !errors.Is(err, ErrForbidden) {
+				return nil, err
+			}
+			owned = err == nil
+			owners[c.BoardID] = owned
+		}
+		if owned {
+			allowed = append(allowed, c.ID)
+		}
+	}
+	result := make(map[uint][]models.Attachment, len(allowed))
+	if len(allowed) == 0 {
+		return result, nil
+	}
+	attachments, err := s.attachmentRepo.GetAttachmentsByCardIDs(allowed)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range allowed {
+		result[id] = attachments[id]
+	}
+	return result, nil
+}
+
+func (s *attachmentService) OpenAttachment(ctx context.Context, id uint, variant string) (*models.Attachment, io.ReadCloser, error) {
+	attachment, err := s.attachmentRepo.GetAttachmentByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := attachment.StorageKey
+	if variant == AttachmentVariantThumbnail {
+		if attachment.ThumbnailKey == "" {
+			return nil, nil, storage.ErrNotFound
+		}
+		key = attachment.ThumbnailKey
+	}
+	rc, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, rc, nil
+}
+
+func (s *attachmentService) DeleteAttachment(ctx context.Context, userID string, id uint) error {
+	attachment, err := s.attachmentRepo.GetAttachmentByID(id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.ensureCardAccess(attachment.CardID, userID); err != nil {
+		return err
+	}
+	if err := s.cardRepo.ClearCoverAttachment(id); err != nil {
+		return err
+	}
+	if err := s.attachmentRepo.DeleteAttachment(id); err != nil {
+		return err
+	}
+	return s.deleteBlobs(ctx, *attachment)
+}
+
+// DeleteCardAttachments 刪除卡片的所有附件，包含儲存空間中的檔案
+func (s *attachmentService) DeleteCardAttachments(ctx context.Context, cardIDs []uint) error {
+	attachmentsByCard, err := s.attachmentRepo.GetAttachmentsByCardIDs(cardIDs)
+	if err != nil {
+		return err
+	}
+	// 先刪除資料列，檔案是否仍被其他附件引用才能正確判斷
+	if err := s.attachmentRepo.DeleteAttachmentsByCardIDs(cardIDs); err != nil {
+		return err
+	}
+	for _, attachments := range attachmentsByCard {
+		for _, a := range attachments {
+			// 單一檔案刪除失敗不影響其他檔案，僅記錄
+			if err := s.deleteBlobs(ctx, a); err != nil {
+				log.Printf("刪除附件檔案失敗 (key=%s): %v", a.StorageKey, err)
+			}
+		}
+	}
+	return nil
+}
+
+// deleteBlobs 刪除附件在儲存空間中的檔案，仍被其他附件（例如複製的卡片）引用的檔案會保留
+func (s *attachmentService) deleteBlobs(ctx context.Context, attachment models.Attachment) error {
+	for _, key := range []string{attachment.ThumbnailKey, attachment.StorageKey} {
+		if key == "" {
+			continue
+		}
+		refs, err := s.attachmentRepo.CountByStorageKey(key)
+		if err != nil {
+			return err
+		}
+		if refs > 0 {
+			continue
+		}
+		if err := s.store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MaxSize 單一檔案大小上限（bytes），<= 0 表示不限制
+func (s *attachmentService) MaxSize() int64 {
+	return s.opts.MaxSize
+}
+
+// DownloadURL 產生有時效的簽章下載連結
+func (s *attachmentService) DownloadURL(attachment *models.Attachment) string {
+	return s.signedURL(attachment.ID, AttachmentVariantOriginal, "download")
+}
+
+// ThumbnailURL 產生縮圖的簽章連結，尚無縮圖時回傳空字串
+func (s *attachmentService) ThumbnailURL(attachment *models.Attachment) string {
+	if attachment.ThumbnailKey == "" {
+		return ""
+	}
+	return s.signedURL(attachment.ID, AttachmentVariantThumbnail, "thumbnail")
+}
+
+func (s *attachmentService) signedURL(id uint, variant, path string) string {
+	expires := time.Now().Add(s.opts.URLTTL).Unix()
+	return fmt.Sprintf("/api/attachments/%d/%s?expires=%d&signature=%s",
+		id, path, expires, s.sign(id, variant, expires))
+}
+
+func (s *attachmentService) VerifyDownload(id uint, variant string, expires int64, signature string) error {
+	if time.Now().Unix() > expires {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(id, variant, expires))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *attachmentService) sign(id uint, variant string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.opts.URLSecret))
+	mac.Write([]byte(strconv.FormatUint(uint64(id), 10) + ":" + variant + ":" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SetCardCover 設定卡片封面；attachmentID 與 color 擇一，兩者皆空則移除封面。
+// 看板停用卡片封面時仍可移除封面
+func (s *attachmentService) SetCardCover(userID string, cardID uint, attachmentID *uint, color string) (*models.Card, error) {
+	if attachmentID != nil && color != "" {
+		return nil, ErrInvalidCover
+	}
+	if color != "" && !coverColorPattern.MatchString(color) {
+		return nil, ErrInvalidCover
+	}
+	card, err := s.cardRepo.GetCardByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+	board, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if board.Settings.CardCoversDisabled && (attachmentID != nil || color != "") {
+		return nil, ErrCoversDisabled
+	}
+	if attachmentID != nil {
+		attachment, err := s.attachmentRepo.GetAttachmentByID(*attachmentID)
+		if err != nil {
+			return nil, err
+		}
+		if attachment.CardID != cardID || !thumbnail.Supported(attachment.ContentType) {
+			return nil, ErrInvalidCover
+		}
+	}
+	card.CoverAttachmentID = attachmentID
+	card.CoverColor = strings.ToLower(color)
+	if err := s.cardRepo.UpdateCard(card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func (s *attachmentService) ensureCardAccess(cardID uint, userID string) (*models.Card, error) {
+	card, err := s.cardRepo.GetCardByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	return name
+}
+
+// detectContentType 依檔案內容判斷型別，無法判斷時才參考副檔名
+func detectContentType(head []byte, fileName string) string {
+	contentType := http.DetectContentType(head)
+	if contentType == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+			return byExt
+		}
+	}
+	return contentType
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+	"trello-backend/internal/models"
+	"trello-backend/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) CreateAttachment(attachment *models.Attachment) error {
+	args := m.Called(attachment)
+	return args.Error(0)
+}
+func (m *MockAttachmentRepository) GetAttachmentByID(id uint) (*models.Attachment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Attachment), args.Error(1)
+}
+func (m *MockAttachmentRepository) GetAttachmentsByCardIDs(cardIDs []uint) (map[uint][]models.Attachment, error) {
+	args := m.Called(cardIDs)
+	return args.Get(0).(map[uint][]models.Attachment), args.Error(1)
+}
+func (m *MockAttachmentRepository) SetThumbnailKey(id uint, key string) error {
+	args := m.Called(id, key)
+	return args.Error(0)
+}
+func (m *MockAttachmentRepository) DeleteAttachment(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+func (m *MockAttachmentRepository) DeleteAttachmentsByCardIDs(cardIDs []uint) error {
+	args := m.Called(cardIDs)
+	return args.Error(0)
+}
+func (m *MockAttachmentRepository) CountByStorageKey(key string) (int64, error) {
+	args := m.Called(key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// 1x1 PNG
+var pngBytes = []byte{
+	0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d,
+	0x49, 0x48, 0x44, 0x52, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
+	0x08, 0x06, 0x00, 0x00, 0x00, 0x1f, 0x15, 0xc4, 0x89,
+}
+
+func newTestAttachmentService(t *testing.T, maxSize int64) (*attachmentService, *MockAttachmentRepository, *MockCardRepository, *MockBoardRepository, storage.BlobStore) {
+	store, err := storage.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+	repo := new(MockAttachmentRepository)
+	cardRepo := new(MockCardRepository)
+	boardRepo := new(MockBoardRepository)
+	service := NewAttachmentService(repo, cardRepo, boardRepo, store, AttachmentOptions{MaxSize: maxSize, URLSecret: "secret", ThumbnailSize: 32}).(*attachmentService)
+	return service, repo, cardRepo, boardRepo, store
+}
+
+// waitThumbnail 讓測試等待背景縮圖工作結束
+func waitThumbnail(service *attachmentService) <-chan error {
+	done := make(chan error, 1)
+	service.onThumbnailDone = func(_ uint, err error) { done <- err }
+	return done
+}
+
+func TestAttachmentService_Upload(t *testing.T) {
+	service, repo, cardRepo, boardRepo, store := newTestAttachmentService(t, 1024)
+	cardRepo.On("GetCardByID", uint(1)).Return(&models.Card{ID: 1, BoardID: 2}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+	repo.On("CreateAttachment", mock.Anything).Return(nil)
+	done := waitThumbnail(service)
+
+	// 副檔名與內容不符時，以內容判斷的型別為準
+	a, err := service.UploadAttachment(context.Background(), "user-1", 1, "../avatar.txt", int64(len(pngBytes)), bytes.NewReader(pngBytes))
+
+	require.NoError(t, err)
+	// 截斷的 PNG 無法產生縮圖，但不影響上傳結果
+	assert.Error(t, <-done)
+	repo.AssertExpectations(t)
+	assert.Equal(t, "avatar.txt", a.FileName)
+	assert.Equal(t, "image/png", a.ContentType)
+	rc, err := store.Get(context.Background(), a.StorageKey)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	assert.Equal(t, pngBytes, data)
+}
+
+func TestAttachmentService_Upload_TooLarge(t *testing.T) {
+	service, repo, cardRepo, _, _ := newTestAttachmentService(t, 10)
+
+	_, err := service.UploadAttachment(context.Background(), "user-1", 1, "big.bin", 11, bytes.NewReader(make([]byte, 11)))
+
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+	cardRepo.AssertNotCalled(t, "GetCardByID", mock.Anything)
+	repo.AssertNotCalled(t, "CreateAttachment", mock.Anything)
+}
+
+func TestAttachmentService_Upload_Forbidden(t *testing.T) {
+	service, repo, cardRepo, boardRepo, _ := newTestAttachmentService(t, 1024)
+	cardRepo.On("GetCardByID", uint(1)).Return(&models.Card{ID: 1, BoardID: 2}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "owner"}, nil)
+
+	_, err := service.UploadAttachment(context.Background(), "someone-else", 1, "a.png", int64(len(pngBytes)), bytes.NewReader(pngBytes))
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "CreateAttachment", mock.Anything)
+}
+
+func TestAttachmentService_DownloadURL(t *testing.T) {
+	service, _, _, _, _ := newTestAttachmentService(t, 0)
+
+	raw := service.DownloadURL(&models.Attachment{ID: 7})
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	signature := u.Query().Get("signature")
+
+	assert.NoError(t, service.VerifyDownload(7, AttachmentVariantOriginal, expires, signature))
+	// 換成其他附件 ID、其他版本或竄改到期時間都應失敗
+	assert.ErrorIs(t, service.VerifyDownload(8, AttachmentVariantOriginal, expires, signature), ErrInvalidSignature)
+	assert.ErrorIs(t, service.VerifyDownload(7, AttachmentVariantThumbnail, expires, signature), ErrInvalidSignature)
+	assert.ErrorIs(t, service.VerifyDownload(7, AttachmentVariantOriginal, expires+60, signature), ErrInvalidSignature)
+	assert.ErrorIs(t, service.VerifyDownload(7, AttachmentVariantOriginal, time.Now().Add(-time.Minute).Unix(), signature), ErrInvalidSignature)
+	// 尚未產生縮圖時不提供縮圖連結
+	assert.Empty(t, service.ThumbnailURL(&models.Attachment{ID: 7}))
+}
+
+func TestAttachmentService_Upload_GeneratesThumbnail(t *testing.T) {
+	service, repo, cardRepo, boardRepo, store := newTestAttachmentService(t, 0)
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 128, 64))))
+	cardRepo.On("GetCardByID", uint(1)).Return(&models.Card{ID: 1, BoardID: 2}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+	repo.On("CreateAttachment", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Attachment).ID = 9
+	}).Return(nil)
+	var thumbKey string
+	repo.On("SetThumbnailKey", uint(9), mock.Anything).Run(func(args mock.Arguments) {
+		thumbKey = args.String(1)
+	}).Return(nil)
+	done := waitThumbnail(service)
+
+	a, err := service.UploadAttachment(context.Background(), "user-1", 1, "photo.png", int64(buf.Len()), bytes.NewReader(buf.Bytes()))
+
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+	assert.Equal(t, a.StorageKey+".thumb.png", thumbKey)
+	rc, err := store.Get(context.Background(), thumbKey)
+	require.NoError(t, err)
+	cfg, err := png.DecodeConfig(rc)
+	_ = rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, 32, cfg.Width)
+	assert.Equal(t, 16, cfg.Height)
+}
+
+func TestAttachmentService_SetCardCover(t *testing.T) {
+	service, repo, cardRepo, boardRepo, _ := newTestAttachmentService(t, 0)
+	card := &models.Card{ID: 1, BoardID: 2}
+	cardRepo.On("GetCardByID", uint(1)).Return(card, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+	repo.On("GetAttachmentByID", uint(5)).Return(&models.Attachment{ID: 5, CardID: 1, ContentType: "image/jpeg"}, nil)
+	repo.On("GetAttachmentByID", uint(6)).Return(&models.Attachment{ID: 6, CardID: 1, ContentType: "application/pdf"}, nil)
+	cardRepo.On("UpdateCard", card).Return(nil)
+
+	attachmentID := uint(5)
+	_, err := service.SetCardCover("user-1", 1, &attachmentID, "")
+	require.NoError(t, err)
+	assert.Equal(t, &attachmentID, card.CoverAttachmentID)
+
+	_, err = service.SetCardCover("user-1", 1, nil, "#FFAA00")
+	require.NoError(t, err)
+	assert.Nil(t, card.CoverAttachmentID)
+	assert.Equal(t, "#ffaa00", card.CoverColor)
+
+	pdfID := uint(6)
+	_, err = service.SetCardCover("user-1", 1, &pdfID, "")
+	assert.ErrorIs(t, err, ErrInvalidCover)
+	_, err = service.SetCardCover("user-1", 1, nil, "red")
+	assert.ErrorIs(t, err, ErrInvalidCover)
+}
+
+func TestAttachmentService_SetCardCover_Disabled(t *testing.T) {
+	service, _, cardRepo, boardRepo, _ := newTestAttachmentService(t, 0)
+	card := &models.Card{ID: 1, BoardID: 2, CoverColor: "#ffaa00"}
+	cardRepo.On("GetCardByID", uint(1)).Return(card, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{
+		ID: 2, UserID: "user-1", Settings: models.BoardSettings{CardCoversDisabled: true},
+	}, nil)
+	cardRepo.On("UpdateCard", card).Return(nil)
+
+	_, err := service.SetCardCover("user-1", 1, nil, "#000000")
+	assert.ErrorIs(t, err, ErrCoversDisabled)
+
+	// 停用後仍可移除既有封面
+	_, err = service.SetCardCover("user-1", 1, nil, "")
+	require.NoError(t, err)
+	assert.Empty(t, card.CoverColor)
+}
+
+func TestAttachmentService_GetAttachmentsByCardIDs(t *testing.T) {
+	service, repo, cardRepo, boardRepo, _ := newTestAttachmentService(t, 0)
+	cardRepo.On("GetCardsByIDs", []uint{1, 2, 3}).Return([]models.Card{
+		{ID: 1, BoardID: 5}, {ID: 2, BoardID: 6}, {ID: 3, BoardID: 5},
+	}, nil)
+	boardRepo.On("GetBoardByID", uint(5)).Return(&models.Board{ID: 5, UserID: "user-1"}, nil).Once()
+	boardRepo.On("GetBoardByID", uint(6)).Return(&models.Board{ID: 6, UserID: "user-2"}, nil).Once()
+	repo.On("GetAttachmentsByCardIDs", []uint{1, 3}).Return(map[uint][]models.Attachment{
+		1: {{ID: 10, CardID: 1}},
+	}, nil)
+
+	result, err := service.GetAttachmentsByCardIDs("user-1", []uint{1, 2, 3})
+
+	require.NoError(t, err)
+	boardRepo.AssertExpectations(t)
+	// 其他使用者看板中的卡片不會出現在結果中；沒有附件的卡片為空
+	assert.Len(t, result[1], 1)
+	assert.NotContains(t, result, uint(2))
+	assert.Contains(t, result, uint(3))
+	assert.Empty(t, result[3])
+}
+
+func TestAttachmentService_DeleteCardAttachments(t *testing.T) {
+	service, repo, _, _, store := newTestAttachmentService(t, 0)
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "cards/1/a.png", bytes.NewReader(pngBytes), int64(len(pngBytes)), "image/png"))
+	repo.On("GetAttachmentsByCardIDs", []uint{1}).Return(map[uint][]models.Attachment{
+		1: {{ID: 1, CardID: 1, StorageKey: "cards/1/a.png"}},
+	}, nil)
+	repo.On("DeleteAttachmentsByCardIDs", []uint{1}).Return(nil)
+	repo.On("CountByStorageKey", "cards/1/a.png").Return(int64(0), nil)
+
+	err := service.DeleteCardAttachments(ctx, []uint{1})
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	_, err = store.Get(ctx, "cards/1/a.png")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestAttachmentService_DeleteCardAttachments_SharedBlob(t *testing.T) {
+	service, repo, _, _, store := newTestAttachmentService(t, 0)
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "cards/1/a.png", bytes.NewReader(pngBytes), int64(len(pngBytes)), "image/png"))
+	repo.On("GetAttachmentsByCardIDs", []uint{2}).Return(map[uint][]models.Attachment{
+		2: {{ID: 2, CardID: 2, StorageKey: "cards/1/a.png"}},
+	}, nil)
+	repo.On("DeleteAttachmentsByCardIDs", []uint{2}).Return(nil)
+	// 原卡片的附件仍引用同一份檔案
+	repo.On("CountByStorageKey", "cards/1/a.png").Return(int64(1), nil)
+
+	err := service.DeleteCardAttachments(ctx, []uint{2})
+
+	assert.NoError(t, err)
+	rc, err := store.Get(ctx, "cards/1/a.png")
+	require.NoError(t, err)
+	rc.Close()
+}
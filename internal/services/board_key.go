@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"trello-backend/internal/repositories"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrInvalidBoardKey = errors.New("看板代號須為 2 到 10 個英文字母或數字，且以字母開頭")
+	ErrBoardKeyTaken   = errors.New("看板代號已被使用")
+	// ErrInvalidCardReference 卡片參照不是「代號-編號」的格式，例如 WEB-142
+	ErrInvalidCardReference = errors.New("無效的卡片參照")
+)
+
+const maxBoardKeyLength = 10
+
+// maxBoardKeyAttempts 自動產生的代號在建立前被同時建立的看板取走時，最多重新挑選的次數
+const maxBoardKeyAttempts = 3
+
+var boardKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// normalizeBoardKey 將代號轉為大寫並驗證格式
+func normalizeBoardKey(key string) (string, error) {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if !boardKeyPattern.MatchString(key) {
+		return "", ErrInvalidBoardKey
+	}
+	return key, nil
+}
+
+// deriveBoardKey 由看板名稱產生代號：先去除字母上的重音符號，多個單字時取各單字字首（最多 4 個），
+// 單一單字時取前 3 個字元。名稱中沒有足夠的英文字母時（例如中文名稱），以 B 加上名稱雜湊的 3 個字元
+// 產生固定的代號，讓不同名稱的看板得到不同的代號；名稱為空時使用 BRD
+func deriveBoardKey(name string) string {
+	words := strings.FieldsFunc(strings.ToUpper(stripMarks(name)), func(r rune) bool {
+		return (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+	})
+	var key string
+	switch {
+	case len(words) > 1:
+		for _, w := range words {
+			key += w[:1]
+		}
+	case len(words) == 1:
+		key = words[0]
+	}
+	key = strings.TrimLeft(key, "0123456789")
+	if len(words) > 1 {
+		key = key[:min(len(key), 4)]
+	} else {
+		key = key[:min(len(key), 3)]
+	}
+	if len(key) < 2 {
+		return hashBoardKey(name)
+	}
+	return key
+}
+
+// stripMarks 將 é、ü 等字母分解後去除附加符號，只保留基本字母
+func stripMarks(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(s))
+}
+
+// hashBoardKey 以名稱的雜湊產生 B 開頭的 4 字元代號
+func hashBoardKey(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "BRD"
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // 不含容易混淆的 I、O、0、1
+	sum := h.Sum32()
+	key := []byte{'B', 0, 0, 0}
+	for i := 1; i < len(key); i++ {
+		key[i] = alphabet[sum%uint32(len(alphabet))]
+		sum /= uint32(len(alphabet))
+	}
+	return string(key)
+}
+
+// uniqueBoardKey 在 base 後加上 2、3… 直到不與使用者其他看板的代號重複
+func uniqueBoardKey(boardRepo repositories.BoardRepository, userID, base string) (string, error) {
+	key := base
+	for i := 2; ; i++ {
+		taken, err := boardRepo.KeyExists(userID, key)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return key, nil
+		}
+		suffix := strconv.Itoa(i)
+		key = base[:min(len(base), maxBoardKeyLength-len(suffix))] + suffix
+	}
+}
+
+// createWithUniqueKey 以 base 挑選不重複的代號並呼叫 create 建立看板；挑選後到建立前
+// 代號被同時建立的看板取走時重新挑選，多次仍衝突時回傳 ErrBoardKeyTaken
+func createWithUniqueKey(boardRepo repositories.BoardRepository, userID, base string, create func(key string) error) error {
+	for attempt := 1; ; attempt++ {
+		key, err := uniqueBoardKey(boardRepo, userID, base)
+		if err != nil {
+			return err
+		}
+		err = create(key)
+		if !errors.Is(err, repositories.ErrBoardKeyConflict) {
+			return err
+		}
+		if attempt == maxBoardKeyAttempts {
+			return ErrBoardKeyTaken
+		}
+	}
+}
+
+// boardKeyTaken 將唯一索引衝突轉為 ErrBoardKeyTaken，用於使用者指定的代號
+func boardKeyTaken(err error) error {
+	if errors.Is(err, repositories.ErrBoardKeyConflict) {
+		return ErrBoardKeyTaken
+	}
+	return err
+}
+
+// parseCardReference 解析 WEB-142 格式的卡片參照，代號不分大小寫
+func parseCardReference(ref string) (string, int, error) {
+	key, num, ok := strings.Cut(strings.TrimSpace(ref), "-")
+	if !ok {
+		return "", 0, ErrInvalidCardReference
+	}
+	key, err := normalizeBoardKey(key)
+	if err != nil {
+		return "", 0, ErrInvalidCardReference
+	}
+	number, err := strconv.Atoi(num)
+	if err != nil || number < 1 {
+		return "", 0, ErrInvalidCardReference
+	}
+	return key, number, nil
+}
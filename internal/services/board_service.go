@@ -1,19 +1,40 @@
 package services
 
 import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
 	"trello-backend/internal/models"
 	"trello-backend/internal/repositories"
 )
 
 type BoardService interface {
-	CreateBoard(name string, userID string, position int) (*models.Board, error)
+	CreateBoard(name, key string, userID string, position int) (*models.Board, error)
 	GetBoard(id uint) (*models.Board, error)
-	UpdateBoard(id uint, name string) error
-	DeleteBoard(id uint) error
+	GetReadableBoard(userID string, id uint) (*models.Board, error)
+	UpdateBoard(userID string, id uint, name, key string, settings *BoardSettingsPatch) error
+	DeleteBoard(userID string, id uint) error
 	GetBoardsByUserID(userID string) ([]models.Board, error)
 	UpdateBoardPosition(id uint, position int) error
+	ArchiveBoard(userID string, id uint) (*models.Board, error)
+	UnarchiveBoard(userID string, id uint) (*models.Board, error)
+	GetArchivedBoards(userID string) ([]models.Board, error)
+	GetBoardKeys(ids []uint) (map[uint]string, error)
+	UpdateBoardSettings(userID string, id uint, patch BoardSettingsPatch) (*models.Board, error)
+	SetTemplate(userID string, id uint, isTemplate bool) (*models.Board, error)
+	GetTemplates(userID string) ([]models.Board, error)
+	CloseBoard(userID string, id uint) (*models.Board, error)
+	ReopenBoard(userID string, id uint) (*models.Board, error)
+	GetClosedBoards(userID string) ([]models.Board, error)
 }
 
+var (
+	ErrInvalidBoardBackground = errors.New("看板背景需為一個 #rrggbb 顏色或一個 http(s) 圖片網址")
+	ErrInvalidBoardVisibility = errors.New("無效的看板可見範圍")
+)
+
 type boardService struct {
 	boardRepo repositories.BoardRepository
 }
@@ -22,11 +43,27 @@ func NewBoardService(repo repositories.BoardRepository) BoardService {
 	return &boardService{boardRepo: repo}
 }
 
-func (s *boardService) CreateBoard(name string, userID string, position int) (*models.Board, error) {
+// CreateBoard 建立看板，key 為空時由名稱產生不重複的代號
+func (s *boardService) CreateBoard(name, key string, userID string, position int) (*models.Board, error) {
 	board := &models.Board{Name: name, UserID: userID, Position: position}
-	if err := s.boardRepo.CreateBoard(board); err != nil {
+	if key == "" {
+		err := createWithUniqueKey(s.boardRepo, userID, deriveBoardKey(name), func(key string) error {
+			board.Key = key
+			return s.boardRepo.CreateBoard(board)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return board, nil
+	}
+	key, err := s.checkBoardKey(userID, key)
+	if err != nil {
 		return nil, err
 	}
+	board.Key = key
+	if err := s.boardRepo.CreateBoard(board); err != nil {
+		return nil, boardKeyTaken(err)
+	}
 	return board, nil
 }
 
@@ -34,16 +71,59 @@ func (s *boardService) GetBoard(id uint) (*models.Board, error) {
 	return s.boardRepo.GetBoardByID(id)
 }
 
-func (s *boardService) UpdateBoard(id uint, name string) error {
-	board, err := s.boardRepo.GetBoardByID(id)
+// GetReadableBoard 取得使用者能閱讀的看板：自己的看板或公開的看板
+func (s *boardService) GetReadableBoard(userID string, id uint) (*models.Board, error) {
+	return ensureBoardReadable(s.boardRepo, id, userID)
+}
+
+// UpdateBoard 更新看板，name 為空時不變更名稱；key 不為空時一併變更代號，既有卡片的參照會隨之改變；
+// settings 不為 nil 時一併變更設定
+func (s *boardService) UpdateBoard(userID string, id uint, name, key string, settings *BoardSettingsPatch) error {
+	board, err := ensureBoardOwner(s.boardRepo, id, userID)
 	if err != nil {
 		return err
 	}
-	board.Name = name
-	return s.boardRepo.UpdateBoard(board)
+	if name != "" {
+		board.Name = name
+	}
+	if settings != nil {
+		if err := applyBoardSettings(&board.Settings, *settings); err != nil {
+			return err
+		}
+	}
+	if key != "" && !strings.EqualFold(key, board.Key) {
+		if board.Key, err = s.checkBoardKey(board.UserID, key); err != nil {
+			return err
+		}
+	}
+	return boardKeyTaken(s.boardRepo.UpdateBoard(board))
 }
 
-func (s *boardService) DeleteBoard(id uint) error {
+// checkBoardKey 驗證使用者指定的代號格式，並確認沒有被其他看板使用
+func (s *boardService) checkBoardKey(userID, key string) (string, error) {
+	key, err := normalizeBoardKey(key)
+	if err != nil {
+		return "", err
+	}
+	taken, err := s.boardRepo.KeyExists(userID, key)
+	if err != nil {
+		return "", err
+	}
+	if taken {
+		return "", ErrBoardKeyTaken
+	}
+	return key, nil
+}
+
+func (s *boardService) GetBoardKeys(ids []uint) (map[uint]string, error) {
+	return s.boardRepo.GetBoardKeysByIDs(ids)
+}
+
+// DeleteBoard 將看板移至垃圾桶，只有擁有者能刪除；已關閉的看板也可以刪除
+func (s *boardService) DeleteBoard(userID string, id uint) error {
+	if _, err := ensureBoardOwner(s.boardRepo, id, userID); err != nil {
+		return err
+	}
 	return s.boardRepo.DeleteBoard(id)
 }
 
@@ -61,3 +141,234 @@ func (s *boardService) UpdateBoardPosition(id uint, position int) error {
 	board.Position = position
 	return s.boardRepo.UpdateBoard(board)
 }
+
+// ArchiveBoard 封存看板，並將使用者其他看板的位置往前補位
+func (s *boardService) ArchiveBoard(userID string, id uint) (*models.Board, error) {
+	board, err := ensureBoardOwner(s.boardRepo, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if board.ArchivedAt != nil {
+		return board, nil
+	}
+	if board.ClosedAt == nil {
+		if err := s.leaveBoardOrder(board); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	board.ArchivedAt = &now
+	if err := s.boardRepo.UpdateBoard(board); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// UnarchiveBoard 還原看板，盡量放回封存前的位置
+func (s *boardService) UnarchiveBoard(userID string, id uint) (*models.Board, error) {
+	board, err := ensureBoardOwner(s.boardRepo, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if board.ArchivedAt == nil {
+		return board, nil
+	}
+	if board.ClosedAt == nil {
+		if err := s.rejoinBoardOrder(board); err != nil {
+			return nil, err
+		}
+	}
+	board.ArchivedAt = nil
+	if err := s.boardRepo.UpdateBoard(board); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// SetTemplate 設定看板是否為範本
+func (s *boardService) SetTemplate(userID string, id uint, isTemplate bool) (*models.Board, error) {
+	board, err := ensureBoardOwner(s.boardRepo, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	board.IsTemplate = isTemplate
+	if err := s.boardRepo.UpdateBoard(board); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// GetTemplates 取得範本庫：使用者自己的範本與其他使用者公開的範本，依名稱排列
+func (s *boardService) GetTemplates(userID string) ([]models.Board, error) {
+	var boards []models.Board
+	err := s.boardRepo.FindTemplateBoards(userID, &boards)
+	return boards, err
+}
+
+func (s *boardService) GetArchivedBoards(userID string) ([]models.Board, error) {
+	var boards []models.Board
+	err := s.boardRepo.FindArchivedBoardsByUserID(userID, &boards)
+	return boards, err
+}
+
+// CloseBoard 關閉看板：看板變為唯讀並離開看板列表，使用者其他看板的位置往前補位
+func (s *boardService) CloseBoard(userID string, id uint) (*models.Board, error) {
+	board, err := ensureBoardOwner(s.boardRepo, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if board.ClosedAt != nil {
+		return board, nil
+	}
+	if board.ArchivedAt == nil {
+		if err := s.leaveBoardOrder(board); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	board.ClosedAt = &now
+	if err := s.boardRepo.UpdateBoard(board); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// ReopenBoard 重新開啟看板，盡量放回關閉前的位置
+func (s *boardService) ReopenBoard(userID string, id uint) (*models.Board, error) {
+	board, err := ensureBoardOwner(s.boardRepo, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if board.ClosedAt == nil {
+		return board, nil
+	}
+	if board.ArchivedAt == nil {
+		if err := s.rejoinBoardOrder(board); err != nil {
+			return nil, err
+		}
+	}
+	board.ClosedAt = nil
+	if err := s.boardRepo.UpdateBoard(board); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// GetClosedBoards 取得使用者已關閉且未封存的看板，最近關閉的在前
+func (s *boardService) GetClosedBoards(userID string) ([]models.Board, error) {
+	var boards []models.Board
+	err := s.boardRepo.FindClosedBoardsByUserID(userID, &boards)
+	return boards, err
+}
+
+// leaveBoardOrder 將看板移出使用者的看板列表，後面的看板往前補位
+func (s *boardService) leaveBoardOrder(board *models.Board) error {
+	boards, err := s.GetBoardsByUserID(board.UserID)
+	if err != nil {
+		return err
+	}
+	for _, b := range boards {
+		if b.ID != board.ID && b.Position > board.Position {
+			b.Position--
+			if err := s.boardRepo.UpdateBoard(&b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rejoinBoardOrder 將看板放回使用者的看板列表，盡量放在原本的位置，其後的看板往後移
+func (s *boardService) rejoinBoardOrder(board *models.Board) error {
+	boards, err := s.GetBoardsByUserID(board.UserID)
+	if err != nil {
+		return err
+	}
+	position := min(max(board.Position, 0), len(boards))
+	for _, b := range boards {
+		if b.Position >= position {
+			b.Position++
+			if err := s.boardRepo.UpdateBoard(&b); err != nil {
+				return err
+			}
+		}
+	}
+	board.Position = position
+	return nil
+}
+
+// BoardSettingsPatch 要變更的看板設定，nil 表示不變更；背景顏色或圖片設為空字串表示移除
+type BoardSettingsPatch struct {
+	Description        *string
+	BackgroundColor    *string
+	BackgroundImageURL *string
+	Visibility         *models.BoardVisibility
+	CommentsEnabled    *bool
+	VotingEnabled      *bool
+	CardCoversEnabled  *bool
+}
+
+// UpdateBoardSettings 變更看板的外觀與功能設定
+func (s *boardService) UpdateBoardSettings(userID string, id uint, patch BoardSettingsPatch) (*models.Board, error) {
+	board, err := ensureBoardOwner(s.boardRepo, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyBoardSettings(&board.Settings, patch); err != nil {
+		return nil, err
+	}
+	if err := s.boardRepo.UpdateBoard(board); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// applyBoardSettings 驗證並套用設定；設定背景顏色會移除背景圖片，反之亦然
+func applyBoardSettings(settings *models.BoardSettings, patch BoardSettingsPatch) error {
+	color, image := patch.BackgroundColor, patch.BackgroundImageURL
+	if color != nil && *color != "" && image != nil && *image != "" {
+		return ErrInvalidBoardBackground
+	}
+	if color != nil {
+		if *color != "" && !coverColorPattern.MatchString(*color) {
+			return ErrInvalidBoardBackground
+		}
+		settings.BackgroundColor = strings.ToLower(*color)
+		if *color != "" {
+			settings.BackgroundImageURL = ""
+		}
+	}
+	if image != nil {
+		if *image != "" && !isHTTPURL(*image) {
+			return ErrInvalidBoardBackground
+		}
+		settings.BackgroundImageURL = *image
+		if *image != "" {
+			settings.BackgroundColor = ""
+		}
+	}
+	if v := patch.Visibility; v != nil {
+		if *v != models.BoardPrivate && *v != models.BoardWorkspace && *v != models.BoardPublic {
+			return ErrInvalidBoardVisibility
+		}
+		settings.Visibility = *v
+	}
+	if patch.Description != nil {
+		settings.Description = strings.TrimSpace(*patch.Description)
+	}
+	if patch.CommentsEnabled != nil {
+		settings.CommentsDisabled = !*patch.CommentsEnabled
+	}
+	if patch.VotingEnabled != nil {
+		settings.VotingDisabled = !*patch.VotingEnabled
+	}
+	if patch.CardCoversEnabled != nil {
+		settings.CardCoversDisabled = !*patch.CardCoversEnabled
+	}
+	return nil
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
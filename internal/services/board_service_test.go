@@ -2,8 +2,10 @@ package services
 
 import (
 	"testing"
+	"time"
 
 	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -38,21 +40,120 @@ func (m *MockBoardRepository) FindBoardsByUserID(userID string, boards *[]models
 	return args.Error(0)
 }
 
+func (m *MockBoardRepository) FindArchivedBoardsByUserID(userID string, boards *[]models.Board) error {
+	args := m.Called(userID, boards)
+	return args.Error(0)
+}
+
+func (m *MockBoardRepository) FindClosedBoardsByUserID(userID string, boards *[]models.Board) error {
+	args := m.Called(userID, boards)
+	return args.Error(0)
+}
+
+func (m *MockBoardRepository) KeyExists(userID, key string) (bool, error) {
+	args := m.Called(userID, key)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBoardRepository) GetBoardKeysByIDs(ids []uint) (map[uint]string, error) {
+	args := m.Called(ids)
+	return args.Get(0).(map[uint]string), args.Error(1)
+}
+
+func (m *MockBoardRepository) FindTemplateBoards(userID string, boards *[]models.Board) error {
+	args := m.Called(userID, boards)
+	return args.Error(0)
+}
+
 func TestBoardService_CreateBoard(t *testing.T) {
 	repo := new(MockBoardRepository)
 	service := NewBoardService(repo)
 
-	board := &models.Board{Name: "Test Board", UserID: "", Position: 0}
+	board := &models.Board{Name: "Test Board", Key: "TB", UserID: "", Position: 0}
+	repo.On("KeyExists", "", "TB").Return(false, nil)
 	repo.On("CreateBoard", board).Return(nil)
 
-	// 新增 userID 參數，測試用空字串，position 預設 0
-	result, err := service.CreateBoard("Test Board", "", 0)
+	// 新增 userID 參數，測試用空字串，position 預設 0；未指定代號時由名稱產生
+	result, err := service.CreateBoard("Test Board", "", "", 0)
 
 	repo.AssertExpectations(t)
 	assert.NoError(t, err)
 	assert.Equal(t, "Test Board", result.Name)
 }
 
+func TestBoardService_GetReadableBoard(t *testing.T) {
+	repo := new(MockBoardRepository)
+	service := NewBoardService(repo)
+	repo.On("GetBoardByID", uint(1)).Return(&models.Board{ID: 1, UserID: "u1"}, nil)
+	repo.On("GetBoardByID", uint(2)).Return(&models.Board{
+		ID: 2, UserID: "u1", Settings: models.BoardSettings{Visibility: models.BoardPublic},
+	}, nil)
+
+	_, err := service.GetReadableBoard("u2", 1)
+	assert.ErrorIs(t, err, ErrForbidden)
+	board, err := service.GetReadableBoard("u2", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), board.ID)
+}
+
+func TestBoardService_CreateBoard_KeyTaken(t *testing.T) {
+	repo := new(MockBoardRepository)
+	service := NewBoardService(repo)
+	repo.On("KeyExists", "u1", "WEB").Return(true, nil)
+
+	_, err := service.CreateBoard("Website", "web", "u1", 0)
+
+	assert.ErrorIs(t, err, ErrBoardKeyTaken)
+	repo.AssertNotCalled(t, "CreateBoard", mock.Anything)
+}
+
+func TestDeriveBoardKey(t *testing.T) {
+	assert.Equal(t, "WEB", deriveBoardKey("Website"))
+	assert.Equal(t, "MAR", deriveBoardKey("Mobile App Redesign"))
+	assert.Equal(t, "QPRR", deriveBoardKey("2025 Q2 planning roadmap review"))
+	assert.Equal(t, "EC", deriveBoardKey("Équipe Café"))
+	assert.Equal(t, "BRD", deriveBoardKey("  "))
+
+	// 沒有英文字母的名稱依名稱產生固定且彼此不同的代號
+	product, marketing := deriveBoardKey("產品規劃"), deriveBoardKey("行銷活動")
+	assert.Regexp(t, boardKeyPattern, product)
+	assert.Equal(t, product, deriveBoardKey("產品規劃"))
+	assert.NotEqual(t, product, marketing)
+}
+
+func TestBoardService_CreateBoard_KeyConflictRetries(t *testing.T) {
+	repo := new(MockBoardRepository)
+	service := NewBoardService(repo)
+	// WEB 在檢查後被同時建立的看板取走，重新挑選 WEB2
+	repo.On("KeyExists", "u1", "WEB").Return(false, nil).Once()
+	repo.On("CreateBoard", mock.MatchedBy(func(b *models.Board) bool { return b.Key == "WEB" })).Return(repositories.ErrBoardKeyConflict).Once()
+	repo.On("KeyExists", "u1", "WEB").Return(true, nil)
+	repo.On("KeyExists", "u1", "WEB2").Return(false, nil)
+	repo.On("CreateBoard", mock.MatchedBy(func(b *models.Board) bool { return b.Key == "WEB2" })).Return(nil)
+
+	board, err := service.CreateBoard("Website", "", "u1", 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "WEB2", board.Key)
+
+	// 使用者指定的代號衝突時不重新挑選
+	repo.On("KeyExists", "u1", "APP").Return(false, nil)
+	repo.On("CreateBoard", mock.MatchedBy(func(b *models.Board) bool { return b.Key == "APP" })).Return(repositories.ErrBoardKeyConflict)
+	_, err = service.CreateBoard("App", "app", "u1", 0)
+	assert.ErrorIs(t, err, ErrBoardKeyTaken)
+}
+
+func TestUniqueBoardKey_TruncatesBeforeSuffix(t *testing.T) {
+	repo := new(MockBoardRepository)
+	repo.On("KeyExists", "u1", "ABCDEFGHIJ").Return(true, nil)
+	repo.On("KeyExists", "u1", "ABCDEFGHI2").Return(false, nil)
+
+	key, err := uniqueBoardKey(repo, "u1", "ABCDEFGHIJ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ABCDEFGHI2", key)
+}
+
 func TestBoardService_GetBoard(t *testing.T) {
 	repo := new(MockBoardRepository)
 	service := NewBoardService(repo)
@@ -72,25 +173,190 @@ func TestBoardService_UpdateBoard(t *testing.T) {
 	repo := new(MockBoardRepository)
 	service := NewBoardService(repo)
 	id := uint(10)
-	old := &models.Board{ID: id, Name: "OldName"}
+	old := &models.Board{ID: id, Name: "OldName", UserID: "u1"}
 	repo.On("GetBoardByID", id).Return(old, nil)
 	repo.On("UpdateBoard", old).Return(nil)
 
-	err := service.UpdateBoard(id, "NewName")
+	err := service.UpdateBoard("u1", id, "NewName", "", nil)
 
 	repo.AssertExpectations(t)
 	assert.NoError(t, err)
 	assert.Equal(t, "NewName", old.Name)
+
+	// 其他使用者不能修改看板
+	err = service.UpdateBoard("u2", id, "Other", "", nil)
+	assert.ErrorIs(t, err, ErrForbidden)
+	assert.Equal(t, "NewName", old.Name)
+	repo.AssertNumberOfCalls(t, "UpdateBoard", 1)
+}
+
+func TestBoardService_UpdateBoard_KeepsNameAndAppliesSettings(t *testing.T) {
+	repo := new(MockBoardRepository)
+	service := NewBoardService(repo)
+	board := &models.Board{ID: 10, Name: "OldName", UserID: "u1", Settings: models.BoardSettings{BackgroundImageURL: "https://example.com/a.jpg"}}
+	repo.On("GetBoardByID", uint(10)).Return(board, nil)
+	repo.On("UpdateBoard", board).Return(nil)
+	color := "#00AAFF"
+
+	err := service.UpdateBoard("u1", 10, "", "", &BoardSettingsPatch{BackgroundColor: &color})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "OldName", board.Name)
+	assert.Equal(t, "#00aaff", board.Settings.BackgroundColor)
+	assert.Empty(t, board.Settings.BackgroundImageURL)
+}
+
+func TestBoardService_UpdateBoardSettings(t *testing.T) {
+	repo := new(MockBoardRepository)
+	service := NewBoardService(repo)
+	board := &models.Board{ID: 1, UserID: "u1", Settings: models.BoardSettings{Visibility: models.BoardPrivate}}
+	repo.On("GetBoardByID", uint(1)).Return(board, nil)
+	repo.On("UpdateBoard", board).Return(nil)
+	description := "  產品待辦  "
+	visibility := models.BoardPublic
+	voting := false
+
+	result, err := service.UpdateBoardSettings("u1", 1, BoardSettingsPatch{
+		Description:   &description,
+		Visibility:    &visibility,
+		VotingEnabled: &voting,
+	})
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, "產品待辦", result.Settings.Description)
+	assert.Equal(t, models.BoardPublic, result.Settings.Visibility)
+	assert.True(t, result.Settings.VotingDisabled)
+	assert.False(t, result.Settings.CommentsDisabled)
+
+	_, err = service.UpdateBoardSettings("u2", 1, BoardSettingsPatch{})
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestBoardService_UpdateBoardSettings_Invalid(t *testing.T) {
+	repo := new(MockBoardRepository)
+	service := NewBoardService(repo)
+	repo.On("GetBoardByID", uint(1)).Return(&models.Board{ID: 1, UserID: "u1"}, nil)
+	color, image, badImage := "#112233", "https://example.com/bg.png", "javascript:alert(1)"
+	visibility := models.BoardVisibility("team")
+
+	for _, patch := range []BoardSettingsPatch{
+		{BackgroundColor: &color, BackgroundImageURL: &image},
+		{BackgroundImageURL: &badImage},
+	} {
+		_, err := service.UpdateBoardSettings("u1", 1, patch)
+		assert.ErrorIs(t, err, ErrInvalidBoardBackground)
+	}
+	_, err := service.UpdateBoardSettings("u1", 1, BoardSettingsPatch{Visibility: &visibility})
+	assert.ErrorIs(t, err, ErrInvalidBoardVisibility)
+	repo.AssertNotCalled(t, "UpdateBoard", mock.Anything)
 }
 
 func TestBoardService_DeleteBoard(t *testing.T) {
 	repo := new(MockBoardRepository)
 	service := NewBoardService(repo)
 	id := uint(20)
+	repo.On("GetBoardByID", id).Return(&models.Board{ID: id, UserID: "u1"}, nil)
 	repo.On("DeleteBoard", id).Return(nil)
 
-	err := service.DeleteBoard(id)
+	// 其他使用者不能把看板移至垃圾桶
+	err := service.DeleteBoard("u2", id)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "DeleteBoard", id)
+
+	err = service.DeleteBoard("u1", id)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+}
+
+func TestBoardService_ArchiveBoard(t *testing.T) {
+	repo := new(MockBoardRepository)
+	service := NewBoardService(repo)
+	board := &models.Board{ID: 1, UserID: "u1", Position: 0}
+	repo.On("GetBoardByID", uint(1)).Return(board, nil)
+	repo.On("FindBoardsByUserID", "u1", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]models.Board) = []models.Board{
+			{ID: 1, UserID: "u1", Position: 0},
+			{ID: 2, UserID: "u1", Position: 1},
+		}
+	}).Return(nil)
+	repo.On("UpdateBoard", mock.MatchedBy(func(b *models.Board) bool {
+		return b.ID == 2 && b.Position == 0
+	})).Return(nil).Once()
+	repo.On("UpdateBoard", board).Return(nil).Once()
+
+	result, err := service.ArchiveBoard("u1", 1)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.NotNil(t, result.ArchivedAt)
+
+	_, err = service.UnarchiveBoard("u2", 1)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestBoardService_SetTemplate(t *testing.T) {
+	repo := new(MockBoardRepository)
+	service := NewBoardService(repo)
+	board := &models.Board{ID: 1, UserID: "u1"}
+	repo.On("GetBoardByID", uint(1)).Return(board, nil)
+	repo.On("UpdateBoard", board).Return(nil)
+
+	result, err := service.SetTemplate("u1", 1, true)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.True(t, result.IsTemplate)
+
+	_, err = service.SetTemplate("u2", 1, false)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestBoardService_CloseBoard(t *testing.T) {
+	repo := new(MockBoardRepository)
+	service := NewBoardService(repo)
+	board := &models.Board{ID: 1, UserID: "u1", Position: 0}
+	repo.On("GetBoardByID", uint(1)).Return(board, nil)
+	repo.On("FindBoardsByUserID", "u1", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]models.Board) = []models.Board{
+			{ID: 1, UserID: "u1", Position: 0},
+			{ID: 2, UserID: "u1", Position: 1},
+		}
+	}).Return(nil)
+	repo.On("UpdateBoard", mock.MatchedBy(func(b *models.Board) bool {
+		return b.ID == 2 && b.Position == 0
+	})).Return(nil).Once()
+	repo.On("UpdateBoard", board).Return(nil).Once()
+
+	result, err := service.CloseBoard("u1", 1)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.NotNil(t, result.ClosedAt)
+
+	_, err = service.CloseBoard("u2", 1)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestBoardService_ReopenBoard(t *testing.T) {
+	repo := new(MockBoardRepository)
+	service := NewBoardService(repo)
+	closedAt := time.Now()
+	board := &models.Board{ID: 1, UserID: "u1", Position: 0, ClosedAt: &closedAt}
+	repo.On("GetBoardByID", uint(1)).Return(board, nil)
+	repo.On("FindBoardsByUserID", "u1", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]models.Board) = []models.Board{{ID: 2, UserID: "u1", Position: 0}}
+	}).Return(nil)
+	repo.On("UpdateBoard", mock.MatchedBy(func(b *models.Board) bool {
+		return b.ID == 2 && b.Position == 1
+	})).Return(nil).Once()
+	repo.On("UpdateBoard", board).Return(nil).Once()
+
+	result, err := service.ReopenBoard("u1", 1)
 
 	repo.AssertExpectations(t)
 	assert.NoError(t, err)
+	assert.Nil(t, result.ClosedAt)
+	assert.Equal(t, 0, result.Position)
 }
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"errors"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+)
+
+var (
+	ErrInvalidCardRelation = errors.New("無效的卡片關係")
+	ErrRelationCycle       = errors.New("新增此阻擋關係會形成循環")
+)
+
+// CardRelations 卡片與其他卡片的關係摘要
+type CardRelations struct {
+	Blocks       []models.Card // 被此卡片阻擋的卡片
+	BlockedBy    []models.Card // 阻擋此卡片的卡片
+	Related      []models.Card
+	OpenBlockers int  // 尚未解除的阻擋卡片數量
+	InDoneList   bool // 卡片位於完成清單中
+}
+
+// BlockedWarning 卡片已移入完成清單，但仍有未解除的阻擋卡片
+func (r CardRelations) BlockedWarning() bool {
+	return r.InDoneList && r.OpenBlockers > 0
+}
+
+type CardRelationService interface {
+	AddRelation(userID string, fromCardID, toCardID uint, relationType models.CardRelationType) error
+	RemoveRelation(userID string, fromCardID, toCardID uint, relationType models.CardRelationType) error
+	GetRelationsByCardIDs(cardIDs []uint) (map[uint]CardRelations, error)
+}
+
+type cardRelationService struct {
+	relationRepo repositories.CardRelationRepository
+	cardRepo     repositories.CardRepository
+	boardRepo    repositories.BoardRepository
+}
+
+func NewCardRelationService(
+	relationRepo repositories.CardRelationRepository,
+	cardRepo repositories.CardRepository,
+	boardRepo repositories.BoardRepository,
+) CardRelationService {
+	return &cardRelationService{relationRepo: relationRepo, cardRepo: cardRepo, boardRepo: boardRepo}
+}
+
+// AddRelation 新增卡片關係，兩張卡片所在的看板都必須可被使用者存取。
+// 阻擋關係不可形成循環；關聯關係為雙向，重複新增不會報錯
+func (s *cardRelationService) AddRelation(userID string, fromCardID, toCardID uint, relationType models.CardRelationType) error {
+	if err := s.checkAccess(userID, fromCardID, toCardID, relationType); err != nil {
+		return err
+	}
+	if relationType == models.CardRelationBlocks {
+		cycle, err := s.reachable(toCardID, fromCardID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrRelationCycle
+		}
+	}
+	fromCardID, toCardID = normalizeRelation(fromCardID, toCardID, relationType)
+	existing, err := s.relationRepo.GetRelationsByCardIDs([]uint{fromCardID})
+	if err != nil {
+		return err
+	}
+	for _, r := range existing {
+		if r.FromCardID == fromCardID && r.ToCardID == toCardID && r.Type == relationType {
+			return nil
+		}
+	}
+	return s.relationRepo.CreateRelation(&models.CardRelation{
+		FromCardID: fromCardID,
+		ToCardID:   toCardID,
+		Type:       relationType,
+	})
+}
+
+func (s *cardRelationService) RemoveRelation(userID string, fromCardID, toCardID uint, relationType models.CardRelationType) error {
+	if err := s.checkAccess(userID, fromCardID, toCardID, relationType); err != nil {
+		return err
+	}
+	fromCardID, toCardID = normalizeRelation(fromCardID, toCardID, relationType)
+	return s.relationRepo.DeleteRelation(fromCardID, toCardID, relationType)
+}
+
+func (s *cardRelationService) checkAccess(userID string, fromCardID, toCardID uint, relationType models.CardRelationType) error {
+	if relationType != models.CardRelationBlocks && relationType != models.CardRelationRelates {
+		return ErrInvalidCardRelation
+	}
+	if fromCardID == toCardID {
+		return ErrInvalidCardRelation
+	}
+	for _, id := range []uint{fromCardID, toCardID} {
+		card, err := s.cardRepo.GetCardByID(id)
+		if err != nil {
+			return err
+		}
+		if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reachable 以 BFS 沿阻擋關係檢查 from 是否能到達 target
+func (s *cardRelationService) reachable(from, target uint) (bool, error) {
+	visited := map[uint]bool{from: true}
+	frontier := []uint{from}
+	for len(frontier) > 0 {
+		next, err := s.relationRepo.GetBlockedCardIDs(frontier)
+		if err != nil {
+			return false, err
+		}
+		frontier = frontier[:0]
+		for _, id := range next {
+			if id == target {
+				return true, nil
+			}
+			if !visited[id] {
+				visited[id] = true
+				frontier = append(frontier, id)
+			}
+		}
+	}
+	return false, nil
+}
+
+// normalizeRelation 雙向的關聯關係一律以較小的 ID 作為 from
+func normalizeRelation(fromCardID, toCardID uint, relationType models.CardRelationType) (uint, uint) {
+	if relationType == models.CardRelationRelates && fromCardID > toCardID {
+		return toCardID, fromCardID
+	}
+	return fromCardID, toCardID
+}
+
+// GetRelationsByCardIDs 批次取得多張卡片的關係摘要
+func (s *cardRelationService) GetRelationsByCardIDs(cardIDs []uint) (map[uint]CardRelations, error) {
+	relations, err := s.relationRepo.GetRelationsByCardIDs(cardIDs)
+	if err != nil {
+		return nil, err
+	}
+	var otherIDs []uint
+	for _, r := range relations {
+		otherIDs = append(otherIDs, r.FromCardID, r.ToCardID)
+	}
+	// 已刪除的卡片不會出現在查詢結果中，關係也一併略過
+	cards, err := s.cardRepo.GetCardsByIDs(otherIDs)
+	if err != nil {
+		return nil, err
+	}
+	cardsByID := make(map[uint]models.Card, len(cards))
+	for _, c := range cards {
+		cardsByID[c.ID] = c
+	}
+	openBlockers, err := s.relationRepo.CountOpenBlockers(cardIDs)
+	if err != nil {
+		return nil, err
+	}
+	inDone, err := s.relationRepo.FindCardIDsInDoneLists(cardIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]CardRelations, len(cardIDs))
+	for _, id := range cardIDs {
+		result[id] = CardRelations{OpenBlockers: openBlockers[id]}
+	}
+	for _, id := range inDone {
+		r := result[id]
+		r.InDoneList = true
+		result[id] = r
+	}
+	for _, rel := range relations {
+		from, okFrom := cardsByID[rel.FromCardID]
+		to, okTo := cardsByID[rel.ToCardID]
+		if !okFrom || !okTo {
+			continue
+		}
+		if r, ok := result[rel.FromCardID]; ok {
+			if rel.Type == models.CardRelationBlocks {
+				r.Blocks = append(r.Blocks, to)
+			} else {
+				r.Related = append(r.Related, to)
+			}
+			result[rel.FromCardID] = r
+		}
+		if r, ok := result[rel.ToCardID]; ok {
+			if rel.Type == models.CardRelationBlocks {
+				r.BlockedBy = append(r.BlockedBy, from)
+			} else {
+				r.Related = append(r.Related, from)
+			}
+			result[rel.ToCardID] = r
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"testing"
+	"trello-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCardRelationRepository struct {
+	mock.Mock
+}
+
+func (m *MockCardRelationRepository) CreateRelation(relation *models.CardRelation) error {
+	args := m.Called(relation)
+	return args.Error(0)
+}
+func (m *MockCardRelationRepository) DeleteRelation(fromCardID, toCardID uint, relationType models.CardRelationType) error {
+	args := m.Called(fromCardID, toCardID, relationType)
+	return args.Error(0)
+}
+func (m *MockCardRelationRepository) GetRelationsByCardIDs(cardIDs []uint) ([]models.CardRelation, error) {
+	args := m.Called(cardIDs)
+	return args.Get(0).([]models.CardRelation), args.Error(1)
+}
+func (m *MockCardRelationRepository) GetBlockedCardIDs(blockerIDs []uint) ([]uint, error) {
+	args := m.Called(blockerIDs)
+	return args.Get(0).([]uint), args.Error(1)
+}
+func (m *MockCardRelationRepository) CountOpenBlockers(cardIDs []uint) (map[uint]int, error) {
+	args := m.Called(cardIDs)
+	return args.Get(0).(map[uint]int), args.Error(1)
+}
+func (m *MockCardRelationRepository) FindCardIDsInDoneLists(cardIDs []uint) ([]uint, error) {
+	args := m.Called(cardIDs)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+// newTestCardRelationService 建立兩個看板（皆屬於 user-1）上的卡片 1~4
+func newTestCardRelationService() (CardRelationService, *MockCardRelationRepository, *MockCardRepository) {
+	relationRepo := new(MockCardRelationRepository)
+	cardRepo := new(MockCardRepository)
+	boardRepo := new(MockBoardRepository)
+	for id := uint(1); id <= 4; id++ {
+		cardRepo.On("GetCardByID", id).Return(&models.Card{ID: id, BoardID: 10 + id%2}, nil)
+	}
+	boardRepo.On("GetBoardByID", uint(10)).Return(&models.Board{ID: 10, UserID: "user-1"}, nil)
+	boardRepo.On("GetBoardByID", uint(11)).Return(&models.Board{ID: 11, UserID: "user-1"}, nil)
+	return NewCardRelationService(relationRepo, cardRepo, boardRepo), relationRepo, cardRepo
+}
+
+func TestCardRelationService_AddRelation_Blocks(t *testing.T) {
+	service, relationRepo, _ := newTestCardRelationService()
+	// 既有 2 -> 3，新增 1 -> 2 不會形成循環
+	relationRepo.On("GetBlockedCardIDs", []uint{2}).Return([]uint{3}, nil)
+	relationRepo.On("GetBlockedCardIDs", []uint{3}).Return([]uint{}, nil)
+	relationRepo.On("GetRelationsByCardIDs", []uint{1}).Return([]models.CardRelation{}, nil)
+	relationRepo.On("CreateRelation", &models.CardRelation{FromCardID: 1, ToCardID: 2, Type: models.CardRelationBlocks}).Return(nil)
+
+	err := service.AddRelation("user-1", 1, 2, models.CardRelationBlocks)
+
+	relationRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+}
+
+func TestCardRelationService_AddRelation_Cycle(t *testing.T) {
+	service, relationRepo, _ := newTestCardRelationService()
+	// 既有 1 -> 2 -> 3，新增 3 -> 1 會形成循環
+	relationRepo.On("GetBlockedCardIDs", []uint{1}).Return([]uint{2}, nil)
+	relationRepo.On("GetBlockedCardIDs", []uint{2}).Return([]uint{3}, nil)
+
+	err := service.AddRelation("user-1", 3, 1, models.CardRelationBlocks)
+
+	assert.ErrorIs(t, err, ErrRelationCycle)
+	relationRepo.AssertNotCalled(t, "CreateRelation", mock.Anything)
+}
+
+func TestCardRelationService_AddRelation_Self(t *testing.T) {
+	service, relationRepo, _ := newTestCardRelationService()
+
+	err := service.AddRelation("user-1", 2, 2, models.CardRelationRelates)
+
+	assert.ErrorIs(t, err, ErrInvalidCardRelation)
+	relationRepo.AssertNotCalled(t, "CreateRelation", mock.Anything)
+}
+
+func TestCardRelationService_AddRelation_Forbidden(t *testing.T) {
+	relationRepo := new(MockCardRelationRepository)
+	cardRepo := new(MockCardRepository)
+	boardRepo := new(MockBoardRepository)
+	cardRepo.On("GetCardByID", uint(1)).Return(&models.Card{ID: 1, BoardID: 10}, nil)
+	cardRepo.On("GetCardByID", uint(2)).Return(&models.Card{ID: 2, BoardID: 20}, nil)
+	boardRepo.On("GetBoardByID", uint(10)).Return(&models.Board{ID: 10, UserID: "user-1"}, nil)
+	boardRepo.On("GetBoardByID", uint(20)).Return(&models.Board{ID: 20, UserID: "user-2"}, nil)
+	service := NewCardRelationService(relationRepo, cardRepo, boardRepo)
+
+	err := service.AddRelation("user-1", 1, 2, models.CardRelationRelates)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestCardRelationService_RemoveRelation_Relates(t *testing.T) {
+	service, relationRepo, _ := newTestCardRelationService()
+	// 關聯關係以較小的 ID 為 from
+	relationRepo.On("DeleteRelation", uint(2), uint(4), models.CardRelationRelates).Return(nil)
+
+	err := service.RemoveRelation("user-1", 4, 2, models.CardRelationRelates)
+
+	relationRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+}
+
+func TestCardRelationService_GetRelationsByCardIDs(t *testing.T) {
+	service, relationRepo, cardRepo := newTestCardRelationService()
+	relationRepo.On("GetRelationsByCardIDs", []uint{2}).Return([]models.CardRelation{
+		{FromCardID: 1, ToCardID: 2, Type: models.CardRelationBlocks},
+		{FromCardID: 2, ToCardID: 3, Type: models.CardRelationBlocks},
+		{FromCardID: 2, ToCardID: 4, Type: models.CardRelationRelates},
+	}, nil)
+	cardRepo.On("GetCardsByIDs", mock.Anything).Return([]models.Card{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}, nil)
+	relationRepo.On("CountOpenBlockers", []uint{2}).Return(map[uint]int{2: 1}, nil)
+	relationRepo.On("FindCardIDsInDoneLists", []uint{2}).Return([]uint{2}, nil)
+
+	result, err := service.GetRelationsByCardIDs([]uint{2})
+
+	assert.NoError(t, err)
+	r := result[2]
+	assert.Len(t, r.BlockedBy, 1)
+	assert.Equal(t, uint(1), r.BlockedBy[0].ID)
+	assert.Len(t, r.Blocks, 1)
+	assert.Equal(t, uint(3), r.Blocks[0].ID)
+	assert.Len(t, r.Related, 1)
+	assert.Equal(t, uint(4), r.Related[0].ID)
+	assert.True(t, r.BlockedWarning())
+}
@@ -1,44 +1,144 @@
 package services
 
 import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
 	"trello-backend/internal/models"
 	"trello-backend/internal/repositories"
+	"trello-backend/pkg/markdown"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gorm.io/gorm"
+)
+
+// CardDetails 建立/更新卡片時可設定的欄位。更新時為 nil 的日期、提醒、優先順序與估計工作量維持原值，
+// 要清除時將欄位列在 Clear 中；清除優先順序即設為 none
+type CardDetails struct {
+	Title           string
+	Content         string
+	StartAt         *time.Time
+	DueAt           *time.Time
+	CompletedAt     *time.Time
+	ReminderMinutes *int
+	Priority        *models.CardPriority
+	Estimate        *float64
+	Clear           CardField
+}
+
+// CardField 可在更新時清除的卡片欄位，可用 | 組合
+type CardField uint8
+
+const (
+	CardStartAt CardField = 1 << iota
+	CardDueAt
+	CardCompletedAt
+	CardReminder
+	CardPriority
+	CardEstimate
 )
 
 type CardService interface {
-	CreateCard(listID uint, boardID uint, title, content string) (*models.Card, error)
+	CreateCard(userID string, listID uint, details CardDetails) (*models.Card, error)
 	GetCards(listID uint) ([]models.Card, error)
 	GetCardByID(id uint) (*models.Card, error)
-	UpdateCard(id uint, title, content string) error
-	DeleteCard(id uint) error
-	MoveCard(id, targetListID uint, newPosition int) error
+	GetReadableCard(userID string, id uint) (*models.Card, error)
+	GetCardByReference(userID, ref string) (*models.Card, error)
+	UpdateCard(userID string, id uint, details CardDetails) error
+	DeleteCard(userID string, id uint) error
+	MoveCard(userID string, id, targetListID uint, newPosition int) error
 	GetCardsByBoardID(boardID uint) ([]models.Card, error) // 新增
 	GetCardsByListIDs(listIDs []uint) (map[uint][]models.Card, error)
+	GetEstimateTotals(listIDs []uint) (map[uint]float64, error)
+	GetBoardEstimateTotals(boardIDs []uint) (map[uint]float64, error)
+	GetOverdueCards(userID string, boardID uint) ([]models.Card, error)
+	GetDueSoonCards(userID string, boardID uint, within time.Duration) ([]models.Card, error)
+	ArchiveCard(userID string, id uint) (*models.Card, error)
+	UnarchiveCard(userID string, id uint) (*models.Card, error)
+	GetArchivedCards(userID string, boardID uint) ([]models.Card, error)
+	RenderContent(content string) (string, error)
+	SetTemplate(userID string, id uint, isTemplate bool) (*models.Card, error)
+	GetTemplates(userID string, boardID uint) ([]models.Card, error)
+	CreateCardFromTemplate(userID string, templateID, listID uint) (*models.Card, error)
+	GetRevisionsByCardIDs(cardIDs []uint) (map[uint][]models.CardRevision, error)
+	RestoreRevision(userID string, revisionID uint) (*models.Card, error)
+	MoveCards(userID string, cardIDs []uint, targetListID uint) ([]BulkCardResult, error)
+	ArchiveListCards(userID string, listID uint) ([]BulkCardResult, error)
+	SortListCards(userID string, listID uint, by ListSortField, descending bool) ([]models.Card, error)
 }
 
+// ListSortField 重新排列清單卡片時依據的欄位
+type ListSortField string
+
+const (
+	SortByDueDate   ListSortField = "due_date"
+	SortByCreatedAt ListSortField = "created_at"
+	SortByTitle     ListSortField = "title"
+	SortByPriority  ListSortField = "priority"
+)
+
+// BulkCardResult 批次操作中單張卡片的結果，Err 為 nil 表示成功
+type BulkCardResult struct {
+	CardID     uint
+	Card       *models.Card // 成功時為操作後的卡片
+	FromListID uint         // 操作前所在的清單
+	Err        error
+}
+
+// WipLimitError 清單已達在製品上限且設定為阻擋時，建立或移入卡片會回傳此錯誤；
+// 上限在 repository 的交易中檢查
+type WipLimitError = repositories.WipLimitError
+
+var (
+	// ErrNotTemplate 指定的卡片不是範本
+	ErrNotTemplate = errors.New("卡片不是範本")
+	// ErrTemplateOtherBoard 範本只能用在所屬的看板
+	ErrTemplateOtherBoard = errors.New("範本不屬於目標清單所在的看板")
+	ErrInvalidPriority    = errors.New("無效的優先順序")
+	ErrCardArchived       = errors.New("卡片已封存")
+	ErrListArchived       = errors.New("清單已封存")
+	ErrInvalidSortBy      = errors.New("無效的排序欄位")
+)
+
 type cardService struct {
-	cardRepo repositories.CardRepository
+	cardRepo  repositories.CardRepository
+	listRepo  repositories.ListRepository
+	boardRepo repositories.BoardRepository
+	markdown  *markdown.Renderer
 }
 
-func NewCardService(repo repositories.CardRepository) CardService {
-	return &cardService{cardRepo: repo}
+func NewCardService(repo repositories.CardRepository, listRepo repositories.ListRepository, boardRepo repositories.BoardRepository) CardService {
+	return &cardService{cardRepo: repo, listRepo: listRepo, boardRepo: boardRepo, markdown: markdown.NewRenderer()}
 }
 
-func (s *cardService) CreateCard(listID uint, boardID uint, title, content string) (*models.Card, error) {
-	// 取得該 list 目前所有卡片
-	cards, err := s.cardRepo.GetCardsByListID(listID)
+// GetCardByReference 以 WEB-142 格式的參照在使用者的看板中尋找卡片
+func (s *cardService) GetCardByReference(userID, ref string) (*models.Card, error) {
+	key, number, err := parseCardReference(ref)
 	if err != nil {
 		return nil, err
 	}
-	// 所有卡片 position +1
-	for i := range cards {
-		cards[i].Position++
-		if err := s.cardRepo.UpdateCard(&cards[i]); err != nil {
-			return nil, err
-		}
+	return s.cardRepo.GetCardByReference(userID, key, number)
+}
+
+// CreateCard 在清單最上方建立卡片，所屬看板由清單決定
+func (s *cardService) CreateCard(userID string, listID uint, details CardDetails) (*models.Card, error) {
+	if err := validateCardDetails(details); err != nil {
+		return nil, err
+	}
+	list, err := getTargetList(s.listRepo, listID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	card := &models.Card{ListID: listID, BoardID: list.BoardID}
+	applyCardDetails(card, details)
+	if err := validateCardDates(card); err != nil {
+		return nil, err
 	}
-	// 新卡片 position = 0
-	card := &models.Card{ListID: listID, BoardID: boardID, Title: title, Content: content, Position: 0}
 	if err := s.cardRepo.CreateCard(card); err != nil {
 		return nil, err
 	}
@@ -53,62 +153,225 @@ func (s *cardService) GetCardByID(id uint) (*models.Card, error) {
 	return s.cardRepo.GetCardByID(id)
 }
 
-func (s *cardService) UpdateCard(id uint, title, content string) error {
+// GetReadableCard 取得卡片，卡片所在的看板必須是使用者能閱讀的看板
+func (s *cardService) GetReadableCard(userID string, id uint) (*models.Card, error) {
 	card, err := s.cardRepo.GetCardByID(id)
 	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardReadable(s.boardRepo, card.BoardID, userID); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// UpdateCard 更新卡片，標題或內容有變更時一併保存修改紀錄
+func (s *cardService) UpdateCard(userID string, id uint, details CardDetails) error {
+	if err := validateCardDetails(details); err != nil {
+		return err
+	}
+	card, err := s.cardRepo.GetCardByID(id)
+	if err != nil {
+		return err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return err
+	}
+	oldTitle, oldContent := card.Title, card.Content
+	applyCardDetails(card, details)
+	// 未指定的日期沿用原值，需與新值一起檢查
+	if err := validateCardDates(card); err != nil {
 		return err
 	}
-	card.Title = title
-	card.Content = content
-	return s.cardRepo.UpdateCard(card)
+	return s.saveWithRevision(userID, card, oldTitle, oldContent)
+}
+
+// saveWithRevision 儲存卡片；標題或內容與 oldTitle、oldContent 不同時記錄修改者與前後的值
+func (s *cardService) saveWithRevision(userID string, card *models.Card, oldTitle, oldContent string) error {
+	if card.Title == oldTitle && card.Content == oldContent {
+		return s.cardRepo.UpdateCard(card)
+	}
+	return s.cardRepo.UpdateCardWithRevision(card, &models.CardRevision{
+		UserID:     userID,
+		OldTitle:   oldTitle,
+		NewTitle:   card.Title,
+		OldContent: oldContent,
+		NewContent: card.Content,
+	})
+}
+
+func (s *cardService) GetRevisionsByCardIDs(cardIDs []uint) (map[uint][]models.CardRevision, error) {
+	return s.cardRepo.GetRevisionsByCardIDs(cardIDs)
+}
+
+// RestoreRevision 將卡片的標題與內容還原為該次修改前的值。
+// 還原本身也會產生一筆修改紀錄，因此可以再被還原
+func (s *cardService) RestoreRevision(userID string, revisionID uint) (*models.Card, error) {
+	revision, err := s.cardRepo.GetRevisionByID(revisionID)
+	if err != nil {
+		return nil, err
+	}
+	card, err := s.cardRepo.GetCardByID(revision.CardID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return nil, err
+	}
+	oldTitle, oldContent := card.Title, card.Content
+	card.Title = revision.OldTitle
+	card.Content = revision.OldContent
+	if err := s.saveWithRevision(userID, card, oldTitle, oldContent); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// RevisionDiff 產生修改前後內容的 unified diff，內容未變更時回傳空字串
+func RevisionDiff(revision *models.CardRevision) string {
+	if revision.OldContent == revision.NewContent {
+		return ""
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(revision.OldContent),
+		B:        splitLines(revision.NewContent),
+		FromFile: "修改前",
+		ToFile:   "修改後",
+		Context:  3,
+	})
+	return diff
 }
 
-func (s *cardService) DeleteCard(id uint) error {
+// splitLines 將內容切成以換行結尾的行；結尾換行不會多產生一個空行
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+	return lines
+}
+
+// DeleteCard 將卡片移至垃圾桶，只有看板擁有者能刪除，與從垃圾桶還原相同
+func (s *cardService) DeleteCard(userID string, id uint) error {
+	card, err := s.cardRepo.GetCardByID(id)
+	if err != nil {
+		return err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return err
+	}
 	return s.cardRepo.DeleteCard(id)
 }
 
-func (s *cardService) MoveCard(id, targetListID uint, newPosition int) error {
+// MoveCard 將卡片移到目標清單的 newPosition；目標清單在其他看板時，
+// 使用者必須同時擁有兩個看板，卡片會取得目標看板的編號並對應自訂欄位
+func (s *cardService) MoveCard(userID string, id, targetListID uint, newPosition int) error {
 	card, err := s.cardRepo.GetCardByID(id)
 	if err != nil {
 		return err
 	}
-	oldListID := card.ListID
-	oldPos := card.Position
-	// 調整原清單中的卡片位置
-	oldCards, err := s.cardRepo.GetCardsByListID(oldListID)
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return err
+	}
+	// 封存的卡片不在清單的排序中，須先還原才能移動
+	if card.ArchivedAt != nil {
+		return ErrCardArchived
+	}
+	list, err := getTargetList(s.listRepo, targetListID)
 	if err != nil {
 		return err
 	}
-	for _, c := range oldCards {
-		if c.ID == id {
-			continue
+	if list.BoardID != card.BoardID {
+		if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+			return err
 		}
-		if c.Position > oldPos {
-			c.Position--
-			if err := s.cardRepo.UpdateCard(&c); err != nil {
-				return err
-			}
+	}
+	return s.cardRepo.MoveCard(card, list, newPosition)
+}
+
+// ArchiveCard 封存卡片，並將同清單中後面的卡片往前補位
+func (s *cardService) ArchiveCard(userID string, id uint) (*models.Card, error) {
+	card, err := s.cardRepo.GetCardByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return nil, err
+	}
+	if card.ArchivedAt != nil {
+		return card, nil
+	}
+	if err := s.closePositionGap(card.ListID, id, card.Position); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	card.ArchivedAt = &now
+	if err := s.cardRepo.UpdateCard(card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// UnarchiveCard 還原卡片，盡量放回封存前的位置
+func (s *cardService) UnarchiveCard(userID string, id uint) (*models.Card, error) {
+	card, err := s.cardRepo.GetCardByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return nil, err
+	}
+	if card.ArchivedAt == nil {
+		return card, nil
+	}
+	if err := s.cardRepo.UnarchiveCard(card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// GetArchivedCards 取得使用者能閱讀的看板中已封存的卡片
+func (s *cardService) GetArchivedCards(userID string, boardID uint) ([]models.Card, error) {
+	if _, err := ensureBoardReadable(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	return s.cardRepo.GetArchivedCardsByBoardID(boardID)
+}
+
+// countWipCards 計算計入在製品上限的卡片數量，排除範本卡片
+func countWipCards(cards []models.Card) int {
+	count := 0
+	for _, c := range cards {
+		if !c.IsTemplate {
+			count++
 		}
 	}
-	// 插入到新清單
-	card.ListID = targetListID
-	newCards, err := s.cardRepo.GetCardsByListID(targetListID)
+	return count
+}
+
+// closePositionGap 卡片離開清單後，將位置在其後的卡片往前移一格
+func (s *cardService) closePositionGap(listID, cardID uint, position int) error {
+	cards, err := s.cardRepo.GetCardsByListID(listID)
 	if err != nil {
 		return err
 	}
-	if newPosition > len(newCards) {
-		newPosition = len(newCards)
-	}
-	for _, c := range newCards {
-		if c.Position >= newPosition {
-			c.Position++
+	for _, c := range cards {
+		if c.ID == cardID {
+			continue
+		}
+		if c.Position > position {
+			c.Position--
 			if err := s.cardRepo.UpdateCard(&c); err != nil {
 				return err
 			}
 		}
 	}
-	card.Position = newPosition
-	return s.cardRepo.UpdateCard(card)
+	return nil
 }
 
 func (s *cardService) GetCardsByBoardID(boardID uint) ([]models.Card, error) {
@@ -118,3 +381,339 @@ func (s *cardService) GetCardsByBoardID(boardID uint) ([]models.Card, error) {
 func (s *cardService) GetCardsByListIDs(listIDs []uint) (map[uint][]models.Card, error) {
 	return s.cardRepo.GetCardsByListIDs(listIDs)
 }
+
+// GetOverdueCards 取得使用者能閱讀的看板中已過期且未完成的卡片
+func (s *cardService) GetOverdueCards(userID string, boardID uint) ([]models.Card, error) {
+	if _, err := ensureBoardReadable(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	return s.cardRepo.GetOverdueCards(boardID, time.Now())
+}
+
+// GetDueSoonCards 取得使用者能閱讀的看板中 within 內到期的卡片
+func (s *cardService) GetDueSoonCards(userID string, boardID uint, within time.Duration) ([]models.Card, error) {
+	if _, err := ensureBoardReadable(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return s.cardRepo.GetCardsDueBetween(boardID, now, now.Add(within))
+}
+
+// applyCardDetails 套用欄位；為 nil 且未列在 Clear 中的欄位維持原值，新卡片的優先順序預設為 none
+func applyCardDetails(card *models.Card, details CardDetails) {
+	card.Title = details.Title
+	card.Content = details.Content
+	setOptional(&card.StartAt, details.StartAt, details.Clear&CardStartAt != 0)
+	setOptional(&card.DueAt, details.DueAt, details.Clear&CardDueAt != 0)
+	setOptional(&card.CompletedAt, details.CompletedAt, details.Clear&CardCompletedAt != 0)
+	setOptional(&card.ReminderMinutes, details.ReminderMinutes, details.Clear&CardReminder != 0)
+	if details.Priority != nil {
+		card.Priority = *details.Priority
+	} else if details.Clear&CardPriority != 0 {
+		card.Priority = models.PriorityNone
+	}
+	if card.Priority == "" {
+		card.Priority = models.PriorityNone
+	}
+	setOptional(&card.Estimate, details.Estimate, details.Clear&CardEstimate != 0)
+}
+
+func validateCardDetails(details CardDetails) error {
+	if details.Priority != nil {
+		if _, ok := priorityRank[*details.Priority]; !ok {
+			return ErrInvalidPriority
+		}
+	}
+	if details.Estimate != nil && *details.Estimate < 0 {
+		return errors.New("估計工作量不可為負數")
+	}
+	if details.ReminderMinutes != nil && *details.ReminderMinutes < 0 {
+		return errors.New("提醒時間不可為負數")
+	}
+	return nil
+}
+
+// validateCardDates 檢查套用變更後卡片日期與提醒的組合
+func validateCardDates(card *models.Card) error {
+	if card.StartAt != nil && card.DueAt != nil && card.DueAt.Before(*card.StartAt) {
+		return errors.New("到期時間不可早於開始時間")
+	}
+	if card.ReminderMinutes != nil && card.DueAt == nil {
+		return errors.New("設定提醒前需先設定到期時間")
+	}
+	return nil
+}
+
+// setOptional clear 為 true 時清除欄位，否則 value 不為 nil 時才覆寫
+func setOptional[T any](field **T, value *T, clear bool) {
+	if clear {
+		*field = nil
+	} else if value != nil {
+		*field = value
+	}
+}
+
+// GetEstimateTotals 計算各清單中卡片估計工作量的總和，不含封存與範本卡片
+func (s *cardService) GetEstimateTotals(listIDs []uint) (map[uint]float64, error) {
+	return s.cardRepo.SumEstimatesByListIDs(listIDs)
+}
+
+// GetBoardEstimateTotals 計算各看板中未封存清單的卡片估計工作量總和，不含封存與範本卡片
+func (s *cardService) GetBoardEstimateTotals(boardIDs []uint) (map[uint]float64, error) {
+	return s.cardRepo.SumEstimatesByBoardIDs(boardIDs)
+}
+
+// priorityRank 優先順序由低到高的排序值
+var priorityRank = map[models.CardPriority]int{
+	models.PriorityNone:   0,
+	models.PriorityLow:    1,
+	models.PriorityMedium: 2,
+	models.PriorityHigh:   3,
+	models.PriorityUrgent: 4,
+}
+
+// SortCardsByPriority 依優先順序由高到低排序，相同優先順序維持原本的位置順序
+func SortCardsByPriority(cards []models.Card) {
+	sort.SliceStable(cards, func(i, j int) bool {
+		return priorityRank[cards[i].Priority] > priorityRank[cards[j].Priority]
+	})
+}
+
+// SortListCards 依欄位重新排列清單中未封存卡片的位置，並回傳新的順序。
+// 沒有到期時間的卡片不論方向都排在最後；標題不分大小寫；優先順序遞增為由低到高；值相同時維持原本的順序
+func (s *cardService) SortListCards(userID string, listID uint, by ListSortField, descending bool) ([]models.Card, error) {
+	compare, ok := cardComparators[by]
+	if !ok {
+		return nil, ErrInvalidSortBy
+	}
+	list, err := s.listRepo.GetListByID(listID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	cards, err := s.cardRepo.GetCardsByListID(listID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(cards, func(i, j int) bool {
+		a, b := &cards[i], &cards[j]
+		if by == SortByDueDate && (a.DueAt == nil) != (b.DueAt == nil) {
+			return b.DueAt == nil
+		}
+		c := compare(a, b)
+		if descending {
+			c = -c
+		}
+		return c < 0
+	})
+	ids := make([]uint, len(cards))
+	for i := range cards {
+		ids[i] = cards[i].ID
+		cards[i].Position = i
+	}
+	if err := s.cardRepo.UpdateCardPositions(ids); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// cardComparators 各排序欄位的比較函式，回傳負數表示 a 排在 b 之前
+var cardComparators = map[ListSortField]func(a, b *models.Card) int{
+	SortByDueDate: func(a, b *models.Card) int {
+		if a.DueAt == nil || b.DueAt == nil {
+			return 0
+		}
+		return a.DueAt.Compare(*b.DueAt)
+	},
+	SortByCreatedAt: func(a, b *models.Card) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	},
+	SortByTitle: func(a, b *models.Card) int {
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	},
+	SortByPriority: func(a, b *models.Card) int {
+		return priorityRank[a.Priority] - priorityRank[b.Priority]
+	},
+}
+
+// RenderContent 將卡片內容的 Markdown 轉為安全的 HTML，相同內容會使用快取的結果
+func (s *cardService) RenderContent(content string) (string, error) {
+	return s.markdown.Render(content)
+}
+
+// SetTemplate 將卡片標記為範本或取消標記
+func (s *cardService) SetTemplate(userID string, id uint, isTemplate bool) (*models.Card, error) {
+	card, err := s.cardRepo.GetCardByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return nil, err
+	}
+	card.IsTemplate = isTemplate
+	if err := s.cardRepo.UpdateCard(card); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// GetTemplates 取得使用者能閱讀的看板中的範本卡片
+func (s *cardService) GetTemplates(userID string, boardID uint) ([]models.Card, error) {
+	if _, err := ensureBoardReadable(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	return s.cardRepo.GetTemplatesByBoardID(boardID)
+}
+
+// CreateCardFromTemplate 以範本的標題、內容、提醒、封面顏色與自訂欄位值建立新卡片，放在目標清單最上方。
+// 目標清單必須與範本在同一個看板；範本上的日期為特定時間點，不複製到新卡片
+func (s *cardService) CreateCardFromTemplate(userID string, templateID, listID uint) (*models.Card, error) {
+	template, err := s.cardRepo.GetCardByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+	if !template.IsTemplate {
+		return nil, ErrNotTemplate
+	}
+	list, err := getTargetList(s.listRepo, listID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	if template.BoardID != list.BoardID {
+		return nil, ErrTemplateOtherBoard
+	}
+	card := &models.Card{
+		Title:           template.Title,
+		Content:         template.Content,
+		ListID:          listID,
+		BoardID:         list.BoardID,
+		ReminderMinutes: template.ReminderMinutes,
+		CoverColor:      template.CoverColor,
+		Priority:        template.Priority,
+		Estimate:        template.Estimate,
+	}
+	if err := s.cardRepo.CreateCardFromTemplate(card, template.ID); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// MoveCards 將多張卡片依指定順序移到目標清單最後。不存在、無權限、已封存或會超過在製品上限的卡片
+// 個別回報失敗，其餘卡片在單一交易中一起移動；重複的 ID 只處理一次
+func (s *cardService) MoveCards(userID string, cardIDs []uint, targetListID uint) ([]BulkCardResult, error) {
+	list, err := getTargetList(s.listRepo, targetListID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	ids := uniqueIDs(cardIDs)
+	cards, err := s.cardRepo.GetCardsByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Card, len(cards))
+	for _, c := range cards {
+		byID[c.ID] = c
+	}
+	// 阻擋模式下，依序移入直到清單達到上限
+	count := -1
+	if list.WipLimit != nil && list.WipMode == models.WipLimitBlock {
+		current, err := s.cardRepo.GetCardsByListID(list.ID)
+		if err != nil {
+			return nil, err
+		}
+		count = countWipCards(current)
+	}
+	owners := boardOwnerCache{boardRepo: s.boardRepo, userID: userID}
+	results := make([]BulkCardResult, len(ids))
+	var moving []models.Card
+	var movingIdx []int
+	for i, id := range ids {
+		results[i].CardID = id
+		card, ok := byID[id]
+		if !ok {
+			results[i].Err = gorm.ErrRecordNotFound
+			continue
+		}
+		results[i].FromListID = card.ListID
+		if err := owners.check(card.BoardID); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if card.ArchivedAt != nil {
+			results[i].Err = ErrCardArchived
+			continue
+		}
+		if count >= 0 && card.ListID != list.ID && !card.IsTemplate {
+			if count >= *list.WipLimit {
+				results[i].Err = &WipLimitError{ListID: list.ID, Limit: *list.WipLimit, Count: count}
+				continue
+			}
+			count++
+		}
+		moving = append(moving, card)
+		movingIdx = append(movingIdx, i)
+	}
+	if len(moving) > 0 {
+		if err := s.cardRepo.MoveCardsToList(moving, list); err != nil {
+			return nil, err
+		}
+	}
+	for j, i := range movingIdx {
+		results[i].Card = &moving[j]
+	}
+	return results, nil
+}
+
+// ArchiveListCards 在單一交易中封存清單中所有未封存的卡片。權限以清單所在的看板檢查，
+// 不會有個別卡片失敗：交易失敗時回傳錯誤，否則結果只包含被封存的卡片且 Err 皆為 nil
+func (s *cardService) ArchiveListCards(userID string, listID uint) ([]BulkCardResult, error) {
+	list, err := s.listRepo.GetListByID(listID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	cards, err := s.cardRepo.ArchiveCardsInList(listID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	results := make([]BulkCardResult, len(cards))
+	for i := range cards {
+		results[i] = BulkCardResult{CardID: cards[i].ID, Card: &cards[i], FromListID: listID}
+	}
+	return results, nil
+}
+
+// getTargetList 取得要放入卡片的清單；已封存的清單不顯示，不能再放入卡片
+func getTargetList(listRepo repositories.ListRepository, id uint) (*models.List, error) {
+	list, err := listRepo.GetListByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if list.ArchivedAt != nil {
+		return nil, ErrListArchived
+	}
+	return list, nil
+}
+
+// uniqueIDs 去除重複的 ID 並保留第一次出現的順序
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"testing"
+	"time"
 	"trello-backend/internal/models"
 
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,10 @@ func (m *MockCardRepository) CreateCard(card *models.Card) error {
 	args := m.Called(card)
 	return args.Error(0)
 }
+func (m *MockCardRepository) CreateCardFromTemplate(card *models.Card, templateID uint) error {
+	args := m.Called(card, templateID)
+	return args.Error(0)
+}
 func (m *MockCardRepository) GetCardsByListID(listID uint) ([]models.Card, error) {
 	args := m.Called(listID)
 	return args.Get(0).([]models.Card), args.Error(1)
@@ -44,34 +49,134 @@ func (m *MockCardRepository) GetCardsByListIDs(listIDs []uint) (map[uint][]model
 	args := m.Called(listIDs)
 	return args.Get(0).(map[uint][]models.Card), args.Error(1)
 }
+func (m *MockCardRepository) GetOverdueCards(boardID uint, now time.Time) ([]models.Card, error) {
+	args := m.Called(boardID, now)
+	return args.Get(0).([]models.Card), args.Error(1)
+}
+func (m *MockCardRepository) GetCardsDueBetween(boardID uint, from, to time.Time) ([]models.Card, error) {
+	args := m.Called(boardID, from, to)
+	return args.Get(0).([]models.Card), args.Error(1)
+}
+func (m *MockCardRepository) ClearCoverAttachment(attachmentID uint) error {
+	args := m.Called(attachmentID)
+	return args.Error(0)
+}
+func (m *MockCardRepository) GetTemplatesByBoardID(boardID uint) ([]models.Card, error) {
+	args := m.Called(boardID)
+	return args.Get(0).([]models.Card), args.Error(1)
+}
+func (m *MockCardRepository) GetCardsByIDs(ids []uint) ([]models.Card, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.Card), args.Error(1)
+}
+func (m *MockCardRepository) GetArchivedCardsByBoardID(boardID uint) ([]models.Card, error) {
+	args := m.Called(boardID)
+	return args.Get(0).([]models.Card), args.Error(1)
+}
+func (m *MockCardRepository) SumEstimatesByBoardIDs(boardIDs []uint) (map[uint]float64, error) {
+	args := m.Called(boardIDs)
+	return args.Get(0).(map[uint]float64), args.Error(1)
+}
+func (m *MockCardRepository) SumEstimatesByListIDs(listIDs []uint) (map[uint]float64, error) {
+	args := m.Called(listIDs)
+	return args.Get(0).(map[uint]float64), args.Error(1)
+}
+
+func (m *MockCardRepository) UpdateCardWithRevision(card *models.Card, revision *models.CardRevision) error {
+	args := m.Called(card, revision)
+	return args.Error(0)
+}
+
+func (m *MockCardRepository) MoveCard(card *models.Card, list *models.List, position int) error {
+	args := m.Called(card, list, position)
+	return args.Error(0)
+}
+
+func (m *MockCardRepository) UnarchiveCard(card *models.Card) error {
+	args := m.Called(card)
+	return args.Error(0)
+}
+
+func (m *MockCardRepository) MoveCardsToList(cards []models.Card, list *models.List) error {
+	args := m.Called(cards, list)
+	return args.Error(0)
+}
+
+func (m *MockCardRepository) ArchiveCardsInList(listID uint, at time.Time) ([]models.Card, error) {
+	args := m.Called(listID, at)
+	return args.Get(0).([]models.Card), args.Error(1)
+}
+
+func (m *MockCardRepository) UpdateCardPositions(cardIDs []uint) error {
+	args := m.Called(cardIDs)
+	return args.Error(0)
+}
+
+func (m *MockCardRepository) GetRevisionByID(id uint) (*models.CardRevision, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.CardRevision), args.Error(1)
+}
+
+func (m *MockCardRepository) GetRevisionsByCardIDs(cardIDs []uint) (map[uint][]models.CardRevision, error) {
+	args := m.Called(cardIDs)
+	return args.Get(0).(map[uint][]models.CardRevision), args.Error(1)
+}
+
+func (m *MockCardRepository) GetCardByReference(userID, boardKey string, number int) (*models.Card, error) {
+	args := m.Called(userID, boardKey, number)
+	return args.Get(0).(*models.Card), args.Error(1)
+}
+
+// newTestCardService 建立使用 mock repository 的 CardService；看板 3 屬於 user-2，
+// 看板 4 屬於 user-1 但已關閉，其他看板皆屬於 user-1
+func newTestCardService(repo *MockCardRepository) (CardService, *MockListRepository) {
+	listRepo := new(MockListRepository)
+	boardRepo := new(MockBoardRepository)
+	boardRepo.On("GetBoardByID", uint(1)).Return(&models.Board{ID: 1, UserID: "user-1"}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+	boardRepo.On("GetBoardByID", uint(3)).Return(&models.Board{ID: 3, UserID: "user-2"}, nil)
+	closedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	boardRepo.On("GetBoardByID", uint(4)).Return(&models.Board{ID: 4, UserID: "user-1", ClosedAt: &closedAt}, nil)
+	boardRepo.On("GetBoardByID", mock.Anything).Return(&models.Board{UserID: "user-1"}, nil)
+	return NewCardService(repo, listRepo, boardRepo), listRepo
+}
 
 func TestCardService_CreateCard(t *testing.T) {
 	repo := new(MockCardRepository)
-	service := NewCardService(repo)
+	service, listRepo := newTestCardService(repo)
 	listID := uint(1)
 	boardID := uint(2)
-	existing := []models.Card{{ID: 10, Position: 0}, {ID: 11, Position: 1}}
-	repo.On("GetCardsByListID", listID).Return(existing, nil)
-	// 期望 UpdateCard 會被呼叫兩次，且 position 變成 1, 2
-	repo.On("UpdateCard", mock.MatchedBy(func(c *models.Card) bool {
-		return (c.ID == 10 && c.Position == 1) || (c.ID == 11 && c.Position == 2)
-	})).Return(nil).Twice()
-	// 新卡片 position = 0
+	listRepo.On("GetListByID", listID).Return(&models.List{ID: listID, BoardID: boardID}, nil)
+	// 看板由清單決定，位置由 repository 在交易中安排
 	repo.On("CreateCard", mock.MatchedBy(func(c *models.Card) bool {
-		return c.ListID == listID && c.Title == "Title" && c.Content == "Content" && c.Position == 0
+		return c.ListID == listID && c.BoardID == boardID && c.Title == "Title" && c.Content == "Content"
 	})).Return(nil)
 
-	card, err := service.CreateCard(listID, boardID, "Title", "Content")
+	card, err := service.CreateCard("user-1", listID, CardDetails{Title: "Title", Content: "Content"})
 
 	repo.AssertExpectations(t)
 	assert.NoError(t, err)
 	assert.Equal(t, listID, card.ListID)
-	assert.Equal(t, 0, card.Position)
+}
+
+func TestCardService_GetReadableCard(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	repo.On("GetCardByID", uint(1)).Return(&models.Card{ID: 1, BoardID: 2}, nil)
+	repo.On("GetCardByID", uint(2)).Return(&models.Card{ID: 2, BoardID: 3}, nil)
+
+	card, err := service.GetReadableCard("user-1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), card.ID)
+
+	// 其他使用者私人看板上的卡片不能讀取
+	_, err = service.GetReadableCard("user-1", 2)
+	assert.ErrorIs(t, err, ErrForbidden)
 }
 
 func TestCardService_GetCards(t *testing.T) {
 	repo := new(MockCardRepository)
-	service := NewCardService(repo)
+	service, _ := newTestCardService(repo)
 	listID := uint(2)
 	existing := []models.Card{{ID: 1}, {ID: 2}}
 	repo.On("GetCardsByListID", listID).Return(existing, nil)
@@ -85,13 +190,16 @@ func TestCardService_GetCards(t *testing.T) {
 
 func TestCardService_UpdateCard(t *testing.T) {
 	repo := new(MockCardRepository)
-	service := NewCardService(repo)
+	service, _ := newTestCardService(repo)
 	id := uint(3)
-	old := &models.Card{ID: id, Title: "Old"}
+	old := &models.Card{ID: id, Title: "Old", Content: "內容"}
 	repo.On("GetCardByID", id).Return(old, nil)
-	repo.On("UpdateCard", old).Return(nil)
+	// 標題變更時在同一交易中保存修改紀錄
+	repo.On("UpdateCardWithRevision", old, &models.CardRevision{
+		UserID: "user-1", OldTitle: "Old", NewTitle: "New", OldContent: "內容", NewContent: "內容",
+	}).Return(nil)
 
-	err := service.UpdateCard(id, "New", "")
+	err := service.UpdateCard("user-1", id, CardDetails{Title: "New", Content: "內容"})
 
 	repo.AssertExpectations(t)
 	assert.NoError(t, err)
@@ -100,24 +208,598 @@ func TestCardService_UpdateCard(t *testing.T) {
 
 func TestCardService_DeleteCard(t *testing.T) {
 	repo := new(MockCardRepository)
-	service := NewCardService(repo)
+	service, _ := newTestCardService(repo)
 	id := uint(4)
+	repo.On("GetCardByID", id).Return(&models.Card{ID: id, BoardID: 1}, nil)
 	repo.On("DeleteCard", id).Return(nil)
 
-	err := service.DeleteCard(id)
+	err := service.DeleteCard("user-1", id)
 
 	repo.AssertExpectations(t)
 	assert.NoError(t, err)
+
+	// 其他使用者看板上的卡片不能刪除
+	repo.On("GetCardByID", uint(5)).Return(&models.Card{ID: 5, BoardID: 3}, nil)
+	assert.ErrorIs(t, service.DeleteCard("user-1", 5), ErrForbidden)
+	repo.AssertNotCalled(t, "DeleteCard", uint(5))
 }
 
 func TestCardService_MoveCard_GetError(t *testing.T) {
 	repo := new(MockCardRepository)
-	service := NewCardService(repo)
+	service, _ := newTestCardService(repo)
 	id := uint(5)
 	repo.On("GetCardByID", id).Return(nil, errors.New("not found"))
 
-	err := service.MoveCard(id, 1, 0)
+	err := service.MoveCard("user-1", id, 1, 0)
 
 	repo.AssertExpectations(t)
 	assert.Error(t, err)
 }
+
+func TestCardService_CreateCard_Forbidden(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	listRepo.On("GetListByID", uint(8)).Return(&models.List{ID: 8, BoardID: 3}, nil)
+
+	_, err := service.CreateCard("user-1", 8, CardDetails{Title: "T"})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "CreateCard", mock.Anything)
+}
+
+func TestCardService_MoveCard_CrossBoard(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	card := &models.Card{ID: 5, ListID: 10, BoardID: 1, Number: 7, Position: 0}
+	repo.On("GetCardByID", uint(5)).Return(card, nil)
+	target := &models.List{ID: 20, BoardID: 2}
+	listRepo.On("GetListByID", uint(20)).Return(target, nil)
+	repo.On("MoveCard", card, target, 0).Return(nil)
+
+	err := service.MoveCard("user-1", 5, 20, 0)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+}
+
+func TestCardService_MoveCard_Archived(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	archivedAt := time.Now()
+	repo.On("GetCardByID", uint(5)).Return(&models.Card{ID: 5, ListID: 10, BoardID: 1, Position: 3, ArchivedAt: &archivedAt}, nil)
+
+	err := service.MoveCard("user-1", 5, 20, 0)
+
+	assert.ErrorIs(t, err, ErrCardArchived)
+	listRepo.AssertNotCalled(t, "GetListByID", mock.Anything)
+	repo.AssertNotCalled(t, "MoveCard", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCardService_ArchivedTargetList(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	archivedAt := time.Now()
+	listRepo.On("GetListByID", uint(20)).Return(&models.List{ID: 20, BoardID: 1, ArchivedAt: &archivedAt}, nil)
+	repo.On("GetCardByID", uint(5)).Return(&models.Card{ID: 5, ListID: 10, BoardID: 1}, nil)
+	repo.On("GetCardByID", uint(6)).Return(&models.Card{ID: 6, ListID: 10, BoardID: 1, IsTemplate: true}, nil)
+
+	// 封存的清單不顯示，不能放入卡片
+	_, err := service.CreateCard("user-1", 20, CardDetails{Title: "T"})
+	assert.ErrorIs(t, err, ErrListArchived)
+	assert.ErrorIs(t, service.MoveCard("user-1", 5, 20, 0), ErrListArchived)
+	_, err = service.CreateCardFromTemplate("user-1", 6, 20)
+	assert.ErrorIs(t, err, ErrListArchived)
+	_, err = service.MoveCards("user-1", []uint{5}, 20)
+	assert.ErrorIs(t, err, ErrListArchived)
+
+	repo.AssertNotCalled(t, "CreateCard", mock.Anything)
+	repo.AssertNotCalled(t, "CreateCardFromTemplate", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "MoveCard", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "MoveCardsToList", mock.Anything, mock.Anything)
+}
+
+func TestCardService_MoveCard_CrossBoardForbidden(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	repo.On("GetCardByID", uint(5)).Return(&models.Card{ID: 5, ListID: 10, BoardID: 1}, nil)
+	listRepo.On("GetListByID", uint(30)).Return(&models.List{ID: 30, BoardID: 3}, nil)
+
+	err := service.MoveCard("user-1", 5, 30, 0)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "MoveCard", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCardService_CreateCard_WipLimitBlocked(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	limit := 2
+	listRepo.On("GetListByID", uint(4)).Return(&models.List{ID: 4, BoardID: 1, WipLimit: &limit, WipMode: models.WipLimitBlock}, nil)
+	// 上限由 repository 在鎖定清單後檢查，錯誤原樣回傳
+	repo.On("CreateCard", mock.Anything).Return(&WipLimitError{ListID: 4, Limit: 2, Count: 2})
+
+	_, err := service.CreateCard("user-1", 4, CardDetails{Title: "T"})
+
+	var wipErr *WipLimitError
+	assert.ErrorAs(t, err, &wipErr)
+	assert.Equal(t, &WipLimitError{ListID: 4, Limit: 2, Count: 2}, wipErr)
+}
+
+func TestCardService_MoveCard_WipLimit(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	limit := 1
+	card := &models.Card{ID: 5, ListID: 4, BoardID: 1, Position: 0}
+	repo.On("GetCardByID", uint(5)).Return(card, nil)
+	target := &models.List{ID: 6, BoardID: 1, WipLimit: &limit, WipMode: models.WipLimitBlock}
+	listRepo.On("GetListByID", uint(6)).Return(target, nil)
+	repo.On("MoveCard", card, target, 0).Return(&WipLimitError{ListID: 6, Limit: 1, Count: 1})
+
+	err := service.MoveCard("user-1", 5, 6, 0)
+
+	var wipErr *WipLimitError
+	assert.ErrorAs(t, err, &wipErr)
+}
+
+func TestCardService_UpdateCard_Dates(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	id := uint(6)
+	old := &models.Card{ID: id, Title: "Old"}
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	due := start.Add(48 * time.Hour)
+	reminder := 30
+	repo.On("GetCardByID", id).Return(old, nil)
+	repo.On("UpdateCard", old).Return(nil)
+
+	err := service.UpdateCard("user-1", id, CardDetails{Title: "Old", StartAt: &start, DueAt: &due, ReminderMinutes: &reminder})
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, &due, old.DueAt)
+	assert.Equal(t, &reminder, old.ReminderMinutes)
+}
+
+func TestCardService_UpdateCard_InvalidDates(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	start := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	due := start.Add(-time.Hour)
+	repo.On("GetCardByID", uint(7)).Return(&models.Card{ID: 7, Title: "T", StartAt: &start}, nil)
+
+	err := service.UpdateCard("user-1", 7, CardDetails{Title: "T", StartAt: &start, DueAt: &due})
+	assert.Error(t, err)
+
+	// 未指定的開始時間沿用原值一起檢查
+	err = service.UpdateCard("user-1", 7, CardDetails{Title: "T", DueAt: &due})
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "UpdateCard", mock.Anything)
+}
+
+func TestCardService_UpdateCard_OmittedDates(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	due := start.Add(48 * time.Hour)
+	reminder := 30
+	card := &models.Card{ID: 6, Title: "T", StartAt: &start, DueAt: &due, ReminderMinutes: &reminder}
+	repo.On("GetCardByID", uint(6)).Return(card, nil)
+	repo.On("UpdateCardWithRevision", card, mock.Anything).Return(nil)
+	repo.On("UpdateCard", card).Return(nil)
+
+	// 只改標題時日期與提醒維持原值
+	err := service.UpdateCard("user-1", 6, CardDetails{Title: "改名"})
+	assert.NoError(t, err)
+	assert.Equal(t, &start, card.StartAt)
+	assert.Equal(t, &due, card.DueAt)
+	assert.Equal(t, &reminder, card.ReminderMinutes)
+
+	// 清除到期時間時需一併清除提醒
+	err = service.UpdateCard("user-1", 6, CardDetails{Title: "改名", Clear: CardDueAt})
+	assert.Error(t, err)
+	err = service.UpdateCard("user-1", 6, CardDetails{Title: "改名", Clear: CardDueAt | CardReminder})
+	assert.NoError(t, err)
+	assert.Equal(t, &start, card.StartAt)
+	assert.Nil(t, card.DueAt)
+	assert.Nil(t, card.ReminderMinutes)
+}
+
+func TestCardService_UpdateCard_PriorityAndEstimate(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	card := &models.Card{ID: 6, Title: "T", Priority: models.PriorityHigh}
+	estimate := 2.5
+	urgent := models.PriorityUrgent
+	repo.On("GetCardByID", uint(6)).Return(card, nil)
+	repo.On("UpdateCard", card).Return(nil)
+
+	err := service.UpdateCard("user-1", 6, CardDetails{Title: "T", Priority: &urgent, Estimate: &estimate})
+	assert.NoError(t, err)
+	assert.Equal(t, models.PriorityUrgent, card.Priority)
+	assert.Equal(t, &estimate, card.Estimate)
+
+	// 未指定時維持原值
+	err = service.UpdateCard("user-1", 6, CardDetails{Title: "T"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.PriorityUrgent, card.Priority)
+	assert.Equal(t, &estimate, card.Estimate)
+
+	// 列在 Clear 中時優先順序設為 none、清除估計工作量
+	err = service.UpdateCard("user-1", 6, CardDetails{Title: "T", Clear: CardPriority | CardEstimate})
+	assert.NoError(t, err)
+	assert.Equal(t, models.PriorityNone, card.Priority)
+	assert.Nil(t, card.Estimate)
+}
+
+func TestCardService_UpdateCard_InvalidPriorityAndEstimate(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	negative := -1.0
+
+	critical := models.CardPriority("critical")
+
+	err := service.UpdateCard("user-1", 6, CardDetails{Title: "T", Priority: &critical})
+	assert.ErrorIs(t, err, ErrInvalidPriority)
+	err = service.UpdateCard("user-1", 6, CardDetails{Title: "T", Estimate: &negative})
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "GetCardByID", mock.Anything)
+}
+
+func TestSortCardsByPriority(t *testing.T) {
+	cards := []models.Card{
+		{ID: 1, Priority: models.PriorityLow},
+		{ID: 2, Priority: models.PriorityUrgent},
+		{ID: 3, Priority: models.PriorityNone},
+		{ID: 4, Priority: models.PriorityUrgent},
+		{ID: 5, Priority: models.PriorityMedium},
+	}
+
+	SortCardsByPriority(cards)
+
+	ids := make([]uint, len(cards))
+	for i, c := range cards {
+		ids[i] = c.ID
+	}
+	assert.Equal(t, []uint{2, 4, 5, 1, 3}, ids)
+}
+
+func TestCardService_GetDueSoonCards(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	boardID := uint(2)
+	repo.On("GetCardsDueBetween", boardID, mock.Anything, mock.Anything).
+		Return([]models.Card{{ID: 1}}, nil).
+		Run(func(args mock.Arguments) {
+			from := args.Get(1).(time.Time)
+			to := args.Get(2).(time.Time)
+			assert.Equal(t, 24*time.Hour, to.Sub(from))
+		})
+
+	cards, err := service.GetDueSoonCards("user-1", boardID, 24*time.Hour)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Len(t, cards, 1)
+}
+
+func TestCardService_DueCards_Forbidden(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+
+	// 其他使用者的私人看板不能查詢到期卡片
+	_, err := service.GetOverdueCards("user-1", 3)
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = service.GetDueSoonCards("user-1", 3, time.Hour)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "GetOverdueCards", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "GetCardsDueBetween", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCardService_ArchiveCard(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	card := &models.Card{ID: 1, ListID: 2, Position: 0}
+	repo.On("GetCardByID", uint(1)).Return(card, nil)
+	repo.On("GetCardsByListID", uint(2)).Return([]models.Card{
+		{ID: 1, ListID: 2, Position: 0},
+		{ID: 3, ListID: 2, Position: 1},
+	}, nil)
+	// 後面的卡片往前補位
+	repo.On("UpdateCard", mock.MatchedBy(func(c *models.Card) bool {
+		return c.ID == 3 && c.Position == 0
+	})).Return(nil).Once()
+	repo.On("UpdateCard", card).Return(nil).Once()
+
+	result, err := service.ArchiveCard("user-1", 1)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.NotNil(t, result.ArchivedAt)
+}
+
+func TestCardService_ArchiveCard_Forbidden(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	repo.On("GetCardByID", uint(1)).Return(&models.Card{ID: 1, ListID: 2, BoardID: 3}, nil)
+
+	_, err := service.ArchiveCard("user-1", 1)
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = service.UnarchiveCard("user-1", 1)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "UpdateCard", mock.Anything)
+	repo.AssertNotCalled(t, "UnarchiveCard", mock.Anything)
+}
+
+func TestCardService_UnarchiveCard(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	archivedAt := time.Now()
+	card := &models.Card{ID: 1, ListID: 2, Position: 5, ArchivedAt: &archivedAt}
+	repo.On("GetCardByID", uint(1)).Return(card, nil)
+	repo.On("UnarchiveCard", card).Return(nil).Once()
+
+	result, err := service.UnarchiveCard("user-1", 1)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Same(t, card, result)
+
+	// 還原會超過在製品上限時不回傳卡片
+	card.ArchivedAt = &archivedAt
+	repo.On("UnarchiveCard", card).Return(&WipLimitError{ListID: 2, Limit: 1, Count: 1}).Once()
+	_, err = service.UnarchiveCard("user-1", 1)
+	var wipErr *WipLimitError
+	assert.ErrorAs(t, err, &wipErr)
+}
+
+func TestCardService_GetArchivedCards(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	repo.On("GetArchivedCardsByBoardID", uint(2)).Return([]models.Card{{ID: 1}}, nil)
+
+	cards, err := service.GetArchivedCards("user-1", 2)
+	assert.NoError(t, err)
+	assert.Len(t, cards, 1)
+
+	// 其他使用者私人看板的封存卡片不能列出
+	_, err = service.GetArchivedCards("user-1", 3)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "GetArchivedCardsByBoardID", uint(3))
+}
+
+func TestCardService_GetTemplates(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	repo.On("GetTemplatesByBoardID", uint(2)).Return([]models.Card{{ID: 1, IsTemplate: true}}, nil)
+
+	cards, err := service.GetTemplates("user-1", 2)
+	assert.NoError(t, err)
+	assert.Len(t, cards, 1)
+
+	// 其他使用者私人看板的範本不能列出
+	_, err = service.GetTemplates("user-1", 3)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "GetTemplatesByBoardID", uint(3))
+}
+
+func TestCardService_CreateCardFromTemplate(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	listRepo.On("GetListByID", uint(3)).Return(&models.List{ID: 3, BoardID: 2}, nil)
+	reminder := 30
+	dueAt := time.Now()
+	template := &models.Card{ID: 1, Title: "Bug report", Content: "## 重現步驟", ListID: 9, BoardID: 2,
+		IsTemplate: true, ReminderMinutes: &reminder, CoverColor: "#ff0000", DueAt: &dueAt}
+	repo.On("GetCardByID", uint(1)).Return(template, nil)
+	// 建立卡片與複製自訂欄位值在同一個交易中完成
+	repo.On("CreateCardFromTemplate", mock.MatchedBy(func(c *models.Card) bool {
+		return c.ListID == 3 && c.BoardID == 2
+	}), uint(1)).Return(nil)
+
+	card, err := service.CreateCardFromTemplate("user-1", 1, 3)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bug report", card.Title)
+	assert.Equal(t, "## 重現步驟", card.Content)
+	assert.Equal(t, uint(3), card.ListID)
+	assert.Equal(t, "#ff0000", card.CoverColor)
+	assert.Equal(t, &reminder, card.ReminderMinutes)
+	assert.False(t, card.IsTemplate)
+	assert.Nil(t, card.DueAt)
+}
+
+func TestCardService_CreateCardFromTemplate_NotTemplate(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	repo.On("GetCardByID", uint(1)).Return(&models.Card{ID: 1}, nil)
+
+	_, err := service.CreateCardFromTemplate("user-1", 1, 3)
+
+	assert.ErrorIs(t, err, ErrNotTemplate)
+	repo.AssertNotCalled(t, "CreateCardFromTemplate", mock.Anything, mock.Anything)
+}
+
+func TestCardService_CreateCardFromTemplate_Forbidden(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	repo.On("GetCardByID", uint(1)).Return(&models.Card{ID: 1, BoardID: 3, IsTemplate: true}, nil)
+	repo.On("GetCardByID", uint(2)).Return(&models.Card{ID: 2, BoardID: 1, IsTemplate: true}, nil)
+	listRepo.On("GetListByID", uint(30)).Return(&models.List{ID: 30, BoardID: 3}, nil)
+	listRepo.On("GetListByID", uint(20)).Return(&models.List{ID: 20, BoardID: 2}, nil)
+
+	// 不能在其他使用者的清單建立卡片，也不能把範本用在其他看板
+	_, err := service.CreateCardFromTemplate("user-1", 1, 30)
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = service.CreateCardFromTemplate("user-1", 1, 20)
+	assert.ErrorIs(t, err, ErrTemplateOtherBoard)
+	_, err = service.CreateCardFromTemplate("user-1", 2, 20)
+	assert.ErrorIs(t, err, ErrTemplateOtherBoard)
+	_, err = service.SetTemplate("user-1", 1, false)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	repo.AssertNotCalled(t, "CreateCardFromTemplate", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "UpdateCard", mock.Anything)
+}
+
+func TestCardService_GetCardByReference(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	repo.On("GetCardByReference", "user-1", "WEB", 142).Return(&models.Card{ID: 7, Number: 142}, nil)
+
+	card, err := service.GetCardByReference("user-1", "web-142")
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), card.ID)
+
+	for _, ref := range []string{"WEB", "WEB-0", "WEB-x", "1WEB-3", "-3"} {
+		_, err := service.GetCardByReference("user-1", ref)
+		assert.ErrorIs(t, err, ErrInvalidCardReference, ref)
+	}
+}
+
+func TestCardService_RestoreRevision(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, _ := newTestCardService(repo)
+	card := &models.Card{ID: 3, BoardID: 3, Title: "亂掉的標題", Content: "亂掉的內容"}
+	repo.On("GetRevisionByID", uint(9)).Return(&models.CardRevision{
+		ID: 9, CardID: 3, OldTitle: "標題", NewTitle: "亂掉的標題", OldContent: "原本的內容", NewContent: "亂掉的內容",
+	}, nil)
+	repo.On("GetCardByID", uint(3)).Return(card, nil)
+	repo.On("UpdateCardWithRevision", card, &models.CardRevision{
+		UserID: "user-2", OldTitle: "亂掉的標題", NewTitle: "標題", OldContent: "亂掉的內容", NewContent: "原本的內容",
+	}).Return(nil)
+
+	restored, err := service.RestoreRevision("user-2", 9)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, "標題", restored.Title)
+	assert.Equal(t, "原本的內容", restored.Content)
+	// 只有看板擁有者可以還原修改紀錄或編輯卡片
+	_, err = service.RestoreRevision("user-1", 9)
+	assert.ErrorIs(t, err, ErrForbidden)
+	err = service.UpdateCard("user-1", 3, CardDetails{Title: "改名"})
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestRevisionDiff(t *testing.T) {
+	diff := RevisionDiff(&models.CardRevision{
+		OldContent: "第一行\n第二行\n",
+		NewContent: "第一行\n第二行已修改\n",
+	})
+	assert.Equal(t, "--- 修改前\n+++ 修改後\n@@ -1,2 +1,2 @@\n 第一行\n-第二行\n+第二行已修改\n", diff)
+	assert.Empty(t, RevisionDiff(&models.CardRevision{OldTitle: "A", NewTitle: "B", OldContent: "同", NewContent: "同"}))
+}
+
+func TestCardService_MoveCards(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	limit := 2
+	target := &models.List{ID: 20, BoardID: 1, WipLimit: &limit, WipMode: models.WipLimitBlock}
+	listRepo.On("GetListByID", uint(20)).Return(target, nil)
+	repo.On("GetCardsByListID", uint(20)).Return([]models.Card{{ID: 9, ListID: 20}}, nil)
+	archivedAt := time.Now()
+	repo.On("GetCardsByIDs", []uint{1, 2, 3, 4, 5}).Return([]models.Card{
+		{ID: 1, ListID: 10, BoardID: 1},
+		{ID: 2, ListID: 30, BoardID: 3},
+		{ID: 3, ListID: 10, BoardID: 1, ArchivedAt: &archivedAt},
+		{ID: 4, ListID: 11, BoardID: 2},
+	}, nil)
+	// 只有卡片 1 能移入：卡片 2 無權限、3 已封存、4 超過上限、5 不存在
+	repo.On("MoveCardsToList", []models.Card{{ID: 1, ListID: 10, BoardID: 1}}, target).Return(nil)
+
+	results, err := service.MoveCards("user-1", []uint{1, 2, 3, 4, 5, 1}, 20)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Len(t, results, 5)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, uint(10), results[0].FromListID)
+	assert.NotNil(t, results[0].Card)
+	assert.ErrorIs(t, results[1].Err, ErrForbidden)
+	assert.ErrorIs(t, results[2].Err, ErrCardArchived)
+	var wipErr *WipLimitError
+	assert.ErrorAs(t, results[3].Err, &wipErr)
+	assert.Error(t, results[4].Err)
+	assert.Nil(t, results[4].Card)
+}
+
+func TestCardService_ArchiveListCards(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	listRepo.On("GetListByID", uint(10)).Return(&models.List{ID: 10, BoardID: 1}, nil)
+	repo.On("ArchiveCardsInList", uint(10), mock.Anything).Return([]models.Card{{ID: 1}, {ID: 2}}, nil)
+
+	results, err := service.ArchiveListCards("user-1", 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, uint(2), results[1].CardID)
+
+	listRepo.On("GetListByID", uint(30)).Return(&models.List{ID: 30, BoardID: 3}, nil)
+	_, err = service.ArchiveListCards("user-1", 30)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestCardService_SortListCards(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	listRepo.On("GetListByID", uint(10)).Return(&models.List{ID: 10, BoardID: 1}, nil)
+	day := func(d int) *time.Time {
+		t := time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	cards := []models.Card{
+		{ID: 1, Title: "b", Position: 0},
+		{ID: 2, Title: "C", Position: 1, DueAt: day(3)},
+		{ID: 3, Title: "a", Position: 2, DueAt: day(1)},
+		{ID: 4, Title: "d", Position: 3},
+	}
+	repo.On("GetCardsByListID", uint(10)).Return(append([]models.Card(nil), cards...), nil).Once()
+	// 沒有到期時間的卡片在遞減時仍排在最後，並維持原本的順序
+	repo.On("UpdateCardPositions", []uint{2, 3, 1, 4}).Return(nil)
+
+	sorted, err := service.SortListCards("user-1", 10, SortByDueDate, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), sorted[0].ID)
+	assert.Equal(t, 0, sorted[0].Position)
+	assert.Equal(t, 3, sorted[3].Position)
+
+	repo.On("GetCardsByListID", uint(10)).Return(append([]models.Card(nil), cards...), nil).Once()
+	repo.On("UpdateCardPositions", []uint{3, 1, 2, 4}).Return(nil)
+
+	_, err = service.SortListCards("user-1", 10, SortByTitle, false)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+
+	_, err = service.SortListCards("user-1", 10, "color", false)
+	assert.ErrorIs(t, err, ErrInvalidSortBy)
+}
+
+func TestCardService_ClosedBoard(t *testing.T) {
+	repo := new(MockCardRepository)
+	service, listRepo := newTestCardService(repo)
+	listRepo.On("GetListByID", uint(40)).Return(&models.List{ID: 40, BoardID: 4}, nil)
+	listRepo.On("GetListByID", uint(10)).Return(&models.List{ID: 10, BoardID: 1}, nil)
+	repo.On("GetCardByID", uint(7)).Return(&models.Card{ID: 7, ListID: 40, BoardID: 4}, nil)
+	repo.On("GetCardByID", uint(8)).Return(&models.Card{ID: 8, ListID: 10, BoardID: 1}, nil)
+
+	_, err := service.CreateCard("user-1", 40, CardDetails{Title: "新卡片"})
+	assert.ErrorIs(t, err, ErrBoardClosed)
+	assert.ErrorIs(t, service.UpdateCard("user-1", 7, CardDetails{Title: "改名"}), ErrBoardClosed)
+	assert.ErrorIs(t, service.DeleteCard("user-1", 7), ErrBoardClosed)
+	_, err = service.ArchiveCard("user-1", 7)
+	assert.ErrorIs(t, err, ErrBoardClosed)
+	// 移出或移入已關閉的看板都不允許
+	assert.ErrorIs(t, service.MoveCard("user-1", 7, 10, 0), ErrBoardClosed)
+	assert.ErrorIs(t, service.MoveCard("user-1", 8, 40, 0), ErrBoardClosed)
+
+	repo.AssertNotCalled(t, "CreateCard", mock.Anything)
+	repo.AssertNotCalled(t, "UpdateCard", mock.Anything)
+	repo.AssertNotCalled(t, "DeleteCard", mock.Anything)
+	repo.AssertNotCalled(t, "MoveCard", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+)
+
+// ErrNotBoardTemplate 只能以範本看板建立新看板
+var ErrNotBoardTemplate = errors.New("看板不是範本")
+
+// CopyService 複製卡片、清單與看板，來源與目的看板都必須屬於使用者（公開的範本看板除外）。
+// 未指定 position（nil）時放在目的位置的最後
+type CopyService interface {
+	CopyCard(userID string, cardID, targetListID uint, position *int, opts repositories.CopyOptions) (*models.Card, error)
+	CopyList(userID string, listID, targetBoardID uint, name string, position *int, opts repositories.CopyOptions) (*models.List, error)
+	CopyBoard(userID string, boardID uint, name string, opts repositories.CopyOptions) (*models.Board, error)
+	CreateBoardFromTemplate(userID string, templateID uint, name string) (*models.Board, error)
+}
+
+type copyService struct {
+	copyRepo  repositories.CopyRepository
+	boardRepo repositories.BoardRepository
+	listRepo  repositories.ListRepository
+	cardRepo  repositories.CardRepository
+}
+
+func NewCopyService(
+	copyRepo repositories.CopyRepository,
+	boardRepo repositories.BoardRepository,
+	listRepo repositories.ListRepository,
+	cardRepo repositories.CardRepository,
+) CopyService {
+	return &copyService{copyRepo: copyRepo, boardRepo: boardRepo, listRepo: listRepo, cardRepo: cardRepo}
+}
+
+func (s *copyService) CopyCard(userID string, cardID, targetListID uint, position *int, opts repositories.CopyOptions) (*models.Card, error) {
+	card, err := s.cardRepo.GetCardByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardOwner(s.boardRepo, card.BoardID, userID); err != nil {
+		return nil, err
+	}
+	list, err := getTargetList(s.listRepo, targetListID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	return s.copyRepo.CopyCard(cardID, targetListID, positionOrEnd(position), opts)
+}
+
+// CopyList 複製清單，name 為空時沿用原名稱
+func (s *copyService) CopyList(userID string, listID, targetBoardID uint, name string, position *int, opts repositories.CopyOptions) (*models.List, error) {
+	list, err := s.listRepo.GetListByID(listID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardOwner(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, targetBoardID, userID); err != nil {
+		return nil, err
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		name = list.Name
+	}
+	return s.copyRepo.CopyList(listID, targetBoardID, name, positionOrEnd(position), opts)
+}
+
+// CopyBoard 複製看板，name 為空時沿用原名稱；新看板的代號由原代號加上數字產生
+func (s *copyService) CopyBoard(userID string, boardID uint, name string, opts repositories.CopyOptions) (*models.Board, error) {
+	board, err := ensureBoardOwner(s.boardRepo, boardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		name = board.Name
+	}
+	var copied *models.Board
+	err = createWithUniqueKey(s.boardRepo, userID, board.Key, func(key string) (err error) {
+		copied, err = s.copyRepo.CopyBoard(boardID, userID, name, key, opts)
+		return err
+	})
+	return copied, err
+}
+
+// CreateBoardFromTemplate 以範本看板的自訂欄位、清單、範本卡片與設定為使用者建立新看板，
+// 一般卡片屬於範本看板本身的工作，不會複製；name 為空時沿用範本名稱；可使用自己的範本或其他使用者公開的範本
+func (s *copyService) CreateBoardFromTemplate(userID string, templateID uint, name string) (*models.Board, error) {
+	template, err := s.boardRepo.GetBoardByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+	if !template.IsTemplate {
+		return nil, ErrNotBoardTemplate
+	}
+	if template.UserID != userID && template.Settings.Visibility != models.BoardPublic {
+		return nil, ErrForbidden
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		name = template.Name
+	}
+	var board *models.Board
+	err = createWithUniqueKey(s.boardRepo, userID, template.Key, func(key string) (err error) {
+		board, err = s.copyRepo.CopyBoard(templateID, userID, name, key, repositories.CopyOptions{CustomFieldValues: true, TemplateCardsOnly: true})
+		return err
+	})
+	return board, err
+}
+
+// positionOrEnd 將未指定的位置轉為 -1，由 repository 放在最後
+func positionOrEnd(position *int) int {
+	if position == nil {
+		return -1
+	}
+	return *position
+}
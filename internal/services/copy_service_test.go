@@ -0,0 +1,135 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCopyRepository struct {
+	mock.Mock
+}
+
+func (m *MockCopyRepository) CopyCard(cardID, targetListID uint, position int, opts repositories.CopyOptions) (*models.Card, error) {
+	args := m.Called(cardID, targetListID, position, opts)
+	return args.Get(0).(*models.Card), args.Error(1)
+}
+func (m *MockCopyRepository) CopyList(listID, targetBoardID uint, name string, position int, opts repositories.CopyOptions) (*models.List, error) {
+	args := m.Called(listID, targetBoardID, name, position, opts)
+	return args.Get(0).(*models.List), args.Error(1)
+}
+func (m *MockCopyRepository) CopyBoard(boardID uint, userID, name, key string, opts repositories.CopyOptions) (*models.Board, error) {
+	args := m.Called(boardID, userID, name, key, opts)
+	return args.Get(0).(*models.Board), args.Error(1)
+}
+
+func newTestCopyService() (CopyService, *MockCopyRepository, *MockBoardRepository, *MockListRepository, *MockCardRepository) {
+	copyRepo := new(MockCopyRepository)
+	boardRepo := new(MockBoardRepository)
+	listRepo := new(MockListRepository)
+	cardRepo := new(MockCardRepository)
+	boardRepo.On("GetBoardByID", uint(1)).Return(&models.Board{ID: 1, Name: "產品", Key: "PRD", UserID: "user-1"}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, Name: "行銷", UserID: "user-2"}, nil)
+	boardRepo.On("GetBoardByID", uint(3)).Return(&models.Board{
+		ID: 3, Name: "Scrum", Key: "SCR", UserID: "user-2", IsTemplate: true,
+		Settings: models.BoardSettings{Visibility: models.BoardPublic},
+	}, nil)
+	boardRepo.On("GetBoardByID", uint(4)).Return(&models.Board{ID: 4, Name: "私人範本", UserID: "user-2", IsTemplate: true}, nil)
+	return NewCopyService(copyRepo, boardRepo, listRepo, cardRepo), copyRepo, boardRepo, listRepo, cardRepo
+}
+
+func TestCopyService_CopyCard_ArchivedList(t *testing.T) {
+	service, copyRepo, _, listRepo, cardRepo := newTestCopyService()
+	cardRepo.On("GetCardByID", uint(7)).Return(&models.Card{ID: 7, BoardID: 1}, nil)
+	archivedAt := time.Now()
+	listRepo.On("GetListByID", uint(3)).Return(&models.List{ID: 3, BoardID: 1, ArchivedAt: &archivedAt}, nil)
+
+	_, err := service.CopyCard("user-1", 7, 3, nil, repositories.CopyOptions{})
+
+	assert.ErrorIs(t, err, ErrListArchived)
+	copyRepo.AssertNotCalled(t, "CopyCard", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCopyService_CopyCard(t *testing.T) {
+	service, copyRepo, _, listRepo, cardRepo := newTestCopyService()
+	cardRepo.On("GetCardByID", uint(7)).Return(&models.Card{ID: 7, BoardID: 1}, nil)
+	listRepo.On("GetListByID", uint(3)).Return(&models.List{ID: 3, BoardID: 1}, nil)
+	opts := repositories.CopyOptions{Attachments: true}
+	copyRepo.On("CopyCard", uint(7), uint(3), -1, opts).Return(&models.Card{ID: 8, ListID: 3, Position: 4}, nil)
+
+	card, err := service.CopyCard("user-1", 7, 3, nil, opts)
+
+	copyRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(8), card.ID)
+}
+
+func TestCopyService_CopyCard_ForeignTarget(t *testing.T) {
+	service, copyRepo, _, listRepo, cardRepo := newTestCopyService()
+	cardRepo.On("GetCardByID", uint(7)).Return(&models.Card{ID: 7, BoardID: 1}, nil)
+	listRepo.On("GetListByID", uint(9)).Return(&models.List{ID: 9, BoardID: 2}, nil)
+
+	_, err := service.CopyCard("user-1", 7, 9, nil, repositories.CopyOptions{})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	copyRepo.AssertNotCalled(t, "CopyCard", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCopyService_CopyList_DefaultName(t *testing.T) {
+	service, copyRepo, _, listRepo, _ := newTestCopyService()
+	listRepo.On("GetListByID", uint(3)).Return(&models.List{ID: 3, BoardID: 1, Name: "待辦"}, nil)
+	position := 0
+	copyRepo.On("CopyList", uint(3), uint(1), "待辦", 0, repositories.CopyOptions{}).Return(&models.List{ID: 4, Name: "待辦"}, nil)
+
+	list, err := service.CopyList("user-1", 3, 1, "  ", &position, repositories.CopyOptions{})
+
+	copyRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), list.ID)
+}
+
+func TestCopyService_CopyBoard(t *testing.T) {
+	service, copyRepo, boardRepo, _, _ := newTestCopyService()
+	opts := repositories.CopyOptions{CustomFieldValues: true}
+	boardRepo.On("KeyExists", "user-1", "PRD").Return(true, nil)
+	boardRepo.On("KeyExists", "user-1", "PRD2").Return(false, nil)
+	copyRepo.On("CopyBoard", uint(1), "user-1", "產品 2025", "PRD2", opts).Return(&models.Board{ID: 5, Name: "產品 2025"}, nil)
+
+	board, err := service.CopyBoard("user-1", 1, "產品 2025", opts)
+
+	copyRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), board.ID)
+
+	_, err = service.CopyBoard("user-1", 2, "", opts)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestCopyService_CreateBoardFromTemplate(t *testing.T) {
+	service, copyRepo, boardRepo, _, _ := newTestCopyService()
+	boardRepo.On("KeyExists", "user-1", "SCR").Return(false, nil)
+	opts := repositories.CopyOptions{CustomFieldValues: true, TemplateCardsOnly: true}
+	copyRepo.On("CopyBoard", uint(3), "user-1", "Scrum", "SCR", opts).Return(&models.Board{ID: 9, Name: "Scrum"}, nil)
+
+	// 其他使用者公開的範本也能使用，未指定名稱時沿用範本名稱
+	board, err := service.CreateBoardFromTemplate("user-1", 3, " ")
+
+	copyRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(9), board.ID)
+}
+
+func TestCopyService_CreateBoardFromTemplate_Rejected(t *testing.T) {
+	service, copyRepo, _, _, _ := newTestCopyService()
+
+	_, err := service.CreateBoardFromTemplate("user-1", 1, "")
+	assert.ErrorIs(t, err, ErrNotBoardTemplate)
+
+	_, err = service.CreateBoardFromTemplate("user-1", 4, "")
+	assert.ErrorIs(t, err, ErrForbidden)
+	copyRepo.AssertNotCalled(t, "CopyBoard", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+)
+
+var (
+	ErrInvalidCustomField      = errors.New("無效的自訂欄位")
+	ErrInvalidCustomFieldValue = errors.New("自訂欄位值與欄位型別不符")
+)
+
+// 文字欄位值的長度上限（字元數）
+const maxCustomFieldTextLength = 1000
+
+// CustomFieldValueDetails 設定卡片欄位值時依欄位型別填入其中一項，全部為 nil 表示清除
+type CustomFieldValueDetails struct {
+	Text     *string
+	Number   *float64
+	Date     *time.Time
+	Checked  *bool
+	OptionID *uint
+}
+
+type CustomFieldService interface {
+	CreateField(userID string, boardID uint, name string, fieldType models.CustomFieldType, options []string) (*models.CustomField, error)
+	UpdateField(userID string, id uint, name string, options []models.CustomFieldOption) (*models.CustomField, error)
+	DeleteField(userID string, id uint) error
+	GetFields(userID string, boardID uint) ([]models.CustomField, error)
+	SetCardValue(userID string, cardID, fieldID uint, details CustomFieldValueDetails) error
+	GetValuesByCardIDs(cardIDs []uint) (map[uint][]models.CustomFieldValue, error)
+	FilterCards(userID string, listID uint, filters []repositories.CustomFieldFilter) ([]models.Card, error)
+}
+
+type customFieldService struct {
+	customFieldRepo repositories.CustomFieldRepository
+	boardRepo       repositories.BoardRepository
+	listRepo        repositories.ListRepository
+	cardRepo        repositories.CardRepository
+}
+
+func NewCustomFieldService(
+	customFieldRepo repositories.CustomFieldRepository,
+	boardRepo repositories.BoardRepository,
+	listRepo repositories.ListRepository,
+	cardRepo repositories.CardRepository,
+) CustomFieldService {
+	return &customFieldService{
+		customFieldRepo: customFieldRepo,
+		boardRepo:       boardRepo,
+		listRepo:        listRepo,
+		cardRepo:        cardRepo,
+	}
+}
+
+// CreateField 在看板新增自訂欄位，排在現有欄位之後
+func (s *customFieldService) CreateField(userID string, boardID uint, name string, fieldType models.CustomFieldType, options []string) (*models.CustomField, error) {
+	if _, err := ensureBoardEditable(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: 名稱不可為空", ErrInvalidCustomField)
+	}
+	if !validCustomFieldType(fieldType) {
+		return nil, fmt.Errorf("%w: 不支援的型別 %q", ErrInvalidCustomField, fieldType)
+	}
+	fieldOptions := make([]models.CustomFieldOption, 0, len(options))
+	for _, label := range options {
+		fieldOptions = append(fieldOptions, models.CustomFieldOption{Label: label})
+	}
+	if err := validateCustomFieldOptions(fieldType, fieldOptions); err != nil {
+		return nil, err
+	}
+	existing, err := s.customFieldRepo.GetFieldsByBoardID(boardID)
+	if err != nil {
+		return nil, err
+	}
+	field := &models.CustomField{
+		BoardID:  boardID,
+		Name:     name,
+		Type:     fieldType,
+		Position: len(existing),
+		Options:  fieldOptions,
+	}
+	if err := s.customFieldRepo.CreateField(field); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// UpdateField 更新欄位名稱；options 不為 nil 時以其取代下拉選單的選項。欄位型別建立後不可變更
+func (s *customFieldService) UpdateField(userID string, id uint, name string, options []models.CustomFieldOption) (*models.CustomField, error) {
+	field, err := s.customFieldRepo.GetFieldByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, field.BoardID, userID); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: 名稱不可為空", ErrInvalidCustomField)
+	}
+	if options != nil {
+		if err := validateCustomFieldOptions(field.Type, options); err != nil {
+			return nil, err
+		}
+		// 只能保留此欄位原有的選項 ID
+		current := make(map[uint]bool, len(field.Options))
+		for _, o := range field.Options {
+			current[o.ID] = true
+		}
+		for _, o := range options {
+			if o.ID != 0 && !current[o.ID] {
+				return nil, fmt.Errorf("%w: 選項 %d 不屬於此欄位", ErrInvalidCustomField, o.ID)
+			}
+		}
+		if err := s.customFieldRepo.SaveOptions(field.ID, options); err != nil {
+			return nil, err
+		}
+		field.Options = options
+	}
+	field.Name = name
+	if err := s.customFieldRepo.UpdateField(field); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+func (s *customFieldService) DeleteField(userID string, id uint) error {
+	field, err := s.customFieldRepo.GetFieldByID(id)
+	if err != nil {
+		return err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, field.BoardID, userID); err != nil {
+		return err
+	}
+	return s.customFieldRepo.DeleteField(id)
+}
+
+// GetFields 取得使用者能閱讀的看板的自訂欄位
+func (s *customFieldService) GetFields(userID string, boardID uint) ([]models.CustomField, error) {
+	if _, err := ensureBoardReadable(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	return s.customFieldRepo.GetFieldsByBoardID(boardID)
+}
+
+// SetCardValue 依欄位型別驗證並設定卡片的欄位值
+func (s *customFieldService) SetCardValue(userID string, cardID, fieldID uint, details CustomFieldValueDetails) error {
+	card, err := s.cardRepo.GetCardByID(cardID)
+	if err != nil {
+		return err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return err
+	}
+	field, err := s.customFieldRepo.GetFieldByID(fieldID)
+	if err != nil {
+		return err
+	}
+	if field.BoardID != card.BoardID {
+		return fmt.Errorf("%w: 欄位不屬於卡片所在的看板", ErrInvalidCustomField)
+	}
+	value, err := buildCustomFieldValue(field, details)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return s.customFieldRepo.DeleteValue(cardID, fieldID)
+	}
+	value.CardID = cardID
+	value.CustomFieldID = fieldID
+	return s.customFieldRepo.UpsertValue(value)
+}
+
+func (s *customFieldService) GetValuesByCardIDs(cardIDs []uint) (map[uint][]models.CustomFieldValue, error) {
+	return s.customFieldRepo.GetValuesByCardIDs(cardIDs)
+}
+
+// FilterCards 取得清單中符合所有自訂欄位條件的卡片，條件必須符合欄位型別；清單所在的看板必須是使用者能閱讀的看板
+func (s *customFieldService) FilterCards(userID string, listID uint, filters []repositories.CustomFieldFilter) ([]models.Card, error) {
+	list, err := s.listRepo.GetListByID(listID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardReadable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	if len(filters) == 0 {
+		return s.cardRepo.GetCardsByListID(listID)
+	}
+	for _, f := range filters {
+		field, err := s.customFieldRepo.GetFieldByID(f.FieldID)
+		if err != nil {
+			return nil, err
+		}
+		if field.BoardID != list.BoardID {
+			return nil, fmt.Errorf("%w: 欄位不屬於清單所在的看板", ErrInvalidCustomField)
+		}
+		if err := validateCustomFieldFilter(field, f); err != nil {
+			return nil, err
+		}
+	}
+	return s.customFieldRepo.FindCardsByListID(listID, filters)
+}
+
+func validCustomFieldType(t models.CustomFieldType) bool {
+	switch t {
+	case models.CustomFieldText, models.CustomFieldNumber, models.CustomFieldDate,
+		models.CustomFieldCheckbox, models.CustomFieldDropdown:
+		return true
+	}
+	return false
+}
+
+// validateCustomFieldOptions 只有下拉選單可以（且必須）有選項，選項名稱不可空白或重複
+func validateCustomFieldOptions(t models.CustomFieldType, options []models.CustomFieldOption) error {
+	if t != models.CustomFieldDropdown {
+		if len(options) > 0 {
+			return fmt.Errorf("%w: 只有下拉選單可以設定選項", ErrInvalidCustomField)
+		}
+		return nil
+	}
+	if len(options) == 0 {
+		return fmt.Errorf("%w: 下拉選單至少需要一個選項", ErrInvalidCustomField)
+	}
+	seen := make(map[string]bool, len(options))
+	for i := range options {
+		label := strings.TrimSpace(options[i].Label)
+		if label == "" {
+			return fmt.Errorf("%w: 選項名稱不可為空", ErrInvalidCustomField)
+		}
+		if seen[label] {
+			return fmt.Errorf("%w: 選項「%s」重複", ErrInvalidCustomField, label)
+		}
+		seen[label] = true
+		options[i].Label = label
+		options[i].Position = i
+	}
+	return nil
+}
+
+// buildCustomFieldValue 依欄位型別建立欄位值，沒有任何值時回傳 nil 表示清除
+func buildCustomFieldValue(field *models.CustomField, d CustomFieldValueDetails) (*models.CustomFieldValue, error) {
+	set := 0
+	for _, present := range []bool{d.Text != nil, d.Number != nil, d.Date != nil, d.Checked != nil, d.OptionID != nil} {
+		if present {
+			set++
+		}
+	}
+	if set == 0 {
+		return nil, nil
+	}
+	mismatch := fmt.Errorf("%w: 欄位「%s」的型別為 %s", ErrInvalidCustomFieldValue, field.Name, field.Type)
+	if set > 1 {
+		return nil, mismatch
+	}
+	value := &models.CustomFieldValue{}
+	switch field.Type {
+	case models.CustomFieldText:
+		if d.Text == nil {
+			return nil, mismatch
+		}
+		if *d.Text == "" {
+			return nil, nil
+		}
+		if utf8.RuneCountInString(*d.Text) > maxCustomFieldTextLength {
+			return nil, fmt.Errorf("%w: 文字長度不可超過 %d 個字元", ErrInvalidCustomFieldValue, maxCustomFieldTextLength)
+		}
+		value.TextValue = d.Text
+	case models.CustomFieldNumber:
+		if d.Number == nil {
+			return nil, mismatch
+		}
+		if math.IsNaN(*d.Number) || math.IsInf(*d.Number, 0) {
+			return nil, fmt.Errorf("%w: 無效的數字", ErrInvalidCustomFieldValue)
+		}
+		value.NumberValue = d.Number
+	case models.CustomFieldDate:
+		if d.Date == nil {
+			return nil, mismatch
+		}
+		value.DateValue = d.Date
+	case models.CustomFieldCheckbox:
+		if d.Checked == nil {
+			return nil, mismatch
+		}
+		value.CheckboxValue = d.Checked
+	case models.CustomFieldDropdown:
+		if d.OptionID == nil {
+			return nil, mismatch
+		}
+		if !hasCustomFieldOption(field, *d.OptionID) {
+			return nil, fmt.Errorf("%w: 選項 %d 不屬於欄位「%s」", ErrInvalidCustomFieldValue, *d.OptionID, field.Name)
+		}
+		value.OptionID = d.OptionID
+	default:
+		return nil, mismatch
+	}
+	return value, nil
+}
+
+// validateCustomFieldFilter 確認篩選條件適用於欄位型別
+func validateCustomFieldFilter(field *models.CustomField, f repositories.CustomFieldFilter) error {
+	allowed := map[models.CustomFieldType]bool{
+		models.CustomFieldText:     f.Min == nil && f.Max == nil && f.From == nil && f.To == nil && f.Checked == nil && len(f.OptionIDs) == 0,
+		models.CustomFieldNumber:   f.Text == nil && f.From == nil && f.To == nil && f.Checked == nil && len(f.OptionIDs) == 0,
+		models.CustomFieldDate:     f.Text == nil && f.Min == nil && f.Max == nil && f.Checked == nil && len(f.OptionIDs) == 0,
+		models.CustomFieldCheckbox: f.Text == nil && f.Min == nil && f.Max == nil && f.From == nil && f.To == nil && len(f.OptionIDs) == 0,
+		models.CustomFieldDropdown: f.Text == nil && f.Min == nil && f.Max == nil && f.From == nil && f.To == nil && f.Checked == nil,
+	}
+	if !allowed[field.Type] {
+		return fmt.Errorf("%w: 篩選條件不適用於型別為 %s 的欄位「%s」", ErrInvalidCustomField, field.Type, field.Name)
+	}
+	for _, id := range f.OptionIDs {
+		if !hasCustomFieldOption(field, id) {
+			return fmt.Errorf("%w: 選項 %d 不屬於欄位「%s」", ErrInvalidCustomField, id, field.Name)
+		}
+	}
+	return nil
+}
+
+func hasCustomFieldOption(field *models.CustomField, optionID uint) bool {
+	for _, o := range field.Options {
+		if o.ID == optionID {
+			return true
+		}
+	}
+	return false
+}
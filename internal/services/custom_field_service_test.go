@@ -0,0 +1,219 @@
+package services
+
+import (
+	"testing"
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCustomFieldRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomFieldRepository) CreateField(field *models.CustomField) error {
+	args := m.Called(field)
+	return args.Error(0)
+}
+func (m *MockCustomFieldRepository) GetFieldByID(id uint) (*models.CustomField, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.CustomField), args.Error(1)
+}
+func (m *MockCustomFieldRepository) GetFieldsByBoardID(boardID uint) ([]models.CustomField, error) {
+	args := m.Called(boardID)
+	return args.Get(0).([]models.CustomField), args.Error(1)
+}
+func (m *MockCustomFieldRepository) UpdateField(field *models.CustomField) error {
+	args := m.Called(field)
+	return args.Error(0)
+}
+func (m *MockCustomFieldRepository) SaveOptions(fieldID uint, options []models.CustomFieldOption) error {
+	args := m.Called(fieldID, options)
+	return args.Error(0)
+}
+func (m *MockCustomFieldRepository) DeleteField(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+func (m *MockCustomFieldRepository) GetValuesByCardIDs(cardIDs []uint) (map[uint][]models.CustomFieldValue, error) {
+	args := m.Called(cardIDs)
+	return args.Get(0).(map[uint][]models.CustomFieldValue), args.Error(1)
+}
+func (m *MockCustomFieldRepository) UpsertValue(value *models.CustomFieldValue) error {
+	args := m.Called(value)
+	return args.Error(0)
+}
+func (m *MockCustomFieldRepository) DeleteValue(cardID, fieldID uint) error {
+	args := m.Called(cardID, fieldID)
+	return args.Error(0)
+}
+func (m *MockCustomFieldRepository) FindCardsByListID(listID uint, filters []repositories.CustomFieldFilter) ([]models.Card, error) {
+	args := m.Called(listID, filters)
+	return args.Get(0).([]models.Card), args.Error(1)
+}
+
+func newTestCustomFieldService() (CustomFieldService, *MockCustomFieldRepository, *MockBoardRepository, *MockListRepository, *MockCardRepository) {
+	repo := new(MockCustomFieldRepository)
+	boardRepo := new(MockBoardRepository)
+	listRepo := new(MockListRepository)
+	cardRepo := new(MockCardRepository)
+	return NewCustomFieldService(repo, boardRepo, listRepo, cardRepo), repo, boardRepo, listRepo, cardRepo
+}
+
+var dropdownField = &models.CustomField{
+	ID: 5, BoardID: 2, Name: "環境", Type: models.CustomFieldDropdown,
+	Options: []models.CustomFieldOption{{ID: 11, Label: "staging"}, {ID: 12, Label: "production"}},
+}
+
+func TestCustomFieldService_CreateField(t *testing.T) {
+	service, repo, boardRepo, _, _ := newTestCustomFieldService()
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+	repo.On("GetFieldsByBoardID", uint(2)).Return([]models.CustomField{{ID: 1}}, nil)
+	repo.On("CreateField", mock.Anything).Return(nil)
+
+	f, err := service.CreateField("user-1", 2, " 環境 ", models.CustomFieldDropdown, []string{"staging", " production "})
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, "環境", f.Name)
+	assert.Equal(t, 1, f.Position)
+	assert.Equal(t, "production", f.Options[1].Label)
+	assert.Equal(t, 1, f.Options[1].Position)
+}
+
+func TestCustomFieldService_CreateField_InvalidOptions(t *testing.T) {
+	service, repo, boardRepo, _, _ := newTestCustomFieldService()
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+
+	_, err := service.CreateField("user-1", 2, "客戶", models.CustomFieldText, []string{"a"})
+	assert.ErrorIs(t, err, ErrInvalidCustomField)
+
+	_, err = service.CreateField("user-1", 2, "環境", models.CustomFieldDropdown, nil)
+	assert.ErrorIs(t, err, ErrInvalidCustomField)
+
+	_, err = service.CreateField("user-1", 2, "環境", models.CustomFieldDropdown, []string{"a", "a"})
+	assert.ErrorIs(t, err, ErrInvalidCustomField)
+
+	repo.AssertNotCalled(t, "CreateField", mock.Anything)
+}
+
+func TestCustomFieldService_UpdateField_ForeignOption(t *testing.T) {
+	service, repo, boardRepo, _, _ := newTestCustomFieldService()
+	repo.On("GetFieldByID", uint(5)).Return(&models.CustomField{
+		ID: 5, BoardID: 2, Name: "環境", Type: models.CustomFieldDropdown,
+		Options: []models.CustomFieldOption{{ID: 11, Label: "staging"}},
+	}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+
+	_, err := service.UpdateField("user-1", 5, "環境", []models.CustomFieldOption{{ID: 99, Label: "x"}})
+
+	assert.ErrorIs(t, err, ErrInvalidCustomField)
+	repo.AssertNotCalled(t, "SaveOptions", mock.Anything, mock.Anything)
+}
+
+func TestCustomFieldService_SetCardValue(t *testing.T) {
+	service, repo, boardRepo, _, cardRepo := newTestCustomFieldService()
+	cardRepo.On("GetCardByID", uint(1)).Return(&models.Card{ID: 1, BoardID: 2}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+	repo.On("GetFieldByID", uint(5)).Return(dropdownField, nil)
+	optionID := uint(12)
+	repo.On("UpsertValue", mock.MatchedBy(func(v *models.CustomFieldValue) bool {
+		return v.CardID == 1 && v.CustomFieldID == 5 && v.OptionID != nil && *v.OptionID == 12
+	})).Return(nil)
+
+	err := service.SetCardValue("user-1", 1, 5, CustomFieldValueDetails{OptionID: &optionID})
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+}
+
+func TestCustomFieldService_SetCardValue_TypeMismatch(t *testing.T) {
+	service, repo, boardRepo, _, cardRepo := newTestCustomFieldService()
+	cardRepo.On("GetCardByID", uint(1)).Return(&models.Card{ID: 1, BoardID: 2}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+	repo.On("GetFieldByID", uint(5)).Return(dropdownField, nil)
+	text := "staging"
+	unknownOption := uint(99)
+
+	err := service.SetCardValue("user-1", 1, 5, CustomFieldValueDetails{Text: &text})
+	assert.ErrorIs(t, err, ErrInvalidCustomFieldValue)
+
+	err = service.SetCardValue("user-1", 1, 5, CustomFieldValueDetails{OptionID: &unknownOption})
+	assert.ErrorIs(t, err, ErrInvalidCustomFieldValue)
+
+	repo.AssertNotCalled(t, "UpsertValue", mock.Anything)
+}
+
+func TestCustomFieldService_SetCardValue_Clear(t *testing.T) {
+	service, repo, boardRepo, _, cardRepo := newTestCustomFieldService()
+	cardRepo.On("GetCardByID", uint(1)).Return(&models.Card{ID: 1, BoardID: 2}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+	repo.On("GetFieldByID", uint(5)).Return(dropdownField, nil)
+	repo.On("DeleteValue", uint(1), uint(5)).Return(nil)
+
+	err := service.SetCardValue("user-1", 1, 5, CustomFieldValueDetails{})
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+}
+
+func TestCustomFieldService_FilterCards(t *testing.T) {
+	service, repo, boardRepo, listRepo, _ := newTestCustomFieldService()
+	listRepo.On("GetListByID", uint(3)).Return(&models.List{ID: 3, BoardID: 2}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+	repo.On("GetFieldByID", uint(5)).Return(dropdownField, nil)
+	filters := []repositories.CustomFieldFilter{{FieldID: 5, OptionIDs: []uint{11}}}
+	repo.On("FindCardsByListID", uint(3), filters).Return([]models.Card{{ID: 1}}, nil)
+
+	cards, err := service.FilterCards("user-1", 3, filters)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Len(t, cards, 1)
+}
+
+func TestCustomFieldService_ReadForbidden(t *testing.T) {
+	service, repo, boardRepo, listRepo, cardRepo := newTestCustomFieldService()
+	listRepo.On("GetListByID", uint(3)).Return(&models.List{ID: 3, BoardID: 2}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+
+	_, err := service.GetFields("user-2", 2)
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = service.FilterCards("user-2", 3, nil)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "GetFieldsByBoardID", mock.Anything)
+	cardRepo.AssertNotCalled(t, "GetCardsByListID", mock.Anything)
+}
+
+func TestCustomFieldService_ReadPublicBoard(t *testing.T) {
+	service, repo, boardRepo, listRepo, cardRepo := newTestCustomFieldService()
+	listRepo.On("GetListByID", uint(3)).Return(&models.List{ID: 3, BoardID: 2}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{
+		ID: 2, UserID: "user-1", Settings: models.BoardSettings{Visibility: models.BoardPublic},
+	}, nil)
+	repo.On("GetFieldsByBoardID", uint(2)).Return([]models.CustomField{{ID: 1}}, nil)
+	cardRepo.On("GetCardsByListID", uint(3)).Return([]models.Card{{ID: 1}}, nil)
+
+	// 公開看板的讀者與其他讀取路徑相同，可以看到欄位與卡片
+	fields, err := service.GetFields("user-2", 2)
+	assert.NoError(t, err)
+	assert.Len(t, fields, 1)
+	cards, err := service.FilterCards("user-2", 3, nil)
+	assert.NoError(t, err)
+	assert.Len(t, cards, 1)
+}
+
+func TestCustomFieldService_FilterCards_WrongCriteria(t *testing.T) {
+	service, repo, boardRepo, listRepo, _ := newTestCustomFieldService()
+	listRepo.On("GetListByID", uint(3)).Return(&models.List{ID: 3, BoardID: 2}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1"}, nil)
+	repo.On("GetFieldByID", uint(5)).Return(dropdownField, nil)
+	min := 3.0
+
+	_, err := service.FilterCards("user-1", 3, []repositories.CustomFieldFilter{{FieldID: 5, Min: &min}})
+
+	assert.ErrorIs(t, err, ErrInvalidCustomField)
+	repo.AssertNotCalled(t, "FindCardsByListID", mock.Anything, mock.Anything)
+}
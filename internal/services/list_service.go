@@ -1,28 +1,46 @@
 package services
 
 import (
+	"errors"
+	"time"
+
 	"trello-backend/internal/models"
 	"trello-backend/internal/repositories"
 )
 
+// ListService 管理看板中的清單；已關閉看板中的清單不能變更
 type ListService interface {
 	CreateList(boardID uint, name string) (*models.List, error)
 	GetLists(boardID uint) ([]models.List, error)
 	GetListByID(id uint) (*models.List, error)
+	GetReadableLists(userID string, boardID uint) ([]models.List, error)
+	GetReadableList(userID string, id uint) (*models.List, error)
 	UpdateList(id uint, name string) error
-	DeleteList(id uint) error
+	DeleteList(userID string, id uint) error
 	MoveList(id uint, newPosition int) error
+	ArchiveList(userID string, id uint) (*models.List, error)
+	UnarchiveList(userID string, id uint) (*models.List, error)
+	GetArchivedLists(userID string, boardID uint) ([]models.List, error)
+	SetListDone(userID string, id uint, done bool) (*models.List, error)
+	SetWipLimit(userID string, id uint, limit *int, mode models.WipLimitMode) (*models.List, error)
 }
 
+// ErrInvalidWipLimit 在製品上限須為正整數，執行方式須為 warn 或 block
+var ErrInvalidWipLimit = errors.New("無效的在製品上限")
+
 type listService struct {
-	listRepo repositories.ListRepository
+	listRepo  repositories.ListRepository
+	boardRepo repositories.BoardRepository
 }
 
-func NewListService(repo repositories.ListRepository) ListService {
-	return &listService{listRepo: repo}
+func NewListService(repo repositories.ListRepository, boardRepo repositories.BoardRepository) ListService {
+	return &listService{listRepo: repo, boardRepo: boardRepo}
 }
 
 func (s *listService) CreateList(boardID uint, name string) (*models.List, error) {
+	if err := ensureBoardOpen(s.boardRepo, boardID); err != nil {
+		return nil, err
+	}
 	lists, err := s.listRepo.GetListsByBoardID(boardID)
 	if err != nil {
 		return nil, err
@@ -43,16 +61,47 @@ func (s *listService) GetListByID(id uint) (*models.List, error) {
 	return s.listRepo.GetListByID(id)
 }
 
+// GetReadableLists 取得使用者能閱讀的看板中的清單
+func (s *listService) GetReadableLists(userID string, boardID uint) ([]models.List, error) {
+	if _, err := ensureBoardReadable(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	return s.listRepo.GetListsByBoardID(boardID)
+}
+
+// GetReadableList 取得清單，清單所在的看板必須是使用者能閱讀的看板
+func (s *listService) GetReadableList(userID string, id uint) (*models.List, error) {
+	list, err := s.listRepo.GetListByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardReadable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
 func (s *listService) UpdateList(id uint, name string) error {
 	list, err := s.listRepo.GetListByID(id)
 	if err != nil {
 		return err
 	}
+	if err := ensureBoardOpen(s.boardRepo, list.BoardID); err != nil {
+		return err
+	}
 	list.Name = name
 	return s.listRepo.UpdateList(list)
 }
 
-func (s *listService) DeleteList(id uint) error {
+// DeleteList 將清單與其中的卡片移至垃圾桶，只有看板擁有者能刪除，與從垃圾桶還原相同
+func (s *listService) DeleteList(userID string, id uint) error {
+	list, err := s.listRepo.GetListByID(id)
+	if err != nil {
+		return err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return err
+	}
 	return s.listRepo.DeleteList(id)
 }
 
@@ -61,6 +110,9 @@ func (s *listService) MoveList(id uint, newPosition int) error {
 	if err != nil {
 		return err
 	}
+	if err := ensureBoardOpen(s.boardRepo, list.BoardID); err != nil {
+		return err
+	}
 	oldPos := list.Position
 	if newPosition == oldPos {
 		return nil
@@ -92,3 +144,115 @@ func (s *listService) MoveList(id uint, newPosition int) error {
 	list.Position = newPosition
 	return s.listRepo.UpdateList(list)
 }
+
+// ArchiveList 封存清單，並將後面的清單往前補位；清單中的卡片維持原狀，隨清單一起隱藏
+func (s *listService) ArchiveList(userID string, id uint) (*models.List, error) {
+	list, err := s.listRepo.GetListByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	if list.ArchivedAt != nil {
+		return list, nil
+	}
+	lists, err := s.listRepo.GetListsByBoardID(list.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lists {
+		if l.ID != id && l.Position > list.Position {
+			l.Position--
+			if err := s.listRepo.UpdateList(&l); err != nil {
+				return nil, err
+			}
+		}
+	}
+	now := time.Now()
+	list.ArchivedAt = &now
+	if err := s.listRepo.UpdateList(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// UnarchiveList 還原清單，盡量放回封存前的位置
+func (s *listService) UnarchiveList(userID string, id uint) (*models.List, error) {
+	list, err := s.listRepo.GetListByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	if list.ArchivedAt == nil {
+		return list, nil
+	}
+	lists, err := s.listRepo.GetListsByBoardID(list.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	position := min(max(list.Position, 0), len(lists))
+	for _, l := range lists {
+		if l.Position >= position {
+			l.Position++
+			if err := s.listRepo.UpdateList(&l); err != nil {
+				return nil, err
+			}
+		}
+	}
+	list.Position = position
+	list.ArchivedAt = nil
+	if err := s.listRepo.UpdateList(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetArchivedLists 取得使用者能閱讀的看板中已封存的清單
+func (s *listService) GetArchivedLists(userID string, boardID uint) ([]models.List, error) {
+	if _, err := ensureBoardReadable(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	return s.listRepo.GetArchivedListsByBoardID(boardID)
+}
+
+// SetListDone 標記清單是否為完成清單
+func (s *listService) SetListDone(userID string, id uint, done bool) (*models.List, error) {
+	list, err := s.listRepo.GetListByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	list.IsDone = done
+	if err := s.listRepo.UpdateList(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// SetWipLimit 設定清單的在製品上限，limit 為 nil 時取消限制
+func (s *listService) SetWipLimit(userID string, id uint, limit *int, mode models.WipLimitMode) (*models.List, error) {
+	if limit != nil && *limit < 1 {
+		return nil, ErrInvalidWipLimit
+	}
+	if mode != models.WipLimitWarn && mode != models.WipLimitBlock {
+		return nil, ErrInvalidWipLimit
+	}
+	list, err := s.listRepo.GetListByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	list.WipLimit = limit
+	list.WipMode = mode
+	if err := s.listRepo.UpdateList(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"testing"
+	"time"
 	"trello-backend/internal/models"
 
 	"github.com/stretchr/testify/assert"
@@ -32,10 +33,24 @@ func (m *MockListRepository) DeleteList(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
+func (m *MockListRepository) GetArchivedListsByBoardID(boardID uint) ([]models.List, error) {
+	args := m.Called(boardID)
+	return args.Get(0).([]models.List), args.Error(1)
+}
+
+// newTestListService 看板 3 屬於 user-2，看板 9 屬於 user-1 但已關閉，其他看板皆屬於 user-1
+func newTestListService(repo *MockListRepository) ListService {
+	boardRepo := new(MockBoardRepository)
+	closedAt := time.Now()
+	boardRepo.On("GetBoardByID", uint(3)).Return(&models.Board{ID: 3, UserID: "user-2"}, nil)
+	boardRepo.On("GetBoardByID", uint(9)).Return(&models.Board{ID: 9, UserID: "user-1", ClosedAt: &closedAt}, nil)
+	boardRepo.On("GetBoardByID", mock.Anything).Return(&models.Board{UserID: "user-1"}, nil)
+	return NewListService(repo, boardRepo)
+}
 
 func TestListService_CreateList(t *testing.T) {
 	repo := new(MockListRepository)
-	service := NewListService(repo)
+	service := newTestListService(repo)
 	boardID := uint(1)
 	existing := []models.List{{Position: 0}, {Position: 1}}
 	repo.On("GetListsByBoardID", boardID).Return(existing, nil)
@@ -54,7 +69,7 @@ func TestListService_CreateList(t *testing.T) {
 
 func TestListService_GetLists(t *testing.T) {
 	repo := new(MockListRepository)
-	service := NewListService(repo)
+	service := newTestListService(repo)
 	boardID := uint(2)
 	existing := []models.List{{ID: 1}, {ID: 2}}
 	repo.On("GetListsByBoardID", boardID).Return(existing, nil)
@@ -66,9 +81,42 @@ func TestListService_GetLists(t *testing.T) {
 	assert.Len(t, lists, 2)
 }
 
+func TestListService_GetArchivedLists(t *testing.T) {
+	repo := new(MockListRepository)
+	service := newTestListService(repo)
+	repo.On("GetArchivedListsByBoardID", uint(2)).Return([]models.List{{ID: 1}}, nil)
+
+	lists, err := service.GetArchivedLists("user-1", 2)
+	assert.NoError(t, err)
+	assert.Len(t, lists, 1)
+
+	// 其他使用者私人看板的封存清單不能列出
+	_, err = service.GetArchivedLists("user-1", 3)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "GetArchivedListsByBoardID", uint(3))
+}
+
+func TestListService_GetReadableLists(t *testing.T) {
+	repo := new(MockListRepository)
+	service := newTestListService(repo)
+	repo.On("GetListsByBoardID", uint(2)).Return([]models.List{{ID: 1}}, nil)
+	repo.On("GetListByID", uint(5)).Return(&models.List{ID: 5, BoardID: 3}, nil)
+
+	lists, err := service.GetReadableLists("user-1", 2)
+	assert.NoError(t, err)
+	assert.Len(t, lists, 1)
+
+	// 其他使用者的私人看板不能讀取
+	_, err = service.GetReadableLists("user-1", 3)
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = service.GetReadableList("user-1", 5)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "GetListsByBoardID", uint(3))
+}
+
 func TestListService_UpdateList(t *testing.T) {
 	repo := new(MockListRepository)
-	service := NewListService(repo)
+	service := newTestListService(repo)
 	id := uint(3)
 	old := &models.List{ID: id, Name: "Old"}
 	repo.On("GetListByID", id).Return(old, nil)
@@ -83,19 +131,25 @@ func TestListService_UpdateList(t *testing.T) {
 
 func TestListService_DeleteList(t *testing.T) {
 	repo := new(MockListRepository)
-	service := NewListService(repo)
+	service := newTestListService(repo)
 	id := uint(4)
+	repo.On("GetListByID", id).Return(&models.List{ID: id, BoardID: 1}, nil)
 	repo.On("DeleteList", id).Return(nil)
 
-	err := service.DeleteList(id)
+	err := service.DeleteList("user-1", id)
 
 	repo.AssertExpectations(t)
 	assert.NoError(t, err)
+
+	// 其他使用者看板上的清單不能刪除
+	repo.On("GetListByID", uint(5)).Return(&models.List{ID: 5, BoardID: 3}, nil)
+	assert.ErrorIs(t, service.DeleteList("user-1", 5), ErrForbidden)
+	repo.AssertNotCalled(t, "DeleteList", uint(5))
 }
 
 func TestListService_MoveList_NoOp(t *testing.T) {
 	repo := new(MockListRepository)
-	service := NewListService(repo)
+	service := newTestListService(repo)
 	id := uint(5)
 	list := &models.List{ID: id, Position: 2}
 	repo.On("GetListByID", id).Return(list, nil)
@@ -105,3 +159,102 @@ func TestListService_MoveList_NoOp(t *testing.T) {
 	repo.AssertExpectations(t)
 	assert.NoError(t, err)
 }
+
+func TestListService_UnarchiveList(t *testing.T) {
+	repo := new(MockListRepository)
+	service := newTestListService(repo)
+	archivedAt := time.Now()
+	list := &models.List{ID: 1, BoardID: 2, Position: 1, ArchivedAt: &archivedAt}
+	repo.On("GetListByID", uint(1)).Return(list, nil)
+	repo.On("GetListsByBoardID", uint(2)).Return([]models.List{
+		{ID: 3, BoardID: 2, Position: 0},
+		{ID: 4, BoardID: 2, Position: 1},
+	}, nil)
+	// 放回原位置，後面的清單往後移
+	repo.On("UpdateList", mock.MatchedBy(func(l *models.List) bool {
+		return l.ID == 4 && l.Position == 2
+	})).Return(nil).Once()
+	repo.On("UpdateList", list).Return(nil).Once()
+
+	result, err := service.UnarchiveList("user-1", 1)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Nil(t, result.ArchivedAt)
+	assert.Equal(t, 1, result.Position)
+}
+
+func TestListService_ArchiveList_Forbidden(t *testing.T) {
+	repo := new(MockListRepository)
+	service := newTestListService(repo)
+	repo.On("GetListByID", uint(1)).Return(&models.List{ID: 1, BoardID: 3}, nil)
+
+	_, err := service.ArchiveList("user-1", 1)
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = service.UnarchiveList("user-1", 1)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "UpdateList", mock.Anything)
+}
+
+func TestListService_SetListDone(t *testing.T) {
+	repo := new(MockListRepository)
+	service := newTestListService(repo)
+	list := &models.List{ID: 1, BoardID: 2}
+	repo.On("GetListByID", uint(1)).Return(list, nil)
+	repo.On("GetListByID", uint(5)).Return(&models.List{ID: 5, BoardID: 3}, nil)
+	repo.On("UpdateList", list).Return(nil)
+
+	result, err := service.SetListDone("user-1", 1, true)
+	assert.NoError(t, err)
+	assert.True(t, result.IsDone)
+
+	// 其他使用者的清單不能標記為完成
+	_, err = service.SetListDone("user-1", 5, true)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNumberOfCalls(t, "UpdateList", 1)
+}
+
+func TestListService_SetWipLimit(t *testing.T) {
+	repo := new(MockListRepository)
+	service := newTestListService(repo)
+	list := &models.List{ID: 1}
+	repo.On("GetListByID", uint(1)).Return(list, nil)
+	repo.On("UpdateList", list).Return(nil)
+	limit := 3
+
+	result, err := service.SetWipLimit("user-1", 1, &limit, models.WipLimitBlock)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, &limit, result.WipLimit)
+	assert.Equal(t, models.WipLimitBlock, result.WipMode)
+
+	zero := 0
+	_, err = service.SetWipLimit("user-1", 1, &zero, models.WipLimitWarn)
+	assert.ErrorIs(t, err, ErrInvalidWipLimit)
+	_, err = service.SetWipLimit("user-1", 1, nil, "strict")
+	assert.ErrorIs(t, err, ErrInvalidWipLimit)
+
+	// 其他使用者的清單不能設定上限
+	repo.On("GetListByID", uint(5)).Return(&models.List{ID: 5, BoardID: 3}, nil)
+	_, err = service.SetWipLimit("user-1", 5, &limit, models.WipLimitBlock)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNumberOfCalls(t, "UpdateList", 1)
+}
+
+func TestListService_ClosedBoard(t *testing.T) {
+	repo := new(MockListRepository)
+	service := newTestListService(repo)
+	repo.On("GetListByID", uint(1)).Return(&models.List{ID: 1, BoardID: 9, Name: "待辦"}, nil)
+
+	_, err := service.CreateList(9, "新清單")
+	assert.ErrorIs(t, err, ErrBoardClosed)
+	assert.ErrorIs(t, service.UpdateList(1, "完成"), ErrBoardClosed)
+	assert.ErrorIs(t, service.DeleteList("user-1", 1), ErrBoardClosed)
+	_, err = service.ArchiveList("user-1", 1)
+	assert.ErrorIs(t, err, ErrBoardClosed)
+
+	repo.AssertNotCalled(t, "CreateList", mock.Anything)
+	repo.AssertNotCalled(t, "UpdateList", mock.Anything)
+	repo.AssertNotCalled(t, "DeleteList", mock.Anything)
+}
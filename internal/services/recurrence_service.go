@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+	"trello-backend/pkg/rrule"
+)
+
+// recurrenceBatchSize 排程每次最多處理的重複規則數量
+const recurrenceBatchSize = 100
+
+// RecurrenceService 管理週期性卡片的重複規則，並在排程中依規則建立新卡片
+type RecurrenceService interface {
+	SetRecurrence(userID string, cardID, listID uint, rule string, startAt time.Time) (*models.CardRecurrence, error)
+	RemoveRecurrence(userID string, cardID uint) error
+	GetRecurrencesByCardIDs(cardIDs []uint) (map[uint]models.CardRecurrence, error)
+	RunDue(ctx context.Context) (int, error)
+}
+
+type recurrenceService struct {
+	recurrenceRepo repositories.RecurrenceRepository
+	boardRepo      repositories.BoardRepository
+	listRepo       repositories.ListRepository
+	cardRepo       repositories.CardRepository
+	now            func() time.Time
+}
+
+func NewRecurrenceService(
+	recurrenceRepo repositories.RecurrenceRepository,
+	boardRepo repositories.BoardRepository,
+	listRepo repositories.ListRepository,
+	cardRepo repositories.CardRepository,
+) RecurrenceService {
+	return &recurrenceService{
+		recurrenceRepo: recurrenceRepo,
+		boardRepo:      boardRepo,
+		listRepo:       listRepo,
+		cardRepo:       cardRepo,
+		now:            time.Now,
+	}
+}
+
+// SetRecurrence 設定卡片的重複規則，已有規則時取代之。
+// 第一次發生為 startAt 起算、晚於目前時間的第一個時間點
+func (s *recurrenceService) SetRecurrence(userID string, cardID, listID uint, rule string, startAt time.Time) (*models.CardRecurrence, error) {
+	parsed, err := rrule.Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+	card, err := s.cardRepo.GetCardByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return nil, err
+	}
+	list, err := getTargetList(s.listRepo, listID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return nil, err
+	}
+	recurrence := &models.CardRecurrence{
+		CardID:  cardID,
+		ListID:  listID,
+		Rule:    parsed.String(),
+		StartAt: startAt,
+	}
+	if next, ok := parsed.Next(startAt, s.now()); ok {
+		recurrence.NextRunAt = &next
+	}
+	if err := s.recurrenceRepo.SaveRecurrence(recurrence); err != nil {
+		return nil, err
+	}
+	return recurrence, nil
+}
+
+func (s *recurrenceService) RemoveRecurrence(userID string, cardID uint) error {
+	card, err := s.cardRepo.GetCardByID(cardID)
+	if err != nil {
+		return err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return err
+	}
+	return s.recurrenceRepo.DeleteByCardID(cardID)
+}
+
+func (s *recurrenceService) GetRecurrencesByCardIDs(cardIDs []uint) (map[uint]models.CardRecurrence, error) {
+	return s.recurrenceRepo.GetByCardIDs(cardIDs)
+}
+
+// RunDue 為所有已到期的重複規則建立卡片，回傳建立的卡片數量。
+// 停機期間錯過多次發生時只建立最近的一次，避免一次湧入大量重複卡片
+func (s *recurrenceService) RunDue(ctx context.Context) (int, error) {
+	now := s.now()
+	due, err := s.recurrenceRepo.FindDue(now, recurrenceBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	created := 0
+	for i := range due {
+		if ctx.Err() != nil {
+			return created, ctx.Err()
+		}
+		rec := &due[i]
+		rule, err := rrule.Parse(rec.Rule)
+		if err != nil {
+			log.Printf("週期性卡片 %d 的規則無效: %v", rec.CardID, err)
+			continue
+		}
+		occurrence := *rec.NextRunAt
+		occurrences := rule.Iter(rec.StartAt, occurrence)
+		next, ok := occurrences.Next()
+		for ok && !next.After(now) {
+			occurrence = next
+			next, ok = occurrences.Next()
+		}
+		var nextRunAt *time.Time
+		if ok {
+			nextRunAt = &next
+		}
+		card, err := s.recurrenceRepo.CreateOccurrence(rec, occurrence, nextRunAt)
+		var wipErr *WipLimitError
+		if errors.As(err, &wipErr) {
+			// 清單已滿時略過這次發生，不留下發生紀錄，下次依規則照常建立
+			log.Printf("週期性卡片 %d 的清單已達在製品上限，略過這次發生: %v", rec.CardID, err)
+			if err := s.recurrenceRepo.SkipOccurrence(rec, nextRunAt); err != nil {
+				log.Printf("略過週期性卡片 %d 失敗: %v", rec.CardID, err)
+			}
+			continue
+		}
+		if err != nil {
+			log.Printf("建立週期性卡片 %d 失敗: %v", rec.CardID, err)
+			continue
+		}
+		if card != nil {
+			created++
+		}
+	}
+	return created, nil
+}
+
+// RunRecurrenceScheduler 每隔 interval 建立到期的週期性卡片，直到 ctx 結束
+func RunRecurrenceScheduler(ctx context.Context, recurrenceService RecurrenceService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		created, err := recurrenceService.RunDue(ctx)
+		if err != nil {
+			log.Printf("建立週期性卡片失敗: %v", err)
+		} else if created > 0 {
+			log.Printf("已建立 %d 張週期性卡片", created)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"trello-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRecurrenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecurrenceRepository) SaveRecurrence(recurrence *models.CardRecurrence) error {
+	args := m.Called(recurrence)
+	return args.Error(0)
+}
+func (m *MockRecurrenceRepository) GetByCardIDs(cardIDs []uint) (map[uint]models.CardRecurrence, error) {
+	args := m.Called(cardIDs)
+	return args.Get(0).(map[uint]models.CardRecurrence), args.Error(1)
+}
+func (m *MockRecurrenceRepository) DeleteByCardID(cardID uint) error {
+	args := m.Called(cardID)
+	return args.Error(0)
+}
+func (m *MockRecurrenceRepository) FindDue(now time.Time, limit int) ([]models.CardRecurrence, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]models.CardRecurrence), args.Error(1)
+}
+func (m *MockRecurrenceRepository) CreateOccurrence(recurrence *models.CardRecurrence, occurrence time.Time, next *time.Time) (*models.Card, error) {
+	args := m.Called(recurrence, occurrence, next)
+	return args.Get(0).(*models.Card), args.Error(1)
+}
+func (m *MockRecurrenceRepository) SkipOccurrence(recurrence *models.CardRecurrence, next *time.Time) error {
+	args := m.Called(recurrence, next)
+	return args.Error(0)
+}
+
+// 2025-03-05 為週三
+var recurrenceNow = time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC)
+
+func newTestRecurrenceService() (RecurrenceService, *MockRecurrenceRepository, *MockListRepository, *MockCardRepository) {
+	repo := new(MockRecurrenceRepository)
+	boardRepo := new(MockBoardRepository)
+	listRepo := new(MockListRepository)
+	cardRepo := new(MockCardRepository)
+	boardRepo.On("GetBoardByID", uint(1)).Return(&models.Board{ID: 1, UserID: "user-1"}, nil)
+	service := NewRecurrenceService(repo, boardRepo, listRepo, cardRepo).(*recurrenceService)
+	service.now = func() time.Time { return recurrenceNow }
+	return service, repo, listRepo, cardRepo
+}
+
+func TestRecurrenceService_SetRecurrence(t *testing.T) {
+	service, repo, listRepo, cardRepo := newTestRecurrenceService()
+	cardRepo.On("GetCardByID", uint(5)).Return(&models.Card{ID: 5, BoardID: 1, IsTemplate: true}, nil)
+	listRepo.On("GetListByID", uint(2)).Return(&models.List{ID: 2, BoardID: 1}, nil)
+	repo.On("SaveRecurrence", mock.Anything).Return(nil)
+	start := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+	rec, err := service.SetRecurrence("user-1", 5, 2, "freq=weekly;byday=mo", start)
+
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO", rec.Rule)
+	// 下一個週一
+	assert.Equal(t, time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), *rec.NextRunAt)
+}
+
+func TestRecurrenceService_SetRecurrence_InvalidRule(t *testing.T) {
+	service, repo, _, _ := newTestRecurrenceService()
+
+	_, err := service.SetRecurrence("user-1", 5, 2, "FREQ=HOURLY", recurrenceNow)
+
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "SaveRecurrence", mock.Anything)
+}
+
+func TestRecurrenceService_RunDue_CatchesUpToLatest(t *testing.T) {
+	service, repo, _, _ := newTestRecurrenceService()
+	start := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	missed := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+	// 每日規則，停機數天後只補建今天 08:00 的卡片，下一次為明天
+	rec := models.CardRecurrence{ID: 1, CardID: 5, ListID: 2, Rule: "FREQ=DAILY", StartAt: start, NextRunAt: &missed}
+	repo.On("FindDue", recurrenceNow, recurrenceBatchSize).Return([]models.CardRecurrence{rec}, nil)
+	today := time.Date(2025, 3, 5, 8, 0, 0, 0, time.UTC)
+	tomorrow := today.AddDate(0, 0, 1)
+	repo.On("CreateOccurrence", mock.Anything, today, &tomorrow).Return(&models.Card{ID: 9}, nil)
+
+	created, err := service.RunDue(context.Background())
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created)
+}
+
+func TestRecurrenceService_RunDue_WipLimitSkips(t *testing.T) {
+	service, repo, _, _ := newTestRecurrenceService()
+	start := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 3, 5, 8, 0, 0, 0, time.UTC)
+	last := today.AddDate(0, 0, -1)
+	rec := models.CardRecurrence{ID: 1, CardID: 5, ListID: 2, Rule: "FREQ=DAILY", StartAt: start, NextRunAt: &today,
+		LastOccurrenceAt: &last, OccurrenceCount: 3}
+	repo.On("FindDue", recurrenceNow, recurrenceBatchSize).Return([]models.CardRecurrence{rec}, nil)
+	tomorrow := today.AddDate(0, 0, 1)
+	repo.On("CreateOccurrence", mock.Anything, today, &tomorrow).
+		Return((*models.Card)(nil), &WipLimitError{ListID: 2, Limit: 1, Count: 1})
+	// 只推進下次執行時間，發生紀錄與次數維持不變
+	repo.On("SkipOccurrence", mock.MatchedBy(func(r *models.CardRecurrence) bool {
+		return r.ID == 1 && r.OccurrenceCount == 3 && r.LastOccurrenceAt.Equal(last)
+	}), &tomorrow).Return(nil)
+
+	created, err := service.RunDue(context.Background())
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, created)
+}
+
+func TestRecurrenceService_RunDue_LastOccurrence(t *testing.T) {
+	service, repo, _, _ := newTestRecurrenceService()
+	start := time.Date(2025, 3, 4, 8, 0, 0, 0, time.UTC)
+	second := start.AddDate(0, 0, 1)
+	rec := models.CardRecurrence{ID: 1, CardID: 5, Rule: "FREQ=DAILY;COUNT=2", StartAt: start, NextRunAt: &second}
+	repo.On("FindDue", recurrenceNow, recurrenceBatchSize).Return([]models.CardRecurrence{rec}, nil)
+	// 已處理過時 repository 回傳 nil，不計入建立數量
+	repo.On("CreateOccurrence", mock.Anything, second, (*time.Time)(nil)).Return((*models.Card)(nil), nil)
+
+	created, err := service.RunDue(context.Background())
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, created)
+}
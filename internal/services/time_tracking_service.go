@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+)
+
+var (
+	ErrInvalidTimeEntry = errors.New("時間紀錄需提供結束時間或時長其中之一，且時長必須大於零")
+	ErrInvalidTimeRange = errors.New("報表的結束時間必須晚於開始時間")
+)
+
+// TimeEntryDetails 手動新增時間紀錄時的欄位，EndedAt 與 Duration 擇一
+type TimeEntryDetails struct {
+	StartedAt time.Time
+	EndedAt   *time.Time
+	Duration  *time.Duration
+	Note      string
+}
+
+// TimeTrackingService 管理卡片的時間紀錄與計時器，每位使用者同時只有一個進行中的計時器
+type TimeTrackingService interface {
+	AddEntry(userID string, cardID uint, details TimeEntryDetails) (*models.TimeEntry, error)
+	DeleteEntry(userID string, id uint) error
+	StartTimer(userID string, cardID uint, note string) (*models.TimeEntry, error)
+	StopTimer(userID string) (*models.TimeEntry, error)
+	GetRunningTimer(userID string) (*models.TimeEntry, error)
+	GetEntriesByCardIDs(cardIDs []uint) (map[uint][]models.TimeEntry, error)
+	GetTotalsByCardIDs(cardIDs []uint) (map[uint]int64, error)
+	GetBoardReport(userID string, boardID uint, from, to time.Time) ([]repositories.TimeReportRow, error)
+}
+
+type timeTrackingService struct {
+	timeEntryRepo repositories.TimeEntryRepository
+	boardRepo     repositories.BoardRepository
+	cardRepo      repositories.CardRepository
+	now           func() time.Time
+}
+
+func NewTimeTrackingService(
+	timeEntryRepo repositories.TimeEntryRepository,
+	boardRepo repositories.BoardRepository,
+	cardRepo repositories.CardRepository,
+) TimeTrackingService {
+	return &timeTrackingService{
+		timeEntryRepo: timeEntryRepo,
+		boardRepo:     boardRepo,
+		cardRepo:      cardRepo,
+		now:           time.Now,
+	}
+}
+
+func (s *timeTrackingService) AddEntry(userID string, cardID uint, details TimeEntryDetails) (*models.TimeEntry, error) {
+	if err := s.ensureCardAccess(userID, cardID); err != nil {
+		return nil, err
+	}
+	if (details.EndedAt == nil) == (details.Duration == nil) {
+		return nil, ErrInvalidTimeEntry
+	}
+	endedAt := details.EndedAt
+	if details.Duration != nil {
+		end := details.StartedAt.Add(*details.Duration)
+		endedAt = &end
+	}
+	duration := int64(endedAt.Sub(details.StartedAt).Seconds())
+	if duration <= 0 {
+		return nil, ErrInvalidTimeEntry
+	}
+	entry := &models.TimeEntry{
+		CardID:          cardID,
+		UserID:          userID,
+		StartedAt:       details.StartedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: duration,
+		Note:            strings.TrimSpace(details.Note),
+	}
+	if err := s.timeEntryRepo.CreateEntry(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteEntry 使用者只能刪除自己的時間紀錄
+func (s *timeTrackingService) DeleteEntry(userID string, id uint) error {
+	entry, err := s.timeEntryRepo.GetEntryByID(id)
+	if err != nil {
+		return err
+	}
+	if entry.UserID != userID {
+		return ErrForbidden
+	}
+	return s.timeEntryRepo.DeleteEntry(id)
+}
+
+// StartTimer 開始計時，使用者原本進行中的計時器會先被停止
+func (s *timeTrackingService) StartTimer(userID string, cardID uint, note string) (*models.TimeEntry, error) {
+	if err := s.ensureCardAccess(userID, cardID); err != nil {
+		return nil, err
+	}
+	entry := &models.TimeEntry{
+		CardID:    cardID,
+		UserID:    userID,
+		StartedAt: s.now(),
+		Note:      strings.TrimSpace(note),
+	}
+	if _, err := s.timeEntryRepo.StartTimer(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// StopTimer 停止進行中的計時器，沒有時回傳 nil
+func (s *timeTrackingService) StopTimer(userID string) (*models.TimeEntry, error) {
+	return s.timeEntryRepo.StopRunningEntry(userID, s.now())
+}
+
+func (s *timeTrackingService) GetRunningTimer(userID string) (*models.TimeEntry, error) {
+	return s.timeEntryRepo.GetRunningEntry(userID)
+}
+
+func (s *timeTrackingService) GetEntriesByCardIDs(cardIDs []uint) (map[uint][]models.TimeEntry, error) {
+	return s.timeEntryRepo.GetEntriesByCardIDs(cardIDs)
+}
+
+// GetTotalsByCardIDs 各卡片已結束時間紀錄的總秒數，不含進行中的計時器
+func (s *timeTrackingService) GetTotalsByCardIDs(cardIDs []uint) (map[uint]int64, error) {
+	return s.timeEntryRepo.SumDurationsByCardIDs(cardIDs)
+}
+
+// GetBoardReport 依使用者統計看板在 [from, to) 期間的時間紀錄
+func (s *timeTrackingService) GetBoardReport(userID string, boardID uint, from, to time.Time) ([]repositories.TimeReportRow, error) {
+	if _, err := ensureBoardOwner(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, ErrInvalidTimeRange
+	}
+	return s.timeEntryRepo.GetBoardReport(boardID, from, to)
+}
+
+func (s *timeTrackingService) ensureCardAccess(userID string, cardID uint) error {
+	card, err := s.cardRepo.GetCardByID(cardID)
+	if err != nil {
+		return err
+	}
+	_, err = ensureBoardEditable(s.boardRepo, card.BoardID, userID)
+	return err
+}
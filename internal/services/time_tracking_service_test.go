@@ -0,0 +1,134 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTimeEntryRepository struct {
+	mock.Mock
+}
+
+func (m *MockTimeEntryRepository) CreateEntry(entry *models.TimeEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+func (m *MockTimeEntryRepository) GetEntryByID(id uint) (*models.TimeEntry, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+func (m *MockTimeEntryRepository) DeleteEntry(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+func (m *MockTimeEntryRepository) GetEntriesByCardIDs(cardIDs []uint) (map[uint][]models.TimeEntry, error) {
+	args := m.Called(cardIDs)
+	return args.Get(0).(map[uint][]models.TimeEntry), args.Error(1)
+}
+func (m *MockTimeEntryRepository) SumDurationsByCardIDs(cardIDs []uint) (map[uint]int64, error) {
+	args := m.Called(cardIDs)
+	return args.Get(0).(map[uint]int64), args.Error(1)
+}
+func (m *MockTimeEntryRepository) GetRunningEntry(userID string) (*models.TimeEntry, error) {
+	args := m.Called(userID)
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+func (m *MockTimeEntryRepository) StartTimer(entry *models.TimeEntry) (*models.TimeEntry, error) {
+	args := m.Called(entry)
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+func (m *MockTimeEntryRepository) StopRunningEntry(userID string, at time.Time) (*models.TimeEntry, error) {
+	args := m.Called(userID, at)
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+func (m *MockTimeEntryRepository) GetBoardReport(boardID uint, from, to time.Time) ([]repositories.TimeReportRow, error) {
+	args := m.Called(boardID, from, to)
+	return args.Get(0).([]repositories.TimeReportRow), args.Error(1)
+}
+
+var timeTrackingNow = time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+func newTestTimeTrackingService() (TimeTrackingService, *MockTimeEntryRepository) {
+	repo := new(MockTimeEntryRepository)
+	boardRepo := new(MockBoardRepository)
+	cardRepo := new(MockCardRepository)
+	boardRepo.On("GetBoardByID", uint(1)).Return(&models.Board{ID: 1, UserID: "user-1"}, nil)
+	cardRepo.On("GetCardByID", uint(5)).Return(&models.Card{ID: 5, BoardID: 1}, nil)
+	service := NewTimeTrackingService(repo, boardRepo, cardRepo).(*timeTrackingService)
+	service.now = func() time.Time { return timeTrackingNow }
+	return service, repo
+}
+
+func TestTimeTrackingService_AddEntry_Duration(t *testing.T) {
+	service, repo := newTestTimeTrackingService()
+	repo.On("CreateEntry", mock.Anything).Return(nil)
+	start := timeTrackingNow.Add(-2 * time.Hour)
+	duration := 90 * time.Minute
+
+	entry, err := service.AddEntry("user-1", 5, TimeEntryDetails{StartedAt: start, Duration: &duration, Note: " 需求訪談 "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5400), entry.DurationSeconds)
+	assert.Equal(t, start.Add(duration), *entry.EndedAt)
+	assert.Equal(t, "需求訪談", entry.Note)
+}
+
+func TestTimeTrackingService_AddEntry_Invalid(t *testing.T) {
+	service, repo := newTestTimeTrackingService()
+	start := timeTrackingNow
+	end := start.Add(-time.Minute)
+	duration := time.Hour
+
+	_, err := service.AddEntry("user-1", 5, TimeEntryDetails{StartedAt: start})
+	assert.ErrorIs(t, err, ErrInvalidTimeEntry)
+	_, err = service.AddEntry("user-1", 5, TimeEntryDetails{StartedAt: start, EndedAt: &end, Duration: &duration})
+	assert.ErrorIs(t, err, ErrInvalidTimeEntry)
+	_, err = service.AddEntry("user-1", 5, TimeEntryDetails{StartedAt: start, EndedAt: &end})
+	assert.ErrorIs(t, err, ErrInvalidTimeEntry)
+	_, err = service.AddEntry("user-2", 5, TimeEntryDetails{StartedAt: start, Duration: &duration})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	repo.AssertNotCalled(t, "CreateEntry", mock.Anything)
+}
+
+func TestTimeTrackingService_StartTimer(t *testing.T) {
+	service, repo := newTestTimeTrackingService()
+	repo.On("StartTimer", mock.MatchedBy(func(e *models.TimeEntry) bool {
+		return e.CardID == 5 && e.UserID == "user-1" && e.StartedAt.Equal(timeTrackingNow) && e.EndedAt == nil
+	})).Return((*models.TimeEntry)(nil), nil)
+
+	entry, err := service.StartTimer("user-1", 5, "")
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), entry.CardID)
+}
+
+func TestTimeTrackingService_DeleteEntry_OthersEntry(t *testing.T) {
+	service, repo := newTestTimeTrackingService()
+	repo.On("GetEntryByID", uint(3)).Return(&models.TimeEntry{ID: 3, UserID: "user-2"}, nil)
+
+	err := service.DeleteEntry("user-1", 3)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "DeleteEntry", mock.Anything)
+}
+
+func TestTimeTrackingService_GetBoardReport(t *testing.T) {
+	service, repo := newTestTimeTrackingService()
+	from := timeTrackingNow.AddDate(0, -1, 0)
+	rows := []repositories.TimeReportRow{{UserID: "user-1", UserName: "王小明", TotalSeconds: 7200, EntryCount: 3}}
+	repo.On("GetBoardReport", uint(1), from, timeTrackingNow).Return(rows, nil)
+
+	report, err := service.GetBoardReport("user-1", 1, from, timeTrackingNow)
+	assert.NoError(t, err)
+	assert.Equal(t, rows, report)
+
+	_, err = service.GetBoardReport("user-1", 1, timeTrackingNow, from)
+	assert.ErrorIs(t, err, ErrInvalidTimeRange)
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrParentInTrash 所屬的看板或清單仍在垃圾桶中，無法單獨還原
+var ErrParentInTrash = errors.New("所屬的看板或清單仍在垃圾桶中，請先還原")
+
+// TrashItemType 垃圾桶中項目的種類
+type TrashItemType string
+
+const (
+	TrashItemBoard TrashItemType = "board"
+	TrashItemList  TrashItemType = "list"
+	TrashItemCard  TrashItemType = "card"
+)
+
+type TrashService interface {
+	GetTrashedBoards(userID string) ([]models.Board, error)
+	GetTrashedLists(userID string, boardID uint) ([]models.List, error)
+	GetTrashedCards(userID string, boardID uint) ([]models.Card, error)
+	Restore(userID string, itemType TrashItemType, id uint) error
+	Purge(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type trashService struct {
+	trashRepo         repositories.TrashRepository
+	boardRepo         repositories.BoardRepository
+	listRepo          repositories.ListRepository
+	cardRepo          repositories.CardRepository
+	attachmentService AttachmentService
+	now               func() time.Time
+}
+
+func NewTrashService(
+	trashRepo repositories.TrashRepository,
+	boardRepo repositories.BoardRepository,
+	listRepo repositories.ListRepository,
+	cardRepo repositories.CardRepository,
+	attachmentService AttachmentService,
+) TrashService {
+	return &trashService{
+		trashRepo:         trashRepo,
+		boardRepo:         boardRepo,
+		listRepo:          listRepo,
+		cardRepo:          cardRepo,
+		attachmentService: attachmentService,
+		now:               time.Now,
+	}
+}
+
+func (s *trashService) GetTrashedBoards(userID string) ([]models.Board, error) {
+	return s.trashRepo.FindDeletedBoards(userID)
+}
+
+func (s *trashService) GetTrashedLists(userID string, boardID uint) ([]models.List, error) {
+	if _, err := ensureBoardOwner(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	return s.trashRepo.FindDeletedLists(boardID)
+}
+
+func (s *trashService) GetTrashedCards(userID string, boardID uint) ([]models.Card, error) {
+	if _, err := ensureBoardOwner(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	return s.trashRepo.FindDeletedCards(boardID)
+}
+
+// Restore 將項目移出垃圾桶，還原後放在所屬集合的最後
+func (s *trashService) Restore(userID string, itemType TrashItemType, id uint) error {
+	switch itemType {
+	case TrashItemBoard:
+		return s.restoreBoard(userID, id)
+	case TrashItemList:
+		return s.restoreList(userID, id)
+	case TrashItemCard:
+		return s.restoreCard(userID, id)
+	default:
+		return errors.New("不支援的垃圾桶項目類型")
+	}
+}
+
+func (s *trashService) restoreBoard(userID string, id uint) error {
+	board, err := s.trashRepo.GetDeletedBoard(id)
+	if err != nil {
+		return err
+	}
+	if board.UserID != userID {
+		return ErrForbidden
+	}
+	var boards []models.Board
+	if err := s.boardRepo.FindBoardsByUserID(userID, &boards); err != nil {
+		return err
+	}
+	board.Position = len(boards)
+	return s.trashRepo.RestoreBoard(board)
+}
+
+func (s *trashService) restoreList(userID string, id uint) error {
+	list, err := s.trashRepo.GetDeletedList(id)
+	if err != nil {
+		return err
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, list.BoardID, userID); err != nil {
+		return parentInTrash(err)
+	}
+	lists, err := s.listRepo.GetListsByBoardID(list.BoardID)
+	if err != nil {
+		return err
+	}
+	list.Position = len(lists)
+	return s.trashRepo.RestoreList(list)
+}
+
+func (s *trashService) restoreCard(userID string, id uint) error {
+	card, err := s.trashRepo.GetDeletedCard(id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.listRepo.GetListByID(card.ListID); err != nil {
+		return parentInTrash(err)
+	}
+	if _, err := ensureBoardEditable(s.boardRepo, card.BoardID, userID); err != nil {
+		return parentInTrash(err)
+	}
+	cards, err := s.cardRepo.GetCardsByListID(card.ListID)
+	if err != nil {
+		return err
+	}
+	card.Position = len(cards)
+	return s.trashRepo.RestoreCard(card)
+}
+
+// parentInTrash 上層項目查無資料時，代表它仍在垃圾桶中
+func parentInTrash(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrParentInTrash
+	}
+	return err
+}
+
+// Purge 永久刪除在垃圾桶中超過保留期限的項目，並清除其附件檔案
+func (s *trashService) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	cutoff := s.now().Add(-retention)
+	cardIDs, err := s.trashRepo.FindCardIDsDeletedBefore(cutoff)
+	if err != nil {
+		return 0, err
+	}
+	if len(cardIDs) > 0 {
+		if err := s.attachmentService.DeleteCardAttachments(ctx, cardIDs); err != nil {
+			return 0, err
+		}
+	}
+	return s.trashRepo.PurgeDeletedBefore(cutoff)
+}
+
+// RunTrashPurger 每隔 interval 清理一次垃圾桶，直到 ctx 結束
+func RunTrashPurger(ctx context.Context, trashService TrashService, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		purged, err := trashService.Purge(ctx, retention)
+		if err != nil {
+			log.Printf("清理垃圾桶失敗: %v", err)
+		} else if purged > 0 {
+			log.Printf("已永久刪除垃圾桶中 %d 筆項目", purged)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"trello-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockTrashRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrashRepository) FindDeletedBoards(userID string) ([]models.Board, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.Board), args.Error(1)
+}
+func (m *MockTrashRepository) FindDeletedLists(boardID uint) ([]models.List, error) {
+	args := m.Called(boardID)
+	return args.Get(0).([]models.List), args.Error(1)
+}
+func (m *MockTrashRepository) FindDeletedCards(boardID uint) ([]models.Card, error) {
+	args := m.Called(boardID)
+	return args.Get(0).([]models.Card), args.Error(1)
+}
+func (m *MockTrashRepository) GetDeletedBoard(id uint) (*models.Board, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Board), args.Error(1)
+}
+func (m *MockTrashRepository) GetDeletedList(id uint) (*models.List, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.List), args.Error(1)
+}
+func (m *MockTrashRepository) GetDeletedCard(id uint) (*models.Card, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Card), args.Error(1)
+}
+func (m *MockTrashRepository) RestoreBoard(board *models.Board) error {
+	args := m.Called(board)
+	return args.Error(0)
+}
+func (m *MockTrashRepository) RestoreList(list *models.List) error {
+	args := m.Called(list)
+	return args.Error(0)
+}
+func (m *MockTrashRepository) RestoreCard(card *models.Card) error {
+	args := m.Called(card)
+	return args.Error(0)
+}
+func (m *MockTrashRepository) FindCardIDsDeletedBefore(cutoff time.Time) ([]uint, error) {
+	args := m.Called(cutoff)
+	return args.Get(0).([]uint), args.Error(1)
+}
+func (m *MockTrashRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	args := m.Called(cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newTestTrashService() (*trashService, *MockTrashRepository, *MockBoardRepository, *MockListRepository, *MockCardRepository) {
+	trashRepo := new(MockTrashRepository)
+	boardRepo := new(MockBoardRepository)
+	listRepo := new(MockListRepository)
+	cardRepo := new(MockCardRepository)
+	service := NewTrashService(trashRepo, boardRepo, listRepo, cardRepo, nil).(*trashService)
+	return service, trashRepo, boardRepo, listRepo, cardRepo
+}
+
+func TestTrashService_RestoreCard(t *testing.T) {
+	service, trashRepo, boardRepo, listRepo, cardRepo := newTestTrashService()
+	card := &models.Card{ID: 1, ListID: 2, BoardID: 3, Position: 0}
+	trashRepo.On("GetDeletedCard", uint(1)).Return(card, nil)
+	listRepo.On("GetListByID", uint(2)).Return(&models.List{ID: 2, BoardID: 3}, nil)
+	boardRepo.On("GetBoardByID", uint(3)).Return(&models.Board{ID: 3, UserID: "user-1"}, nil)
+	cardRepo.On("GetCardsByListID", uint(2)).Return([]models.Card{{ID: 4}, {ID: 5}}, nil)
+	trashRepo.On("RestoreCard", card).Return(nil)
+
+	err := service.Restore("user-1", TrashItemCard, 1)
+
+	trashRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+	// 還原後放在清單最後
+	assert.Equal(t, 2, card.Position)
+}
+
+func TestTrashService_RestoreList_BoardInTrash(t *testing.T) {
+	service, trashRepo, boardRepo, _, _ := newTestTrashService()
+	trashRepo.On("GetDeletedList", uint(1)).Return(&models.List{ID: 1, BoardID: 3}, nil)
+	boardRepo.On("GetBoardByID", uint(3)).Return((*models.Board)(nil), gorm.ErrRecordNotFound)
+
+	err := service.Restore("user-1", TrashItemList, 1)
+
+	assert.ErrorIs(t, err, ErrParentInTrash)
+	trashRepo.AssertNotCalled(t, "RestoreList", mock.Anything)
+}
+
+func TestTrashService_RestoreBoard_Forbidden(t *testing.T) {
+	service, trashRepo, _, _, _ := newTestTrashService()
+	trashRepo.On("GetDeletedBoard", uint(1)).Return(&models.Board{ID: 1, UserID: "user-2"}, nil)
+
+	err := service.Restore("user-1", TrashItemBoard, 1)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	trashRepo.AssertNotCalled(t, "RestoreBoard", mock.Anything)
+}
+
+func TestTrashService_Purge(t *testing.T) {
+	attachmentService, attachmentRepo, _, _, _ := newTestAttachmentService(t, 0)
+	service, trashRepo, _, _, _ := newTestTrashService()
+	service.attachmentService = attachmentService
+	now := time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	cutoff := now.Add(-30 * 24 * time.Hour)
+	trashRepo.On("FindCardIDsDeletedBefore", cutoff).Return([]uint{7, 8}, nil)
+	attachmentRepo.On("GetAttachmentsByCardIDs", []uint{7, 8}).Return(map[uint][]models.Attachment{}, nil)
+	attachmentRepo.On("DeleteAttachmentsByCardIDs", []uint{7, 8}).Return(nil)
+	trashRepo.On("PurgeDeletedBefore", cutoff).Return(int64(3), nil)
+
+	purged, err := service.Purge(context.Background(), 30*24*time.Hour)
+
+	trashRepo.AssertExpectations(t)
+	attachmentRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrBoardNotStarred 只能調整已加上星號的看板順序
+var ErrBoardNotStarred = errors.New("看板沒有加上星號")
+
+// recentBoardLimit 每位使用者保留的最近瀏覽看板數量
+const recentBoardLimit = 20
+
+// UserBoardService 管理使用者個人的星號看板與最近瀏覽的看板
+type UserBoardService interface {
+	StarBoard(userID string, boardID uint) (*models.Board, error)
+	UnstarBoard(userID string, boardID uint) (*models.Board, error)
+	MoveStarredBoard(userID string, boardID uint, position int) ([]models.Board, error)
+	GetStarredBoards(userID string) ([]models.Board, error)
+	GetStarred(userID string, boardIDs []uint) (map[uint]bool, error)
+	RecordView(userID string, boardID uint) error
+	GetRecentBoards(userID string) ([]models.Board, error)
+}
+
+type userBoardService struct {
+	userBoardRepo repositories.UserBoardRepository
+	boardRepo     repositories.BoardRepository
+	now           func() time.Time
+}
+
+func NewUserBoardService(userBoardRepo repositories.UserBoardRepository, boardRepo repositories.BoardRepository) UserBoardService {
+	return &userBoardService{userBoardRepo: userBoardRepo, boardRepo: boardRepo, now: time.Now}
+}
+
+// StarBoard 將看板加上星號，新的星號看板放在最後
+func (s *userBoardService) StarBoard(userID string, boardID uint) (*models.Board, error) {
+	board, err := ensureBoardOwner(s.boardRepo, boardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userBoardRepo.StarBoard(userID, boardID); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+func (s *userBoardService) UnstarBoard(userID string, boardID uint) (*models.Board, error) {
+	board, err := ensureBoardOwner(s.boardRepo, boardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userBoardRepo.UnstarBoard(userID, boardID); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// MoveStarredBoard 調整星號看板的順序，回傳調整後的星號看板
+func (s *userBoardService) MoveStarredBoard(userID string, boardID uint, position int) ([]models.Board, error) {
+	err := s.userBoardRepo.MoveStar(userID, boardID, position)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBoardNotStarred
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.userBoardRepo.GetStarredBoards(userID)
+}
+
+func (s *userBoardService) GetStarredBoards(userID string) ([]models.Board, error) {
+	return s.userBoardRepo.GetStarredBoards(userID)
+}
+
+// GetStarred 回傳使用者是否對各看板加上星號
+func (s *userBoardService) GetStarred(userID string, boardIDs []uint) (map[uint]bool, error) {
+	result := make(map[uint]bool)
+	if userID == "" {
+		return result, nil
+	}
+	ids, err := s.userBoardRepo.FindStarredBoardIDs(userID, boardIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// RecordView 記錄使用者瀏覽了自己的看板，其他人的看板不記錄
+func (s *userBoardService) RecordView(userID string, boardID uint) error {
+	board, err := s.boardRepo.GetBoardByID(boardID)
+	if err != nil {
+		return err
+	}
+	if board.UserID != userID {
+		return nil
+	}
+	return s.userBoardRepo.RecordView(userID, boardID, s.now(), recentBoardLimit)
+}
+
+func (s *userBoardService) GetRecentBoards(userID string) ([]models.Board, error) {
+	return s.userBoardRepo.GetRecentBoards(userID, recentBoardLimit)
+}
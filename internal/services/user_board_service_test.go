@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"trello-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockUserBoardRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserBoardRepository) StarBoard(userID string, boardID uint) error {
+	args := m.Called(userID, boardID)
+	return args.Error(0)
+}
+func (m *MockUserBoardRepository) UnstarBoard(userID string, boardID uint) error {
+	args := m.Called(userID, boardID)
+	return args.Error(0)
+}
+func (m *MockUserBoardRepository) MoveStar(userID string, boardID uint, position int) error {
+	args := m.Called(userID, boardID, position)
+	return args.Error(0)
+}
+func (m *MockUserBoardRepository) GetStarredBoards(userID string) ([]models.Board, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.Board), args.Error(1)
+}
+func (m *MockUserBoardRepository) FindStarredBoardIDs(userID string, boardIDs []uint) ([]uint, error) {
+	args := m.Called(userID, boardIDs)
+	return args.Get(0).([]uint), args.Error(1)
+}
+func (m *MockUserBoardRepository) RecordView(userID string, boardID uint, at time.Time, keep int) error {
+	args := m.Called(userID, boardID, at, keep)
+	return args.Error(0)
+}
+func (m *MockUserBoardRepository) GetRecentBoards(userID string, limit int) ([]models.Board, error) {
+	args := m.Called(userID, limit)
+	return args.Get(0).([]models.Board), args.Error(1)
+}
+
+var userBoardNow = time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+
+func newTestUserBoardService() (UserBoardService, *MockUserBoardRepository) {
+	repo := new(MockUserBoardRepository)
+	boardRepo := new(MockBoardRepository)
+	boardRepo.On("GetBoardByID", uint(1)).Return(&models.Board{ID: 1, UserID: "user-1"}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-2"}, nil)
+	service := &userBoardService{userBoardRepo: repo, boardRepo: boardRepo, now: func() time.Time { return userBoardNow }}
+	return service, repo
+}
+
+func TestUserBoardService_StarBoard(t *testing.T) {
+	service, repo := newTestUserBoardService()
+	repo.On("StarBoard", "user-1", uint(1)).Return(nil)
+
+	board, err := service.StarBoard("user-1", 1)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), board.ID)
+
+	_, err = service.StarBoard("user-1", 2)
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNumberOfCalls(t, "StarBoard", 1)
+}
+
+func TestUserBoardService_MoveStarredBoard(t *testing.T) {
+	service, repo := newTestUserBoardService()
+	repo.On("MoveStar", "user-1", uint(1), 0).Return(nil)
+	repo.On("MoveStar", "user-1", uint(3), 0).Return(gorm.ErrRecordNotFound)
+	repo.On("GetStarredBoards", "user-1").Return([]models.Board{{ID: 1}, {ID: 4}}, nil)
+
+	boards, err := service.MoveStarredBoard("user-1", 1, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, boards, 2)
+
+	_, err = service.MoveStarredBoard("user-1", 3, 0)
+	assert.ErrorIs(t, err, ErrBoardNotStarred)
+}
+
+func TestUserBoardService_RecordView(t *testing.T) {
+	service, repo := newTestUserBoardService()
+	repo.On("RecordView", "user-1", uint(1), userBoardNow, recentBoardLimit).Return(nil)
+
+	assert.NoError(t, service.RecordView("user-1", 1))
+	// 瀏覽他人的看板不留下紀錄
+	assert.NoError(t, service.RecordView("user-1", 2))
+
+	repo.AssertExpectations(t)
+	repo.AssertNumberOfCalls(t, "RecordView", 1)
+}
+
+func TestUserBoardService_GetStarred(t *testing.T) {
+	service, repo := newTestUserBoardService()
+	repo.On("FindStarredBoardIDs", "user-1", []uint{1, 4}).Return([]uint{4}, nil)
+
+	starred, err := service.GetStarred("user-1", []uint{1, 4})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint]bool{4: true}, starred)
+}
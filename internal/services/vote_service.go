@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+)
+
+// ErrVotingDisabled 看板設定停用了投票
+var ErrVotingDisabled = errors.New("此看板已停用投票")
+
+// defaultVoteRankingLimit 未指定數量時，票數排行最多回傳的卡片數
+const defaultVoteRankingLimit = 50
+
+// VoteService 管理成員對卡片的投票，用於整理待辦清單時排出優先順序
+type VoteService interface {
+	ToggleVote(userID string, cardID uint) (*models.Card, error)
+	GetVoteCounts(cardIDs []uint) (map[uint]int, error)
+	GetVoted(userID string, cardIDs []uint) (map[uint]bool, error)
+	RankCardsByVotes(userID string, boardID uint, limit int) ([]models.Card, error)
+}
+
+type voteService struct {
+	voteRepo  repositories.VoteRepository
+	boardRepo repositories.BoardRepository
+	cardRepo  repositories.CardRepository
+}
+
+func NewVoteService(
+	voteRepo repositories.VoteRepository,
+	boardRepo repositories.BoardRepository,
+	cardRepo repositories.CardRepository,
+) VoteService {
+	return &voteService{voteRepo: voteRepo, boardRepo: boardRepo, cardRepo: cardRepo}
+}
+
+// ToggleVote 對卡片投票，已投過票時取消，回傳卡片。能閱讀看板的使用者都可以投票，
+// 公開看板的讀者也不例外；看板已關閉或停用投票時兩者皆不允許
+func (s *voteService) ToggleVote(userID string, cardID uint) (*models.Card, error) {
+	card, err := s.cardRepo.GetCardByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+	board, err := ensureBoardReadable(s.boardRepo, card.BoardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if board.ClosedAt != nil {
+		return nil, ErrBoardClosed
+	}
+	if board.Settings.VotingDisabled {
+		return nil, ErrVotingDisabled
+	}
+	if _, err := s.voteRepo.ToggleVote(userID, cardID); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func (s *voteService) GetVoteCounts(cardIDs []uint) (map[uint]int, error) {
+	return s.voteRepo.CountByCardIDs(cardIDs)
+}
+
+// GetVoted 回傳使用者是否對各卡片投過票
+func (s *voteService) GetVoted(userID string, cardIDs []uint) (map[uint]bool, error) {
+	result := make(map[uint]bool)
+	if userID == "" {
+		return result, nil
+	}
+	ids, err := s.voteRepo.FindVotedCardIDs(userID, cardIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// RankCardsByVotes 依票數排列看板中有票的卡片，limit 小於 1 時使用預設數量
+func (s *voteService) RankCardsByVotes(userID string, boardID uint, limit int) ([]models.Card, error) {
+	if _, err := ensureBoardReadable(s.boardRepo, boardID, userID); err != nil {
+		return nil, err
+	}
+	if limit < 1 {
+		limit = defaultVoteRankingLimit
+	}
+	return s.voteRepo.RankCardsByVotes(boardID, limit)
+}
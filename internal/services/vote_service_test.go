@@ -0,0 +1,128 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"trello-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockVoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockVoteRepository) ToggleVote(userID string, cardID uint) (bool, error) {
+	args := m.Called(userID, cardID)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockVoteRepository) CountByCardIDs(cardIDs []uint) (map[uint]int, error) {
+	args := m.Called(cardIDs)
+	return args.Get(0).(map[uint]int), args.Error(1)
+}
+func (m *MockVoteRepository) FindVotedCardIDs(userID string, cardIDs []uint) ([]uint, error) {
+	args := m.Called(userID, cardIDs)
+	return args.Get(0).([]uint), args.Error(1)
+}
+func (m *MockVoteRepository) RankCardsByVotes(boardID uint, limit int) ([]models.Card, error) {
+	args := m.Called(boardID, limit)
+	return args.Get(0).([]models.Card), args.Error(1)
+}
+
+func newTestVoteService() (VoteService, *MockVoteRepository, *MockCardRepository) {
+	repo := new(MockVoteRepository)
+	boardRepo := new(MockBoardRepository)
+	cardRepo := new(MockCardRepository)
+	boardRepo.On("GetBoardByID", uint(1)).Return(&models.Board{ID: 1, UserID: "user-1"}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-2"}, nil)
+	boardRepo.On("GetBoardByID", uint(3)).Return(&models.Board{
+		ID: 3, UserID: "user-2", Settings: models.BoardSettings{Visibility: models.BoardPublic},
+	}, nil)
+	closedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	boardRepo.On("GetBoardByID", uint(4)).Return(&models.Board{ID: 4, UserID: "user-1", ClosedAt: &closedAt}, nil)
+	return NewVoteService(repo, boardRepo, cardRepo), repo, cardRepo
+}
+
+func TestVoteService_ToggleVote(t *testing.T) {
+	service, repo, cardRepo := newTestVoteService()
+	cardRepo.On("GetCardByID", uint(7)).Return(&models.Card{ID: 7, BoardID: 1}, nil)
+	repo.On("ToggleVote", "user-1", uint(7)).Return(true, nil)
+
+	card, err := service.ToggleVote("user-1", 7)
+
+	repo.AssertExpectations(t)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), card.ID)
+}
+
+func TestVoteService_ToggleVote_ForeignBoard(t *testing.T) {
+	service, repo, cardRepo := newTestVoteService()
+	cardRepo.On("GetCardByID", uint(8)).Return(&models.Card{ID: 8, BoardID: 2}, nil)
+
+	_, err := service.ToggleVote("user-1", 8)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	repo.AssertNotCalled(t, "ToggleVote", mock.Anything, mock.Anything)
+}
+
+func TestVoteService_ToggleVote_PublicBoard(t *testing.T) {
+	service, repo, cardRepo := newTestVoteService()
+	cardRepo.On("GetCardByID", uint(9)).Return(&models.Card{ID: 9, BoardID: 3}, nil)
+	cardRepo.On("GetCardByID", uint(10)).Return(&models.Card{ID: 10, BoardID: 4}, nil)
+	repo.On("ToggleVote", "user-1", uint(9)).Return(true, nil)
+
+	// 公開看板的讀者可以投票
+	_, err := service.ToggleVote("user-1", 9)
+	assert.NoError(t, err)
+
+	// 已關閉的看板不接受投票
+	_, err = service.ToggleVote("user-1", 10)
+	assert.ErrorIs(t, err, ErrBoardClosed)
+	repo.AssertNumberOfCalls(t, "ToggleVote", 1)
+}
+
+func TestVoteService_ToggleVote_Disabled(t *testing.T) {
+	repo := new(MockVoteRepository)
+	boardRepo := new(MockBoardRepository)
+	cardRepo := new(MockCardRepository)
+	boardRepo.On("GetBoardByID", uint(1)).Return(&models.Board{
+		ID: 1, UserID: "user-1", Settings: models.BoardSettings{VotingDisabled: true},
+	}, nil)
+	cardRepo.On("GetCardByID", uint(7)).Return(&models.Card{ID: 7, BoardID: 1}, nil)
+	service := NewVoteService(repo, boardRepo, cardRepo)
+
+	_, err := service.ToggleVote("user-1", 7)
+
+	assert.ErrorIs(t, err, ErrVotingDisabled)
+	repo.AssertNotCalled(t, "ToggleVote", mock.Anything, mock.Anything)
+}
+
+func TestVoteService_GetVoted(t *testing.T) {
+	service, repo, _ := newTestVoteService()
+	repo.On("FindVotedCardIDs", "user-1", []uint{1, 2, 3}).Return([]uint{2}, nil)
+
+	voted, err := service.GetVoted("user-1", []uint{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint]bool{2: true}, voted)
+
+	// 未登入時不查詢資料庫
+	voted, err = service.GetVoted("", []uint{1})
+	assert.NoError(t, err)
+	assert.Empty(t, voted)
+	repo.AssertNumberOfCalls(t, "FindVotedCardIDs", 1)
+}
+
+func TestVoteService_RankCardsByVotes_DefaultLimit(t *testing.T) {
+	service, repo, _ := newTestVoteService()
+	repo.On("RankCardsByVotes", uint(1), defaultVoteRankingLimit).Return([]models.Card{{ID: 3}, {ID: 1}}, nil)
+
+	cards, err := service.RankCardsByVotes("user-1", 1, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, cards, 2)
+
+	_, err = service.RankCardsByVotes("user-1", 2, 10)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
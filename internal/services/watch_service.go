@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+)
+
+// ErrInvalidWatchTarget 不支援關注的項目種類
+var ErrInvalidWatchTarget = errors.New("無效的關注項目")
+
+// notificationLimit 一次最多取得的通知數量
+const notificationLimit = 100
+
+// WatchService 管理使用者對看板、清單與卡片的關注，並在項目變動時通知關注者。
+// 看板擁有者與公開看板的其他使用者可以關注；卡片的關注者包含關注卡片本身、所在清單與所在看板的使用者，
+// 觸發變動者與已無法讀取看板的關注者不會收到通知
+type WatchService interface {
+	Watch(userID string, entityType models.WatchEntityType, entityID uint) error
+	Unwatch(userID string, entityType models.WatchEntityType, entityID uint) error
+	GetWatched(userID string, targets []repositories.WatchTarget) (map[repositories.WatchTarget]bool, error)
+	NotifyCardMoved(actorID string, card *models.Card, fromListID uint) error
+	NotifyDueDateChanged(actorID string, card *models.Card, oldDueAt *time.Time) error
+	GetNotifications(userID string, unreadOnly bool) ([]models.Notification, error)
+	MarkNotificationRead(userID string, id uint) error
+	MarkAllNotificationsRead(userID string) error
+}
+
+type watchService struct {
+	watchRepo        repositories.WatchRepository
+	notificationRepo repositories.NotificationRepository
+	boardRepo        repositories.BoardRepository
+	listRepo         repositories.ListRepository
+	cardRepo         repositories.CardRepository
+	now              func() time.Time
+}
+
+func NewWatchService(
+	watchRepo repositories.WatchRepository,
+	notificationRepo repositories.NotificationRepository,
+	boardRepo repositories.BoardRepository,
+	listRepo repositories.ListRepository,
+	cardRepo repositories.CardRepository,
+) WatchService {
+	return &watchService{
+		watchRepo:        watchRepo,
+		notificationRepo: notificationRepo,
+		boardRepo:        boardRepo,
+		listRepo:         listRepo,
+		cardRepo:         cardRepo,
+		now:              time.Now,
+	}
+}
+
+func (s *watchService) Watch(userID string, entityType models.WatchEntityType, entityID uint) error {
+	if err := s.ensureAccess(userID, entityType, entityID); err != nil {
+		return err
+	}
+	return s.watchRepo.CreateWatch(&models.Watch{UserID: userID, EntityType: entityType, EntityID: entityID})
+}
+
+// Unwatch 取消關注；只會刪除使用者自己的關注，看板改為私人後仍可取消
+func (s *watchService) Unwatch(userID string, entityType models.WatchEntityType, entityID uint) error {
+	if !validWatchTarget(entityType) {
+		return ErrInvalidWatchTarget
+	}
+	return s.watchRepo.DeleteWatch(userID, entityType, entityID)
+}
+
+func validWatchTarget(entityType models.WatchEntityType) bool {
+	return entityType == models.WatchBoard || entityType == models.WatchList || entityType == models.WatchCard
+}
+
+// ensureAccess 確認使用者可以讀取項目所在的看板
+func (s *watchService) ensureAccess(userID string, entityType models.WatchEntityType, entityID uint) error {
+	var boardID uint
+	switch entityType {
+	case models.WatchBoard:
+		boardID = entityID
+	case models.WatchList:
+		list, err := s.listRepo.GetListByID(entityID)
+		if err != nil {
+			return err
+		}
+		boardID = list.BoardID
+	case models.WatchCard:
+		card, err := s.cardRepo.GetCardByID(entityID)
+		if err != nil {
+			return err
+		}
+		boardID = card.BoardID
+	default:
+		return ErrInvalidWatchTarget
+	}
+	_, err := ensureBoardReadable(s.boardRepo, boardID, userID)
+	return err
+}
+
+// GetWatched 批次查詢使用者是否關注各項目
+func (s *watchService) GetWatched(userID string, targets []repositories.WatchTarget) (map[repositories.WatchTarget]bool, error) {
+	watched, err := s.watchRepo.FindWatchedTargets(userID, targets)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[repositories.WatchTarget]bool, len(watched))
+	for _, t := range watched {
+		result[t] = true
+	}
+	return result, nil
+}
+
+// NotifyCardMoved 卡片移動到其他清單時通知關注者，原清單的關注者也會收到
+func (s *watchService) NotifyCardMoved(actorID string, card *models.Card, fromListID uint) error {
+	if card.ListID == fromListID {
+		return nil
+	}
+	from, err := s.listRepo.GetListByID(fromListID)
+	if err != nil {
+		return err
+	}
+	to, err := s.listRepo.GetListByID(card.ListID)
+	if err != nil {
+		return err
+	}
+	message := fmt.Sprintf("卡片「%s」已從「%s」移至「%s」", card.Title, from.Name, to.Name)
+	extra := repositories.WatchTarget{Type: models.WatchList, ID: fromListID}
+	return s.notify(actorID, card, models.NotificationCardMoved, message, extra)
+}
+
+// NotifyDueDateChanged 卡片到期日變更時通知關注者
+func (s *watchService) NotifyDueDateChanged(actorID string, card *models.Card, oldDueAt *time.Time) error {
+	if sameTime(oldDueAt, card.DueAt) {
+		return nil
+	}
+	message := fmt.Sprintf("卡片「%s」的到期日已移除", card.Title)
+	if card.DueAt != nil {
+		message = fmt.Sprintf("卡片「%s」的到期日已變更為 %s", card.Title, card.DueAt.Format("2006-01-02 15:04"))
+	}
+	return s.notify(actorID, card, models.NotificationDueDateChanged, message)
+}
+
+func (s *watchService) notify(actorID string, card *models.Card, notificationType models.NotificationType, message string, extra ...repositories.WatchTarget) error {
+	targets := append([]repositories.WatchTarget{
+		{Type: models.WatchCard, ID: card.ID},
+		{Type: models.WatchList, ID: card.ListID},
+		{Type: models.WatchBoard, ID: card.BoardID},
+	}, extra...)
+	watcherIDs, err := s.watchRepo.FindWatcherIDs(targets)
+	if err != nil {
+		return err
+	}
+	board, err := s.boardRepo.GetBoardByID(card.BoardID)
+	if err != nil {
+		return err
+	}
+	notifications := make([]models.Notification, 0, len(watcherIDs))
+	for _, userID := range watcherIDs {
+		// 看板改為私人後，其他使用者先前的關注不再收到通知
+		if userID == actorID || (userID != board.UserID && board.Settings.Visibility != models.BoardPublic) {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserID:  userID,
+			ActorID: actorID,
+			Type:    notificationType,
+			BoardID: card.BoardID,
+			CardID:  card.ID,
+			Message: message,
+		})
+	}
+	return s.notificationRepo.CreateNotifications(notifications)
+}
+
+func (s *watchService) GetNotifications(userID string, unreadOnly bool) ([]models.Notification, error) {
+	return s.notificationRepo.FindByUserID(userID, unreadOnly, notificationLimit)
+}
+
+func (s *watchService) MarkNotificationRead(userID string, id uint) error {
+	return s.notificationRepo.MarkRead(userID, id, s.now())
+}
+
+func (s *watchService) MarkAllNotificationsRead(userID string) error {
+	return s.notificationRepo.MarkAllRead(userID, s.now())
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"testing"
+	"time"
+	"trello-backend/internal/models"
+	"trello-backend/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWatchRepository struct {
+	mock.Mock
+}
+
+func (m *MockWatchRepository) CreateWatch(watch *models.Watch) error {
+	args := m.Called(watch)
+	return args.Error(0)
+}
+func (m *MockWatchRepository) DeleteWatch(userID string, entityType models.WatchEntityType, entityID uint) error {
+	args := m.Called(userID, entityType, entityID)
+	return args.Error(0)
+}
+func (m *MockWatchRepository) FindWatchedTargets(userID string, targets []repositories.WatchTarget) ([]repositories.WatchTarget, error) {
+	args := m.Called(userID, targets)
+	return args.Get(0).([]repositories.WatchTarget), args.Error(1)
+}
+func (m *MockWatchRepository) FindWatcherIDs(targets []repositories.WatchTarget) ([]string, error) {
+	args := m.Called(targets)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) CreateNotifications(notifications []models.Notification) error {
+	args := m.Called(notifications)
+	return args.Error(0)
+}
+func (m *MockNotificationRepository) FindByUserID(userID string, unreadOnly bool, limit int) ([]models.Notification, error) {
+	args := m.Called(userID, unreadOnly, limit)
+	return args.Get(0).([]models.Notification), args.Error(1)
+}
+func (m *MockNotificationRepository) MarkRead(userID string, id uint, at time.Time) error {
+	args := m.Called(userID, id, at)
+	return args.Error(0)
+}
+func (m *MockNotificationRepository) MarkAllRead(userID string, at time.Time) error {
+	args := m.Called(userID, at)
+	return args.Error(0)
+}
+
+func newTestWatchService() (WatchService, *MockWatchRepository, *MockNotificationRepository, *MockListRepository, *MockCardRepository) {
+	watchRepo := new(MockWatchRepository)
+	notificationRepo := new(MockNotificationRepository)
+	boardRepo := new(MockBoardRepository)
+	listRepo := new(MockListRepository)
+	cardRepo := new(MockCardRepository)
+	boardRepo.On("GetBoardByID", uint(1)).Return(&models.Board{ID: 1, UserID: "user-1"}, nil)
+	boardRepo.On("GetBoardByID", uint(2)).Return(&models.Board{ID: 2, UserID: "user-1", Settings: models.BoardSettings{Visibility: models.BoardPublic}}, nil)
+	return NewWatchService(watchRepo, notificationRepo, boardRepo, listRepo, cardRepo), watchRepo, notificationRepo, listRepo, cardRepo
+}
+
+func TestWatchService_WatchCard(t *testing.T) {
+	service, watchRepo, _, _, cardRepo := newTestWatchService()
+	cardRepo.On("GetCardByID", uint(5)).Return(&models.Card{ID: 5, BoardID: 1}, nil)
+	watchRepo.On("CreateWatch", &models.Watch{UserID: "user-1", EntityType: models.WatchCard, EntityID: 5}).Return(nil)
+
+	err := service.Watch("user-1", models.WatchCard, 5)
+
+	watchRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+
+	// 其他使用者只能關注公開看板
+	err = service.Watch("user-2", models.WatchBoard, 1)
+	assert.ErrorIs(t, err, ErrForbidden)
+	watchRepo.On("CreateWatch", &models.Watch{UserID: "user-2", EntityType: models.WatchBoard, EntityID: 2}).Return(nil)
+	err = service.Watch("user-2", models.WatchBoard, 2)
+	assert.NoError(t, err)
+	err = service.Watch("user-1", models.WatchEntityType("label"), 1)
+	assert.ErrorIs(t, err, ErrInvalidWatchTarget)
+}
+
+func TestWatchService_Unwatch(t *testing.T) {
+	service, watchRepo, _, _, _ := newTestWatchService()
+	watchRepo.On("DeleteWatch", "user-2", models.WatchBoard, uint(1)).Return(nil)
+
+	// 看板改為私人後仍可取消先前的關注
+	err := service.Unwatch("user-2", models.WatchBoard, 1)
+
+	watchRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+	err = service.Unwatch("user-2", models.WatchEntityType("label"), 1)
+	assert.ErrorIs(t, err, ErrInvalidWatchTarget)
+}
+
+func TestWatchService_NotifyCardMoved(t *testing.T) {
+	service, watchRepo, notificationRepo, listRepo, _ := newTestWatchService()
+	listRepo.On("GetListByID", uint(2)).Return(&models.List{ID: 2, Name: "待辦"}, nil)
+	listRepo.On("GetListByID", uint(3)).Return(&models.List{ID: 3, Name: "進行中"}, nil)
+	watchRepo.On("FindWatcherIDs", []repositories.WatchTarget{
+		{Type: models.WatchCard, ID: 5},
+		{Type: models.WatchList, ID: 3},
+		{Type: models.WatchBoard, ID: 2},
+		{Type: models.WatchList, ID: 2},
+	}).Return([]string{"user-1", "user-2"}, nil)
+	// 觸發者 user-1 不會收到通知
+	notificationRepo.On("CreateNotifications", []models.Notification{{
+		UserID: "user-2", ActorID: "user-1", Type: models.NotificationCardMoved,
+		BoardID: 2, CardID: 5, Message: "卡片「修正登入」已從「待辦」移至「進行中」",
+	}}).Return(nil)
+
+	err := service.NotifyCardMoved("user-1", &models.Card{ID: 5, Title: "修正登入", ListID: 3, BoardID: 2}, 2)
+
+	notificationRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+}
+
+func TestWatchService_NotifyCardMoved_SameList(t *testing.T) {
+	service, watchRepo, notificationRepo, _, _ := newTestWatchService()
+
+	err := service.NotifyCardMoved("user-1", &models.Card{ID: 5, ListID: 3, BoardID: 1}, 3)
+
+	assert.NoError(t, err)
+	watchRepo.AssertNotCalled(t, "FindWatcherIDs", mock.Anything)
+	notificationRepo.AssertNotCalled(t, "CreateNotifications", mock.Anything)
+}
+
+func TestWatchService_NotifyDueDateChanged(t *testing.T) {
+	service, watchRepo, notificationRepo, _, _ := newTestWatchService()
+	due := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	card := &models.Card{ID: 5, Title: "發布", ListID: 3, BoardID: 2, DueAt: &due}
+
+	// 到期日未變更時不通知
+	err := service.NotifyDueDateChanged("user-1", card, &due)
+	assert.NoError(t, err)
+	watchRepo.AssertNotCalled(t, "FindWatcherIDs", mock.Anything)
+
+	watchRepo.On("FindWatcherIDs", mock.Anything).Return([]string{"user-2"}, nil)
+	notificationRepo.On("CreateNotifications", mock.MatchedBy(func(n []models.Notification) bool {
+		return len(n) == 1 && n[0].Type == models.NotificationDueDateChanged &&
+			n[0].Message == "卡片「發布」的到期日已變更為 2025-03-01 09:30"
+	})).Return(nil)
+
+	err = service.NotifyDueDateChanged("user-1", card, nil)
+
+	notificationRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+}
+
+func TestWatchService_NotifyPrivateBoard(t *testing.T) {
+	service, watchRepo, notificationRepo, _, _ := newTestWatchService()
+	due := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	watchRepo.On("FindWatcherIDs", mock.Anything).Return([]string{"user-1", "user-2"}, nil)
+	// 看板為私人時，user-2 先前的關注不再收到通知
+	notificationRepo.On("CreateNotifications", []models.Notification{}).Return(nil)
+
+	err := service.NotifyDueDateChanged("user-1", &models.Card{ID: 5, Title: "發布", ListID: 3, BoardID: 1, DueAt: &due}, nil)
+
+	notificationRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+}
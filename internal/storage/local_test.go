@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStore_PutGetDelete(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	err = store.Put(ctx, "cards/1/a.txt", strings.NewReader("hello"), 5, "text/plain")
+	require.NoError(t, err)
+
+	rc, err := store.Get(ctx, "cards/1/a.txt")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	assert.Equal(t, "hello", string(data))
+
+	assert.NoError(t, store.Delete(ctx, "cards/1/a.txt"))
+	_, err = store.Get(ctx, "cards/1/a.txt")
+	assert.ErrorIs(t, err, ErrNotFound)
+	// 重複刪除不應回傳錯誤
+	assert.NoError(t, store.Delete(ctx, "cards/1/a.txt"))
+}
+
+func TestLocalBlobStore_RejectsTraversal(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	err = store.Put(context.Background(), "../escape.txt", strings.NewReader("x"), 1, "")
+	assert.Error(t, err)
+}
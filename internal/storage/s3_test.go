@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 模擬 MinIO 等 S3 相容服務的最小行為：path-style 的 PUT/GET/DELETE
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/") || r.Header.Get("x-amz-date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = data
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newTestS3Store(t *testing.T) (*S3BlobStore, *fakeS3) {
+	fake := newFakeS3()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	store, err := NewS3BlobStore(S3Config{
+		Endpoint:  srv.URL,
+		Bucket:    "attachments",
+		AccessKey: "access",
+		SecretKey: "secret",
+	}, srv.Client())
+	require.NoError(t, err)
+	return store, fake
+}
+
+func TestS3BlobStore_PutGetDelete(t *testing.T) {
+	store, fake := newTestS3Store(t)
+	ctx := context.Background()
+
+	err := store.Put(ctx, "cards/1/report.pdf", strings.NewReader("pdf-data"), 8, "application/pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", fake.types["/attachments/cards/1/report.pdf"])
+
+	rc, err := store.Get(ctx, "cards/1/report.pdf")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	assert.Equal(t, "pdf-data", string(data))
+
+	require.NoError(t, store.Delete(ctx, "cards/1/report.pdf"))
+	_, err = store.Get(ctx, "cards/1/report.pdf")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestS3BlobStore_RequiresBucket(t *testing.T) {
+	_, err := NewS3BlobStore(S3Config{Endpoint: "http://localhost:9000"}, nil)
+	assert.Error(t, err)
+}
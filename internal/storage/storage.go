@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound 表示指定的物件不存在
+var ErrNotFound = errors.New("檔案不存在")
+
+// BlobStore 定義附件檔案的儲存介面，可替換為本機或 S3 相容的實作
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
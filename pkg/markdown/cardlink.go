@@ -0,0 +1,58 @@
+package markdown
+
+import (
+	"strconv"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// CardLinkClass 卡片內部連結的 class，供前端攔截點擊改為站內導覽
+const CardLinkClass = "card-link"
+
+// CardURL 回傳卡片內部連結指向的路徑
+func CardURL(id uint64) string {
+	return "/cards/" + strconv.FormatUint(id, 10)
+}
+
+// 卡片 ID 最多的位數，避免把過長的數字當成連結
+const maxCardIDDigits = 10
+
+// cardLinkParser 將 #123 這類寫法轉為指向卡片 123 的連結
+type cardLinkParser struct{}
+
+func (p *cardLinkParser) Trigger() []byte {
+	return []byte{'#'}
+}
+
+func (p *cardLinkParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	// 前一個字元是文字、數字或 &（HTML entity，如 &#123;）時不視為卡片連結
+	if prev := block.PrecendingCharacter(); prev == '&' || unicode.IsLetter(prev) || unicode.IsDigit(prev) {
+		return nil
+	}
+	line, segment := block.PeekLine()
+	n := 1
+	for n < len(line) && line[n] >= '0' && line[n] <= '9' {
+		n++
+	}
+	if digits := n - 1; digits == 0 || digits > maxCardIDDigits {
+		return nil
+	}
+	if n < len(line) {
+		if next := rune(line[n]); next == '_' || unicode.IsLetter(next) || unicode.IsDigit(next) {
+			return nil
+		}
+	}
+	id, err := strconv.ParseUint(string(line[1:n]), 10, 64)
+	if err != nil || id == 0 {
+		return nil
+	}
+	link := ast.NewLink()
+	link.Destination = []byte(CardURL(id))
+	link.SetAttributeString("class", []byte(CardLinkClass))
+	link.AppendChild(link, ast.NewTextSegment(text.NewSegment(segment.Start, segment.Start+n)))
+	block.Advance(n)
+	return link
+}
@@ -0,0 +1,65 @@
+// Package markdown 將卡片內容的 Markdown 轉為經過消毒、可安全顯示的 HTML
+package markdown
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"regexp"
+
+	"github.com/hashicorp/golang-lru/v2"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// 快取的渲染結果數量上限
+const defaultCacheSize = 1024
+
+// Renderer 支援 GFM（表格、待辦清單、刪除線、自動連結）與卡片內部連結，
+// 輸出一律經過 HTML 消毒。渲染結果以內容的 SHA-256 為 key 快取。
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+	cache  *lru.Cache[[sha256.Size]byte, string]
+}
+
+func NewRenderer() *Renderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(
+			parser.WithInlineParsers(util.Prioritized(&cardLinkParser{}, 500)),
+		),
+		// 允許原始 HTML，交由 bluemonday 統一消毒
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+	cache, _ := lru.New[[sha256.Size]byte, string](defaultCacheSize)
+	return &Renderer{md: md, policy: newPolicy(), cache: cache}
+}
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// 待辦清單的核取方塊
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	// 卡片內部連結
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^` + CardLinkClass + `$`)).OnElements("a")
+	return p
+}
+
+// Render 將 Markdown 轉為安全的 HTML
+func (r *Renderer) Render(source string) (string, error) {
+	key := sha256.Sum256([]byte(source))
+	if html, ok := r.cache.Get(key); ok {
+		return html, nil
+	}
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	html := r.policy.Sanitize(buf.String())
+	r.cache.Add(key, html)
+	return html, nil
+}
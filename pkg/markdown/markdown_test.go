@@ -0,0 +1,69 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func render(t *testing.T, source string) string {
+	html, err := NewRenderer().Render(source)
+	require.NoError(t, err)
+	return html
+}
+
+func TestRender_GFM(t *testing.T) {
+	html := render(t, "| a | b |\n|---|---|\n| 1 | 2 |\n\n- [x] done\n- [ ] todo\n\n~~old~~")
+
+	assert.Contains(t, html, "<table>")
+	assert.Contains(t, html, "<td>1</td>")
+	assert.Contains(t, html, `<input checked="" disabled="" type="checkbox"`)
+	assert.Contains(t, html, `<input disabled="" type="checkbox"`)
+	assert.Contains(t, html, "<del>old</del>")
+}
+
+func TestRender_Sanitize(t *testing.T) {
+	html := render(t, "<script>alert(1)</script>\n\n[x](javascript:alert(1)) <img src=x onerror=alert(1)> <input type=\"text\">")
+
+	assert.NotContains(t, html, "<script")
+	assert.NotContains(t, html, "javascript:")
+	assert.NotContains(t, html, "onerror")
+	assert.NotContains(t, html, `type="text"`)
+}
+
+func TestRender_CardLinks(t *testing.T) {
+	html := render(t, "see #12, and (#7)")
+
+	assert.Contains(t, html, `<a href="/cards/12" class="card-link"`)
+	assert.Contains(t, html, `>#12</a>`)
+	assert.Contains(t, html, `<a href="/cards/7" class="card-link"`)
+}
+
+func TestRender_CardLinks_Ignored(t *testing.T) {
+	cases := []string{
+		"issue#12",
+		"#12abc",
+		"&#123;",
+		"`#12`",
+		"#12345678901",
+		"#0",
+	}
+	for _, source := range cases {
+		html := render(t, source)
+		assert.False(t, strings.Contains(html, "card-link"), "source %q rendered %q", source, html)
+	}
+}
+
+func TestRender_Cache(t *testing.T) {
+	r := NewRenderer()
+	first, err := r.Render("**bold**")
+	require.NoError(t, err)
+	assert.Equal(t, 1, r.cache.Len())
+
+	second, err := r.Render("**bold**")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, r.cache.Len())
+}
@@ -0,0 +1,307 @@
+// Package rrule 實作 RFC 5545 RRULE 的子集，用於週期性卡片。
+//
+// 支援的屬性：FREQ（DAILY、WEEKLY、MONTHLY、YEARLY）、INTERVAL、
+// BYDAY（僅 WEEKLY，不含序數，例如 MO,WE,FR）、BYMONTHDAY（僅 MONTHLY，1~31 或 -1 表示月底）、
+// COUNT 與 UNTIL（與 COUNT 擇一）。週的起始日固定為週一。
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("無效的重複規則")
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods 尋找下一次發生時最多檢查的週期數，避免規則永遠不會發生時無限迴圈
+const maxPeriods = 100_000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule 解析後的重複規則
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay int // 0 表示未指定
+	Count      int // 0 表示不限次數
+	Until      *time.Time
+}
+
+// Parse 解析 RRULE 字串，可包含或省略 "RRULE:" 前綴
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, ErrInvalidRule
+	}
+	rule := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || value == "" || seen[key] {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+		seen[key] = true
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(value)
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly && rule.Freq != Yearly {
+				err = fmt.Errorf("不支援的 FREQ %s", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = positiveInt(value)
+		case "COUNT":
+			rule.Count, err = positiveInt(value)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = strconv.Atoi(value)
+			if err == nil && (rule.ByMonthDay == 0 || rule.ByMonthDay < -1 || rule.ByMonthDay > 31) {
+				err = errors.New("BYMONTHDAY 需介於 1~31 或為 -1")
+			}
+		default:
+			err = fmt.Errorf("不支援的屬性 %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	}
+	switch {
+	case rule.Freq == "":
+		return nil, fmt.Errorf("%w: 缺少 FREQ", ErrInvalidRule)
+	case rule.Count > 0 && rule.Until != nil:
+		return nil, fmt.Errorf("%w: COUNT 與 UNTIL 不可同時使用", ErrInvalidRule)
+	case len(rule.ByDay) > 0 && rule.Freq != Weekly:
+		return nil, fmt.Errorf("%w: BYDAY 僅支援 WEEKLY", ErrInvalidRule)
+	case rule.ByMonthDay != 0 && rule.Freq != Monthly:
+		return nil, fmt.Errorf("%w: BYMONTHDAY 僅支援 MONTHLY", ErrInvalidRule)
+	}
+	return rule, nil
+}
+
+func positiveInt(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s 不是正整數", s)
+	}
+	return n, nil
+}
+
+func parseUntil(s string) (*time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			if layout == "20060102" {
+				// 只有日期時包含當天整天
+				t = t.Add(24*time.Hour - time.Nanosecond)
+			}
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("無法解析 UNTIL %s", s)
+}
+
+func parseByDay(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	seen := make(map[time.Weekday]bool)
+	for _, d := range strings.Split(s, ",") {
+		wd, ok := weekdays[strings.TrimSpace(d)]
+		if !ok {
+			return nil, fmt.Errorf("無效的 BYDAY %s", d)
+		}
+		if !seen[wd] {
+			seen[wd] = true
+			days = append(days, wd)
+		}
+	}
+	// 依週一為起始的順序排列
+	sort.Slice(days, func(i, j int) bool { return mondayOffset(days[i]) < mondayOffset(days[j]) })
+	return days, nil
+}
+
+func mondayOffset(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+// String 輸出正規化後的 RRULE（不含前綴）
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		names := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			names[i] = strings.ToUpper(d.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(names, ","))
+	}
+	if r.ByMonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next 回傳 dtstart 起算、嚴格晚於 after 的下一次發生時間；規則已結束時 ok 為 false。
+// 發生時間沿用 dtstart 的時區與時刻
+func (r *Rule) Next(dtstart, after time.Time) (next time.Time, ok bool) {
+	return r.Iter(dtstart, after).Next()
+}
+
+// Iterator 依序產生規則的發生時間，每次從上一次的位置繼續尋找
+type Iterator struct {
+	rule    *Rule
+	dtstart time.Time
+	after   time.Time
+	period  int // 下一個要展開的週期
+	end     int
+	pending []time.Time
+	count   int // 已經過的發生次數，用於 COUNT
+	done    bool
+}
+
+// Iter 回傳產生 dtstart 起算、嚴格晚於 after 的發生時間的迭代器。
+// 未設定 COUNT 時不必計算之前的發生次數，直接從 after 附近的週期開始尋找
+func (r *Rule) Iter(dtstart, after time.Time) *Iterator {
+	start := 0
+	if r.Count == 0 {
+		start = r.skipPeriods(dtstart, after)
+	}
+	return &Iterator{rule: r, dtstart: dtstart, after: after, period: start, end: start + maxPeriods}
+}
+
+// Next 回傳下一次發生時間；規則已結束時 ok 為 false
+func (it *Iterator) Next() (next time.Time, ok bool) {
+	for !it.done {
+		if len(it.pending) == 0 {
+			if it.period >= it.end {
+				it.done = true
+				break
+			}
+			it.pending = it.rule.period(it.dtstart, it.period)
+			it.period++
+			continue
+		}
+		t := it.pending[0]
+		it.pending = it.pending[1:]
+		if t.Before(it.dtstart) {
+			continue
+		}
+		if it.rule.Until != nil && t.After(*it.rule.Until) {
+			it.done = true
+			break
+		}
+		it.count++
+		if it.rule.Count > 0 && it.count > it.rule.Count {
+			it.done = true
+			break
+		}
+		if t.After(it.after) {
+			it.after = t
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// skipPeriods 估計 after 之前可以略過的週期數，這些週期中的發生時間都不晚於 after；
+// 保留一個週期的餘裕，日光節約時間造成的偏移不影響結果
+func (r *Rule) skipPeriods(dtstart, after time.Time) int {
+	if !after.After(dtstart) {
+		return 0
+	}
+	after = after.In(dtstart.Location())
+	var elapsed int // 經過的天、週、月或年數
+	switch r.Freq {
+	case Daily:
+		elapsed = int(after.Sub(dtstart).Hours() / 24)
+	case Weekly:
+		elapsed = int(after.Sub(dtstart).Hours() / (24 * 7))
+	case Monthly:
+		elapsed = (after.Year()-dtstart.Year())*12 + int(after.Month()-dtstart.Month())
+	case Yearly:
+		elapsed = after.Year() - dtstart.Year()
+	}
+	return max(elapsed/r.Interval-1, 0)
+}
+
+// period 回傳第 k 個週期中依時間排序的候選時間
+func (r *Rule) period(dtstart time.Time, k int) []time.Time {
+	n := k * r.Interval
+	y, m, d := dtstart.Date()
+	hh, mm, ss := dtstart.Clock()
+	loc := dtstart.Location()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hh, mm, ss, dtstart.Nanosecond(), loc)
+	}
+	switch r.Freq {
+	case Daily:
+		return []time.Time{at(y, m, d+n)}
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			return []time.Time{at(y, m, d+7*n)}
+		}
+		weekStart := d - mondayOffset(dtstart.Weekday()) + 7*n
+		times := make([]time.Time, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			times[i] = at(y, m, weekStart+mondayOffset(wd))
+		}
+		return times
+	case Monthly:
+		month := time.Month(int(m) + n)
+		day := d
+		if r.ByMonthDay != 0 {
+			day = r.ByMonthDay
+		}
+		last := daysIn(y, month)
+		if day == -1 {
+			day = last
+		}
+		if day > last {
+			// 該月沒有這一天，依 RFC 5545 略過
+			return nil
+		}
+		return []time.Time{at(y, month, day)}
+	case Yearly:
+		if m == time.February && d == 29 && daysIn(y+n, m) < 29 {
+			return nil
+		}
+		return []time.Time{at(y+n, m, d)}
+	}
+	return nil
+}
+
+// daysIn 回傳指定月份的天數，month 可超出 1~12，會自動進位
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
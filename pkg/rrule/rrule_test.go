@@ -0,0 +1,149 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, s string) *Rule {
+	t.Helper()
+	r, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q) 失敗: %v", s, err)
+	}
+	return r
+}
+
+func date(y int, m time.Month, d, hh, mm int) time.Time {
+	return time.Date(y, m, d, hh, mm, 0, 0, time.UTC)
+}
+
+// occurrences 從 dtstart 起依序列出最多 n 次發生時間
+func occurrences(r *Rule, dtstart time.Time, n int) []time.Time {
+	var result []time.Time
+	after := dtstart.Add(-time.Nanosecond)
+	for len(result) < n {
+		next, ok := r.Next(dtstart, after)
+		if !ok {
+			break
+		}
+		result = append(result, next)
+		after = next
+	}
+	return result
+}
+
+func assertTimes(t *testing.T, got []time.Time, want ...time.Time) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("得到 %d 次發生 %v，預期 %d 次 %v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("第 %d 次為 %v，預期 %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=3;UNTIL=20250101",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYHOUR=9",
+	} {
+		if _, err := Parse(s); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("Parse(%q) 應回傳 ErrInvalidRule，得到 %v", s, err)
+		}
+	}
+}
+
+func TestParse_String(t *testing.T) {
+	r := mustParse(t, "RRULE:freq=weekly;byday=FR,MO;interval=2;until=20250301")
+	want := "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;UNTIL=20250301T235959Z"
+	if got := r.String(); got != want {
+		t.Errorf("String() = %q，預期 %q", got, want)
+	}
+}
+
+func TestNext_WeeklyByDay(t *testing.T) {
+	// 2025-01-01 為週三
+	r := mustParse(t, "FREQ=WEEKLY;BYDAY=MO,FR")
+	got := occurrences(r, date(2025, 1, 1, 9, 0), 4)
+	assertTimes(t, got, date(2025, 1, 3, 9, 0), date(2025, 1, 6, 9, 0), date(2025, 1, 10, 9, 0), date(2025, 1, 13, 9, 0))
+}
+
+func TestNext_WeeklyInterval(t *testing.T) {
+	r := mustParse(t, "FREQ=WEEKLY;INTERVAL=2;COUNT=3")
+	got := occurrences(r, date(2025, 1, 6, 10, 0), 10)
+	assertTimes(t, got, date(2025, 1, 6, 10, 0), date(2025, 1, 20, 10, 0), date(2025, 2, 3, 10, 0))
+}
+
+func TestNext_MonthlyLastDay(t *testing.T) {
+	r := mustParse(t, "FREQ=MONTHLY;BYMONTHDAY=-1")
+	got := occurrences(r, date(2025, 1, 15, 17, 0), 3)
+	assertTimes(t, got, date(2025, 1, 31, 17, 0), date(2025, 2, 28, 17, 0), date(2025, 3, 31, 17, 0))
+}
+
+func TestNext_MonthlySkipsShortMonths(t *testing.T) {
+	r := mustParse(t, "FREQ=MONTHLY")
+	got := occurrences(r, date(2025, 1, 31, 9, 0), 3)
+	assertTimes(t, got, date(2025, 1, 31, 9, 0), date(2025, 3, 31, 9, 0), date(2025, 5, 31, 9, 0))
+}
+
+func TestNext_Until(t *testing.T) {
+	r := mustParse(t, "FREQ=DAILY;UNTIL=20250103")
+	got := occurrences(r, date(2025, 1, 1, 8, 0), 10)
+	assertTimes(t, got, date(2025, 1, 1, 8, 0), date(2025, 1, 2, 8, 0), date(2025, 1, 3, 8, 0))
+}
+
+func TestNext_AfterSkipsPast(t *testing.T) {
+	r := mustParse(t, "FREQ=YEARLY")
+	next, ok := r.Next(date(2020, 2, 29, 0, 0), date(2021, 1, 1, 0, 0))
+	if !ok || !next.Equal(date(2024, 2, 29, 0, 0)) {
+		t.Errorf("Next = %v, %v，預期 2024-02-29", next, ok)
+	}
+}
+
+func TestNext_FarAfterStart(t *testing.T) {
+	// 距離 dtstart 超過 maxPeriods 個週期時仍能找到下一次發生
+	r := mustParse(t, "FREQ=DAILY;INTERVAL=1")
+	next, ok := r.Next(date(1700, 1, 1, 9, 0), date(2025, 3, 1, 12, 0))
+	if !ok || !next.Equal(date(2025, 3, 2, 9, 0)) {
+		t.Errorf("Next = %v, %v，預期 2025-03-02 09:00", next, ok)
+	}
+}
+
+func TestIter_MatchesNext(t *testing.T) {
+	dtstart := time.Date(2024, 1, 31, 9, 0, 0, 0, time.FixedZone("UTC+8", 8*60*60))
+	for _, rule := range []string{
+		"FREQ=DAILY;INTERVAL=3",
+		"FREQ=WEEKLY;BYDAY=MO,WE,SU",
+		"FREQ=WEEKLY;INTERVAL=2;COUNT=5",
+		"FREQ=MONTHLY",
+		"FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=-1",
+		"FREQ=YEARLY;UNTIL=20300101",
+	} {
+		r := mustParse(t, rule)
+		after := date(2024, 6, 15, 0, 0)
+		it := r.Iter(dtstart, after)
+		for i := 0; i < 8; i++ {
+			got, gotOK := it.Next()
+			want, wantOK := r.Next(dtstart, after)
+			if gotOK != wantOK || !got.Equal(want) {
+				t.Fatalf("%s 第 %d 次：Iter 得到 %v, %v，Next 得到 %v, %v", rule, i, got, gotOK, want, wantOK)
+			}
+			if !wantOK {
+				break
+			}
+			after = want
+		}
+	}
+}
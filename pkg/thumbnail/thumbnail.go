@@ -0,0 +1,101 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // 註冊 GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// MaxPixels 解碼前檢查的像素上限，避免惡意的超大圖片耗盡記憶體
+const MaxPixels = 40_000_000
+
+var ErrTooLarge = errors.New("圖片尺寸過大")
+
+// Supported 回傳是否能為該 content type 產生縮圖
+func Supported(contentType string) bool {
+	switch contentType {
+	case "image/png", "image/jpeg", "image/gif":
+		return true
+	}
+	return false
+}
+
+// Generate 將圖片等比例縮小至 maxSize x maxSize 以內，回傳編碼後的內容與 content type。
+// JPEG 輸出為 JPEG，PNG 與 GIF（取第一格）輸出為 PNG 以保留透明度。
+func Generate(r io.Reader, maxSize int) ([]byte, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, "", ErrTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	dst := Resize(src, maxSize)
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+		return buf.Bytes(), "image/jpeg", err
+	}
+	err = png.Encode(&buf, dst)
+	return buf.Bytes(), "image/png", err
+}
+
+// Resize 以區域平均（box filter）等比例縮小圖片；原圖已小於 maxSize 時只轉成 RGBA
+func Resize(src image.Image, maxSize int) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > maxSize || h > maxSize {
+		if w >= h {
+			tw, th = maxSize, max(1, h*maxSize/w)
+		} else {
+			tw, th = max(1, w*maxSize/h), maxSize
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	if tw == w && th == h {
+		draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+		return dst
+	}
+	for y := 0; y < th; y++ {
+		y0 := b.Min.Y + y*h/th
+		y1 := max(y0+1, b.Min.Y+(y+1)*h/th)
+		for x := 0; x < tw; x++ {
+			x0 := b.Min.X + x*w/tw
+			x1 := max(x0+1, b.Min.X+(x+1)*w/tw)
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
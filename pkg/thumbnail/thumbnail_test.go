@@ -0,0 +1,73 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func solidImage(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestResize_KeepsAspectRatio(t *testing.T) {
+	dst := Resize(solidImage(400, 200, color.White), 100)
+
+	assert.Equal(t, 100, dst.Bounds().Dx())
+	assert.Equal(t, 50, dst.Bounds().Dy())
+	assert.Equal(t, color.RGBA{255, 255, 255, 255}, dst.RGBAAt(10, 10))
+}
+
+func TestResize_SmallImageUnchanged(t *testing.T) {
+	dst := Resize(solidImage(20, 10, color.Black), 100)
+
+	assert.Equal(t, image.Rect(0, 0, 20, 10), dst.Bounds())
+}
+
+func TestGenerate_Formats(t *testing.T) {
+	src := solidImage(300, 600, color.RGBA{200, 0, 0, 255})
+
+	var pngBuf, jpegBuf, gifBuf bytes.Buffer
+	require.NoError(t, png.Encode(&pngBuf, src))
+	require.NoError(t, jpeg.Encode(&jpegBuf, src, nil))
+	require.NoError(t, gif.Encode(&gifBuf, src, nil))
+
+	cases := []struct {
+		name        string
+		data        []byte
+		contentType string
+	}{
+		{"png", pngBuf.Bytes(), "image/png"},
+		{"jpeg", jpegBuf.Bytes(), "image/jpeg"},
+		{"gif", gifBuf.Bytes(), "image/png"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, contentType, err := Generate(bytes.NewReader(tc.data), 128)
+			require.NoError(t, err)
+			assert.Equal(t, tc.contentType, contentType)
+			cfg, _, err := image.DecodeConfig(bytes.NewReader(out))
+			require.NoError(t, err)
+			assert.Equal(t, 64, cfg.Width)
+			assert.Equal(t, 128, cfg.Height)
+		})
+	}
+}
+
+func TestGenerate_InvalidImage(t *testing.T) {
+	_, _, err := Generate(bytes.NewReader([]byte("not an image")), 128)
+	assert.Error(t, err)
+}